@@ -0,0 +1,22 @@
+package logfilter
+
+// WithAllowListMode inverts the handler's default for targeted tracing: when
+// enabled, only records matched by an active filter are emitted, and every
+// other record is suppressed whatever its level, the global level or a
+// ContextWithLevel override. Filters become an allow-list; the global level
+// no longer lets anything through on its own.
+//
+// A matched record is still decided by the filter's Level, and emitted at its
+// OutputLevel if it has one, so a filter with Level "debug" and OutputLevel
+// "info" lets that debug traffic through as info, and one with Level "warn"
+// lets only warnings and errors through. WithDefaultOutputLevel applies to
+// matched records below the global level, as usual. Global suppressions,
+// sticky matches (see WithStickyMatches) and baseline sampling still apply,
+// so sampling, if configured, still lets a fraction of unmatched records
+// through. Records released by capture-on-error or FlushSuppressed were
+// suppressed, so they are emitted as usual when flushed.
+func WithAllowListMode(enabled bool) Option {
+	return func(o *options) {
+		o.allowList = enabled
+	}
+}
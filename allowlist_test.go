@@ -0,0 +1,91 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_AllowListMode(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, WithAllowListMode(true))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", OutputLevel: "info", Enabled: true},
+		{Type: "component", Pattern: "db", Level: "warn", Enabled: true},
+		{Type: "job_id", Pattern: "job_7", Level: "debug"}, // Disabled
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		log   func()
+		want  string
+		emits bool
+	}{
+		{"unmatched info suppressed", func() { logger.Info("unmatched info") }, "unmatched info", false},
+		{"unmatched error suppressed", func() { logger.Error("unmatched error") }, "unmatched error", false},
+		{"matched debug emitted", func() { logger.Debug("traced", "job_id", "job_42") }, "level=INFO msg=traced", true},
+		{"matched below filter level", func() { logger.Info("db info", "component", "db") }, "db info", false},
+		{"matched at filter level", func() { logger.Warn("db warn", "component", "db") }, "db warn", true},
+		{"disabled filter doesn't allow", func() { logger.Info("other job", "job_id", "job_7") }, "other job", false},
+		{"context level doesn't allow", func() {
+			logger.InfoContext(ContextWithLevel(context.Background(), slog.LevelDebug), "ctx info")
+		}, "ctx info", false},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		tt.log()
+		if got := strings.Contains(buf.String(), tt.want); got != tt.emits {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.emits, buf.String())
+		}
+	}
+}
+
+func TestHandler_AllowListMode_NoFilters(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithAllowListMode(true))
+	slog.New(handler).Error("nothing allowed")
+	if buf.Len() > 0 {
+		t.Errorf("Expected nothing emitted without filters, got: %s", buf.String())
+	}
+}
+
+func TestHandler_AllowListMode_Off(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithAllowListMode(false))
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_42", Level: "debug", Enabled: true}})
+	slog.New(handler).Info("unmatched info")
+	if !strings.Contains(buf.String(), "unmatched info") {
+		t.Errorf("Expected the global level to apply with allow-list mode off, got: %s", buf.String())
+	}
+}
+
+func TestHandler_AllowListMode_Explain(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithAllowListMode(true))
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_42", Level: "debug", Enabled: true}})
+
+	r := slog.NewRecord(handler.now(), slog.LevelWarn, "warn", 0)
+	if d := handler.Explain(context.Background(), r); d.Emit || d.Filter != nil {
+		t.Errorf("Expected an unmatched warning to be suppressed, got %v", d)
+	}
+	r.AddAttrs(slog.String("job_id", "job_42"))
+	if d := handler.Explain(context.Background(), r); !d.Emit || d.Filter == nil {
+		t.Errorf("Expected a matched warning to be emitted, got %v", d)
+	}
+}
@@ -0,0 +1,34 @@
+package logfilter
+
+import "log/slog"
+
+// WithAnnotateMatches adds an attribute with the given key to every record
+// emitted through a matching filter, naming the filter, e.g.
+// matched_filter=debug-checkout, for tracing why a record was logged. The
+// value is the filter's Name, or if it has none its "type:pattern", or for a
+// compound filter without a Type its FilterID. Records no filter matched,
+// including those emitted by baseline sampling, aren't annotated. Like the
+// record's own attributes, it is qualified by the logger's groups, if any.
+func WithAnnotateMatches(attrKey string) Option {
+	return func(o *options) {
+		o.annotateKey = attrKey
+	}
+}
+
+// annotation returns the value WithAnnotateMatches records for f.
+func (f *LogFilter) annotation() string {
+	switch {
+	case f.Name != "":
+		return f.Name
+	case f.Type != "":
+		return f.Type + ":" + f.Pattern
+	default:
+		return f.FilterID()
+	}
+}
+
+// annotate adds the WithAnnotateMatches attribute naming f to r, which must
+// not share its attributes with the caller's record.
+func (h *Handler) annotate(r *slog.Record, f *LogFilter) {
+	r.AddAttrs(slog.String(h.annotateKey, f.annotation()))
+}
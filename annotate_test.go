@@ -0,0 +1,88 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_AnnotateMatches(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithAnnotateMatches("matched_filter"))
+	handler.SetFilters([]LogFilter{
+		{Name: "checkout-debug", Type: "job_id", Pattern: "checkout_*", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "billing_*", Level: "debug", OutputLevel: "info", Enabled: true},
+		{Conditions: []Condition{{Type: "service", Pattern: "auth"}}, Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name string
+		log  func()
+		want string // Expected annotation, or "" for none
+	}{
+		{"named filter", func() { logger.Debug("step", "job_id", "checkout_1") }, "matched_filter=checkout-debug"},
+		{"unnamed filter", func() { logger.Debug("step", "job_id", "billing_1") }, "matched_filter=job_id:billing_*"},
+		{"compound filter", func() { logger.Debug("step", "service", "auth") }, "matched_filter=f-"},
+		{"matched above global level", func() { logger.Warn("step", "job_id", "checkout_1") }, "matched_filter=checkout-debug"},
+		{"unmatched", func() { logger.Info("step", "job_id", "other") }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			out := buf.String()
+			if out == "" {
+				t.Fatal("Expected the record to be emitted")
+			}
+			if tt.want == "" {
+				if strings.Contains(out, "matched_filter") {
+					t.Errorf("Expected no annotation on unmatched record, got: %s", out)
+				}
+				return
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Expected %q, got: %s", tt.want, out)
+			}
+		})
+	}
+
+	// The transformed level and the annotation combine
+	buf.Reset()
+	logger.Debug("step", "job_id", "billing_2")
+	if out := buf.String(); !strings.Contains(out, "level=INFO") || strings.Count(out, "matched_filter=") != 1 {
+		t.Errorf("Expected one annotation on the transformed record, got: %s", out)
+	}
+}
+
+func TestHandler_AnnotateMatches_CallerRecordUnchanged(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithAnnotateMatches("matched_filter"))
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true, Confirmed: true}})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "step", 0)
+	r.AddAttrs(slog.String("job_id", "job_1"))
+	for range 2 {
+		if err := handler.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.NumAttrs() != 1 {
+		t.Errorf("Expected the caller's record unchanged, got %d attrs", r.NumAttrs())
+	}
+	if got := strings.Count(buf.String(), "matched_filter="); got != 2 {
+		t.Errorf("Expected one annotation per record, got %d: %s", got, buf.String())
+	}
+}
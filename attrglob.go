@@ -0,0 +1,73 @@
+package logfilter
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// isKeyGlob reports whether an attribute filter's Type is a glob over
+// attribute keys, such as "*_id", rather than a single key.
+func isKeyGlob(key string) bool {
+	return strings.ContainsAny(key, "*?[")
+}
+
+// globAttrValues returns the values, as strings, of the attributes whose keys
+// f's key glob matches, from the sources its MatchScope allows: the record's
+// first, then the logger's. Slice values contribute their elements. Under
+// WithGroup, record keys also match by their group-qualified form.
+func (v *recordView) globAttrValues(f *LogFilter) []string {
+	var values []string
+	add := func(val slog.Value) {
+		if elems, ok := v.h.matchElements(val); ok {
+			values = append(values, elems...)
+			return
+		}
+		values = append(values, v.h.matchString(val))
+	}
+
+	h := v.h
+	keys := *f.keyGlob
+	if f.MatchScope != MatchScopePreformatted {
+		v.r.Attrs(func(a slog.Attr) bool {
+			if keys.Match(a.Key) || (h.groupPrefix != "" && keys.Match(h.groupPrefix+a.Key)) {
+				add(a.Value)
+			}
+			return true
+		})
+	}
+	if f.MatchScope != MatchScopeRecord {
+		for _, a := range h.groupedAttrs {
+			if keys.Match(a.Key) {
+				add(a.Value)
+			}
+		}
+		for _, a := range h.preformattedAttrs {
+			if keys.Match(a.Key) {
+				add(a.Value)
+			}
+		}
+	}
+	return values
+}
+
+// matchGlobAttr reports whether the value of any attribute whose key f's key
+// glob matches matches f's pattern, element-wise as for a slice attribute
+// (see matchElems), returning the matched value. With Negate, it matches if
+// there are such attributes and none of their values does.
+func (v *recordView) matchGlobAttr(f *LogFilter) (string, bool) {
+	values := v.globAttrValues(f)
+	if len(values) == 0 {
+		return "", false
+	}
+	return f.matchElems(f.matcher, values, strings.Join(values, ","))
+}
+
+// lookupGlobAttr returns the value of the first attribute whose key f's key
+// glob matches.
+func (v *recordView) lookupGlobAttr(f *LogFilter) (string, bool) {
+	values := v.globAttrValues(f)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
@@ -0,0 +1,179 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_WildcardAttributeKey(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "*_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		attrs []any
+		want  bool
+	}{
+		{"order_id", []any{"order_id", "debug_1"}, true},
+		{"shipment_id", []any{"shipment_id", "debug_2"}, true},
+		{"invoice_id", []any{"invoice_id", "debug_3"}, true},
+		{"second id matches", []any{"order_id", "o-1", "invoice_id", "debug_4"}, true},
+		{"no value matches", []any{"order_id", "o-1", "shipment_id", "s-1"}, false},
+		{"key doesn't match", []any{"order", "debug_5"}, false},
+		{"slice element", []any{"batch_id", []string{"b-1", "debug_6"}}, true},
+		{"no attributes", nil, false},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		logger.Debug("step", tt.attrs...)
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.want, buf.String())
+		}
+	}
+}
+
+func TestHandler_WildcardAttributeKey_LoggerAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "*_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+		{Type: "http.*_id", Pattern: "req_*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.With("order_id", "debug_1").Debug("from logger attrs")
+	logger.WithGroup("http").Debug("grouped", "request_id", "req_1")
+	logger.With("order_id", "o-1").Debug("no match")
+
+	out := buf.String()
+	for _, want := range []string{"from logger attrs", "grouped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q to be emitted, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "no match") {
+		t.Errorf("Expected unmatched record to be suppressed, got: %s", out)
+	}
+}
+
+func TestHandler_WildcardAttributeKey_Modes(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter LogFilter
+		logger func(*slog.Logger) *slog.Logger
+		attrs  []any
+		want   bool
+	}{
+		{
+			"negate with no matching value",
+			LogFilter{Type: "*_id", Pattern: "debug_*", Negate: true},
+			nil,
+			[]any{"order_id", "o-1", "invoice_id", "i-1"},
+			true,
+		},
+		{
+			"negate with a matching value",
+			LogFilter{Type: "*_id", Pattern: "debug_*", Negate: true},
+			nil,
+			[]any{"order_id", "o-1", "invoice_id", "debug_1"},
+			false,
+		},
+		{
+			"negate without matching keys",
+			LogFilter{Type: "*_id", Pattern: "debug_*", Negate: true},
+			nil,
+			[]any{"user", "u-1"},
+			false,
+		},
+		{
+			"present",
+			LogFilter{Type: "*_id", MatchMode: MatchPresent},
+			nil,
+			[]any{"shipment_id", "s-1"},
+			true,
+		},
+		{
+			"absent",
+			LogFilter{Type: "*_id", MatchMode: MatchAbsent},
+			nil,
+			[]any{"user", "u-1"},
+			true,
+		},
+		{
+			"record scope ignores logger attrs",
+			LogFilter{Type: "*_id", Pattern: "debug_*", MatchScope: MatchScopeRecord},
+			func(l *slog.Logger) *slog.Logger { return l.With("order_id", "debug_1") },
+			nil,
+			false,
+		},
+		{
+			"preformatted scope ignores record attrs",
+			LogFilter{Type: "*_id", Pattern: "debug_*", MatchScope: MatchScopePreformatted},
+			nil,
+			[]any{"order_id", "debug_1"},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		level := new(slog.LevelVar)
+		level.Set(slog.LevelInfo)
+
+		inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		handler := NewHandler(inner, level)
+		tt.filter.Level = "debug"
+		tt.filter.Enabled = true
+		handler.SetFilters([]LogFilter{tt.filter})
+
+		logger := slog.New(handler)
+		if tt.logger != nil {
+			logger = tt.logger(logger)
+		}
+		logger.Debug("step", tt.attrs...)
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.want, buf.String())
+		}
+	}
+}
+
+func TestPrepare_WildcardAttributeKey(t *testing.T) {
+	tests := []struct {
+		typ  string
+		glob bool
+	}{
+		{"*_id", true},
+		{"job_?", true},
+		{"[ab]_id", true},
+		{"job_id", false},
+		{"labels.env", false},
+		{"context:label.*", false},
+		{"any:*_id", false},
+	}
+
+	for _, tt := range tests {
+		f := LogFilter{Type: tt.typ, Pattern: "x"}
+		f.prepare()
+		if got := f.keyGlob != nil; got != tt.glob {
+			t.Errorf("%s: expected key glob %v, got %v", tt.typ, tt.glob, got)
+		}
+		if f.keyGlob != nil && (f.keyPath || f.indexable()) {
+			t.Errorf("%s: expected a key glob to be neither a key path nor indexable", tt.typ)
+		}
+	}
+}
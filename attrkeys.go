@@ -0,0 +1,235 @@
+package logfilter
+
+import "log/slog"
+
+// attrKeys maps each attribute key that filters and suppressions read to its
+// slot in a recordView, so that Handle converts only those attributes to
+// strings instead of building a map of every attribute.
+type attrKeys map[string]int
+
+// buildAttrKeys collects the attribute keys read by the filters in lists,
+// their conditions, and suppressions.
+func buildAttrKeys(lists [][]LogFilter, suppressions []Suppression) attrKeys {
+	keys := make(attrKeys)
+	add := func(key string) {
+		if _, ok := keys[key]; !ok {
+			keys[key] = len(keys)
+		}
+	}
+	var addFilter func(f *LogFilter)
+	addFilter = func(f *LogFilter) {
+		if f.attributeKey != "" && f.keyGlob == nil {
+			add(f.attributeKey)
+		}
+		for i := range f.conditions {
+			addFilter(&f.conditions[i])
+		}
+	}
+	for _, list := range lists {
+		for i := range list {
+			addFilter(&list[i])
+		}
+	}
+	for _, s := range suppressions {
+		add(s.Key)
+	}
+	return keys
+}
+
+// updateAttrKeys recomputes the attribute keys read by the handler's filters
+// and suppressions, and whether there are any at all (see noFilters). Must be
+// called with filtersLock held.
+func (h *Handler) updateAttrKeys() {
+	lists := make([][]LogFilter, 0, 2+len(h.groups))
+	lists = append(lists, h.baseFilters, h.filters)
+	for _, g := range h.groups {
+		lists = append(lists, g.filters)
+	}
+	h.attrKeys = buildAttrKeys(lists, h.suppressions)
+
+	empty := len(h.suppressions) == 0
+	for _, list := range lists {
+		empty = empty && len(list) == 0
+	}
+	h.noFilters.Store(empty)
+}
+
+// Ranks of an attrSlot's source, in increasing precedence.
+const (
+	attrUnset uint8 = iota
+	attrPreformatted
+	attrGrouped
+	attrRecord
+)
+
+// attrSlot holds the value of one needed attribute for a record.
+type attrSlot struct {
+	val       slog.Value
+	str       string   // val as a string, set on first lookup
+	elems     []string // Elements of a slice val as strings, see elements
+	rank      uint8    // Source of val, attrUnset if not found
+	converted bool
+	isSlice   bool // val is a slice or array; set with elems
+	split     bool // elems and isSlice are set
+}
+
+// maxStackAttrSlots is the number of needed attributes a recordView holds
+// without allocating.
+const maxStackAttrSlots = 8
+
+// attr returns the value of the attribute with the given key as a string.
+// Record attributes take precedence over the logger's, later over earlier;
+// under WithGroup, keys also match by their group-qualified form, e.g.
+// "http.status".
+func (v *recordView) attr(key string) (string, bool) {
+	i, ok := v.keys[key]
+	if !ok {
+		// Not read by the current filters, e.g. a filter evaluated outside the
+		// handler's lists; resolve just this key.
+		s := v.scanAttr(key)
+		return s.string(v.h)
+	}
+	if !v.collected {
+		v.collectSlots()
+	}
+	return v.slot(i).string(v.h)
+}
+
+// attrElems returns the elements of the attribute with the given key as
+// strings, if its value is a slice or array (see attrValueElems), with the
+// same precedence as attr.
+func (v *recordView) attrElems(key string) ([]string, bool) {
+	i, ok := v.keys[key]
+	if !ok {
+		s := v.scanAttr(key)
+		return s.elements(v.h)
+	}
+	if !v.collected {
+		v.collectSlots()
+	}
+	return v.slot(i).elements(v.h)
+}
+
+// slot returns the i'th needed attribute's slot. Slots live in slotBuf when
+// they fit, rather than in a slice of it, which would make the view escape.
+func (v *recordView) slot(i int) *attrSlot {
+	if v.slots != nil {
+		return &v.slots[i]
+	}
+	return &v.slotBuf[i]
+}
+
+// collectSlots resolves every needed attribute in one pass over the record's
+// attributes. The logger's are only scanned if some slot is still unset.
+func (v *recordView) collectSlots() {
+	v.collected = true
+	if n := len(v.keys); n > len(v.slotBuf) {
+		v.slots = make([]attrSlot, n)
+	}
+
+	h := v.h
+	v.r.Attrs(func(a slog.Attr) bool {
+		v.setSlot(a.Key, a.Value, attrRecord)
+		if h.groupPrefix != "" {
+			v.setSlot(h.groupPrefix+a.Key, a.Value, attrRecord)
+		}
+		return true
+	})
+	if v.allSet() {
+		return
+	}
+	for _, a := range h.groupedAttrs {
+		v.setSlot(a.Key, a.Value, attrGrouped)
+	}
+	for _, a := range h.preformattedAttrs {
+		v.setSlot(a.Key, a.Value, attrPreformatted)
+	}
+}
+
+// setSlot stores val in key's slot, if key is needed and val's source
+// doesn't rank below the slot's current one.
+func (v *recordView) setSlot(key string, val slog.Value, rank uint8) {
+	if i, ok := v.keys[key]; ok {
+		if s := v.slot(i); s.rank <= rank {
+			*s = attrSlot{val: val, rank: rank}
+		}
+	}
+}
+
+// scanAttr resolves a single attribute with the same precedence as
+// collectSlots.
+func (v *recordView) scanAttr(key string) attrSlot {
+	if s := v.scanRecordAttr(key); s.rank != attrUnset {
+		return s
+	}
+	return v.scanLoggerAttr(key)
+}
+
+// scanRecordAttr resolves a single attribute from the record's attributes.
+func (v *recordView) scanRecordAttr(key string) attrSlot {
+	var s attrSlot
+	h := v.h
+	v.r.Attrs(func(a slog.Attr) bool {
+		if a.Key == key || (h.groupPrefix != "" && h.groupPrefix+a.Key == key) {
+			s = attrSlot{val: a.Value, rank: attrRecord}
+		}
+		return true
+	})
+	return s
+}
+
+// scanLoggerAttr resolves a single attribute from the logger's attributes,
+// grouped ones taking precedence as in collectSlots.
+func (v *recordView) scanLoggerAttr(key string) attrSlot {
+	var s attrSlot
+	h := v.h
+	for _, a := range h.groupedAttrs {
+		if a.Key == key {
+			s = attrSlot{val: a.Value, rank: attrGrouped}
+		}
+	}
+	if s.rank != attrUnset {
+		return s
+	}
+	for _, a := range h.preformattedAttrs {
+		if a.Key == key {
+			s = attrSlot{val: a.Value, rank: attrPreformatted}
+		}
+	}
+	return s
+}
+
+// allSet reports whether every needed attribute has a value.
+func (v *recordView) allSet() bool {
+	for i := range len(v.keys) {
+		if v.slot(i).rank == attrUnset {
+			return false
+		}
+	}
+	return true
+}
+
+// string returns the slot's value as a string for matching, converting it
+// once (see Handler.matchString).
+func (s *attrSlot) string(h *Handler) (string, bool) {
+	if s.rank == attrUnset {
+		return "", false
+	}
+	if !s.converted {
+		s.str, s.converted = h.matchString(s.val), true
+	}
+	return s.str, true
+}
+
+// elements returns the slot's slice elements as strings for matching,
+// converting them once, and whether the value is a slice.
+func (s *attrSlot) elements(h *Handler) ([]string, bool) {
+	if s.rank == attrUnset {
+		return nil, false
+	}
+	if !s.split {
+		s.elems, s.isSlice = h.matchElements(s.val)
+		s.split = true
+	}
+	return s.elems, s.isSlice
+}
@@ -0,0 +1,145 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestHandler_AttrKeys(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level,
+		WithBaseFilters([]LogFilter{{Type: "tenant", Pattern: "acme", Level: "debug", Enabled: true}}))
+	handler.SetFilters([]LogFilter{
+		{Type: "service", Pattern: "auth", Level: "debug", Enabled: true},
+		{Type: "any:user", Pattern: "admin", Level: "debug", Enabled: true},
+		{Type: "context:request_id", Pattern: "*", Level: "debug", Enabled: true},
+		{Conditions: []Condition{{Type: "env", Pattern: "prod"}}, Level: "debug", Enabled: true},
+	})
+	handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/health"}})
+
+	handler.filtersLock.RLock()
+	keys := handler.attrKeys
+	handler.filtersLock.RUnlock()
+	for _, key := range []string{"tenant", "service", "user", "env", "path"} {
+		if _, ok := keys[key]; !ok {
+			t.Errorf("Expected attribute key %q to be collected, got %v", key, keys)
+		}
+	}
+	if _, ok := keys["request_id"]; ok {
+		t.Errorf("Expected context-only key not to be collected")
+	}
+
+	handler.ClearGlobalSuppressions()
+	handler.filtersLock.RLock()
+	_, ok := handler.attrKeys["path"]
+	handler.filtersLock.RUnlock()
+	if ok {
+		t.Errorf("Expected suppression key to be dropped with the suppressions")
+	}
+}
+
+func TestRecordView_Attr(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "service", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "status", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "http.status", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "env", Pattern: "*", Level: "debug", Enabled: true},
+	})
+	scoped := handler.WithAttrs([]slog.Attr{slog.String("service", "logger"), slog.String("env", "prod")}).
+		WithGroup("http").WithAttrs([]slog.Attr{slog.Int("status", 500)}).(*Handler)
+
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "request", 0)
+	r.AddAttrs(slog.String("service", "first"), slog.String("service", "record"))
+
+	handler.filtersLock.RLock()
+	keys := handler.attrKeys
+	handler.filtersLock.RUnlock()
+	v := recordView{h: scoped, ctx: context.Background(), r: &r, keys: keys}
+
+	tests := []struct {
+		key   string
+		want  string
+		found bool
+	}{
+		{"service", "record", true}, // Record over logger, later over earlier
+		{"env", "prod", true},
+		{"status", "500", true},      // Bare key of a grouped attribute
+		{"http.status", "500", true}, // Qualified key
+		{"missing", "", false},       // Not collected: resolved on demand
+		{"http.env", "", false},
+	}
+	for _, tt := range tests {
+		if got, found := v.attr(tt.key); got != tt.want || found != tt.found {
+			t.Errorf("Expected attr(%q) = (%q, %v), got (%q, %v)", tt.key, tt.want, tt.found, got, found)
+		}
+	}
+}
+
+func TestHandler_AttrKeys_ManyKeys(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+
+	// More keys than a recordView holds without allocating
+	var filters []LogFilter
+	for i := 0; i < 2*maxStackAttrSlots; i++ {
+		filters = append(filters, LogFilter{Type: fmt.Sprintf("key%d", i), Pattern: "match", Level: "debug", Enabled: true})
+	}
+	handler.SetFilters(filters)
+	logger := slog.New(handler)
+
+	last := fmt.Sprintf("key%d", 2*maxStackAttrSlots-1)
+	logger.Debug("hit", "key0", "other", last, "match")
+	if buf.Len() == 0 {
+		t.Errorf("Expected a match on %s to be emitted", last)
+	}
+	buf.Reset()
+	logger.Debug("miss", "key0", "other", last, "other")
+	if buf.Len() > 0 {
+		t.Errorf("Expected no match, got: %s", buf.String())
+	}
+}
+
+func BenchmarkHandle_ManyAttrs(b *testing.B) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "service", Pattern: "auth*", Level: "debug", Enabled: true},
+		{Type: "user_id", Pattern: "admin_*", Level: "debug", Enabled: true},
+	})
+	logger := handler.WithAttrs([]slog.Attr{slog.String("env", "prod"), slog.Int("pid", 4242)})
+
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "request", 0)
+	r.AddAttrs(
+		slog.String("service", "billing"),
+		slog.String("method", "GET"),
+		slog.String("path", "/api/v1/invoices"),
+		slog.Int("status", 200),
+		slog.Duration("latency", 42*time.Millisecond),
+		slog.Int64("bytes", 18234),
+		slog.Float64("ratio", 0.93),
+		slog.Time("started", time.Now()),
+		slog.Bool("cached", false),
+		slog.String("request_id", "req_0123456789"),
+	)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Handle(ctx, r)
+	}
+}
@@ -0,0 +1,202 @@
+package logfilter
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// FilterBuilder builds a LogFilter fluently, taking levels as slog.Level
+// rather than strings:
+//
+//	f := logfilter.NewFilter("job_id").
+//		Pattern("job_*").
+//		Level(slog.LevelDebug).
+//		OutputLevel(slog.LevelInfo).
+//		ExpiresIn(10 * time.Minute).
+//		Build()
+//
+// Built filters are enabled unless Disabled is called.
+type FilterBuilder struct {
+	f LogFilter
+}
+
+// NewFilter starts building an enabled filter of the given type (see
+// LogFilter.Type).
+func NewFilter(filterType string) *FilterBuilder {
+	return &FilterBuilder{f: LogFilter{Type: filterType, Enabled: true}}
+}
+
+// ID sets the filter's ID.
+func (b *FilterBuilder) ID(id string) *FilterBuilder {
+	b.f.ID = id
+	return b
+}
+
+// Name sets the filter's Name.
+func (b *FilterBuilder) Name(name string) *FilterBuilder {
+	b.f.Name = name
+	return b
+}
+
+// Pattern sets the glob pattern (or regex or numeric comparison, see Regex
+// and Numeric) matched against the value.
+func (b *FilterBuilder) Pattern(pattern string) *FilterBuilder {
+	b.f.Pattern = pattern
+	return b
+}
+
+// Negate inverts the pattern match.
+func (b *FilterBuilder) Negate() *FilterBuilder {
+	b.f.Negate = true
+	return b
+}
+
+// Regex treats the pattern as a regular expression.
+func (b *FilterBuilder) Regex() *FilterBuilder {
+	b.f.Regex = true
+	return b
+}
+
+// Numeric treats the pattern as a numeric comparison.
+func (b *FilterBuilder) Numeric() *FilterBuilder {
+	b.f.Numeric = true
+	return b
+}
+
+// Condition adds a condition to a compound filter.
+func (b *FilterBuilder) Condition(conditionType, pattern string) *FilterBuilder {
+	b.f.Conditions = append(b.f.Conditions, Condition{Type: conditionType, Pattern: pattern})
+	return b
+}
+
+// MatchMode matches on whether the value exists rather than on a pattern:
+// MatchPresent, MatchAbsent, MatchEmpty or MatchNonEmpty. MatchErrorType
+// matches the pattern against the type of the error the value holds.
+func (b *FilterBuilder) MatchMode(mode string) *FilterBuilder {
+	b.f.MatchMode = mode
+	return b
+}
+
+// MatchScope restricts the attributes consulted: MatchScopeRecord,
+// MatchScopePreformatted or MatchScopeBoth.
+func (b *FilterBuilder) MatchScope(scope string) *FilterBuilder {
+	b.f.MatchScope = scope
+	return b
+}
+
+// MatchAny makes a compound filter match when any condition matches, rather
+// than all.
+func (b *FilterBuilder) MatchAny() *FilterBuilder {
+	b.f.Match = ConditionsAny
+	return b
+}
+
+// SourceLevels restricts the filter to records at one of levels.
+func (b *FilterBuilder) SourceLevels(levels ...slog.Level) *FilterBuilder {
+	for _, l := range levels {
+		b.f.SourceLevels = append(b.f.SourceLevels, LevelToString(l))
+	}
+	return b
+}
+
+// TimeWindow restricts the filter to records logged within w.
+func (b *FilterBuilder) TimeWindow(w TimeWindow) *FilterBuilder {
+	b.f.TimeWindow = &w
+	return b
+}
+
+// Level sets the minimum level for matching records.
+func (b *FilterBuilder) Level(level slog.Level) *FilterBuilder {
+	b.f.Level = LevelToString(level)
+	return b
+}
+
+// OutputLevel sets the level matching records are emitted at.
+func (b *FilterBuilder) OutputLevel(level slog.Level) *FilterBuilder {
+	b.f.OutputLevel = LevelToString(level)
+	return b
+}
+
+// OutputLevelOffset emits matching records steps levels above (or, if
+// negative, below) their own, e.g. +1 for debug to info.
+func (b *FilterBuilder) OutputLevelOffset(steps int) *FilterBuilder {
+	b.f.OutputLevel = fmt.Sprintf("%+d", steps)
+	return b
+}
+
+// Priority sets the filter's evaluation priority.
+func (b *FilterBuilder) Priority(priority int) *FilterBuilder {
+	b.f.Priority = priority
+	return b
+}
+
+// Route sends matching records to the named route.
+func (b *FilterBuilder) Route(name string) *FilterBuilder {
+	b.f.Route = name
+	return b
+}
+
+// ThrottlePerValue limits matching records to one per value per interval.
+func (b *FilterBuilder) ThrottlePerValue(interval time.Duration) *FilterBuilder {
+	b.f.ThrottlePerValue = interval
+	return b
+}
+
+// MaxPerSecond limits the rate of matching records.
+func (b *FilterBuilder) MaxPerSecond(n int) *FilterBuilder {
+	b.f.MaxPerSecond = n
+	return b
+}
+
+// MaxHits makes the filter inactive after it has matched n records.
+func (b *FilterBuilder) MaxHits(n int) *FilterBuilder {
+	b.f.MaxHits = n
+	return b
+}
+
+// ExpiresAt sets the time the filter expires.
+func (b *FilterBuilder) ExpiresAt(t time.Time) *FilterBuilder {
+	b.f.ExpiresAt = &t
+	return b
+}
+
+// ExpiresIn makes the filter expire d from now, according to the
+// package-level clock (see SetDefaultClock).
+func (b *FilterBuilder) ExpiresIn(d time.Duration) *FilterBuilder {
+	return b.ExpiresAt(now().Add(d))
+}
+
+// Confirmed acknowledges a catch-all pattern (see WithRejectMatchAll).
+func (b *FilterBuilder) Confirmed() *FilterBuilder {
+	b.f.Confirmed = true
+	return b
+}
+
+// Disabled builds the filter disabled.
+func (b *FilterBuilder) Disabled() *FilterBuilder {
+	b.f.Enabled = false
+	return b
+}
+
+// Build returns the filter. The builder can be reused; later changes don't
+// affect filters already built.
+func (b *FilterBuilder) Build() LogFilter {
+	f := b.f
+	if f.ExpiresAt != nil {
+		t := *f.ExpiresAt
+		f.ExpiresAt = &t
+	}
+	if f.Conditions != nil {
+		f.Conditions = append([]Condition(nil), f.Conditions...)
+	}
+	if f.SourceLevels != nil {
+		f.SourceLevels = append([]string(nil), f.SourceLevels...)
+	}
+	if f.TimeWindow != nil {
+		w := *f.TimeWindow
+		w.Weekdays = append([]string(nil), w.Weekdays...)
+		f.TimeWindow = &w
+	}
+	return f
+}
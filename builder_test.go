@@ -0,0 +1,149 @@
+package logfilter
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterBuilder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	SetDefaultClock(newFakeClock(start))
+	defer SetDefaultClock(nil)
+
+	expires := start.Add(10 * time.Minute)
+	tests := []struct {
+		name  string
+		built LogFilter
+		want  LogFilter
+	}{
+		{
+			name: "basic",
+			built: NewFilter("job_id").
+				Pattern("job_*").
+				Level(slog.LevelDebug).
+				OutputLevel(slog.LevelInfo).
+				ExpiresIn(10 * time.Minute).
+				Build(),
+			want: LogFilter{
+				Type:        "job_id",
+				Pattern:     "job_*",
+				Level:       "debug",
+				OutputLevel: "info",
+				ExpiresAt:   &expires,
+				Enabled:     true,
+			},
+		},
+		{
+			name: "expires at",
+			built: NewFilter("service").
+				Pattern("auth").
+				Level(slog.LevelWarn).
+				ExpiresAt(expires).
+				Build(),
+			want: LogFilter{Type: "service", Pattern: "auth", Level: "warn", ExpiresAt: &expires, Enabled: true},
+		},
+		{
+			name: "options",
+			built: NewFilter("duration_ms").
+				ID("slow").
+				Name("slow-requests").
+				Pattern(">500").
+				Numeric().
+				Negate().
+				Level(LevelTrace).
+				OutputLevel(slog.LevelError).
+				Priority(5).
+				Route("audit").
+				MaxPerSecond(10).
+				ThrottlePerValue(time.Second).
+				Disabled().
+				Build(),
+			want: LogFilter{
+				ID:               "slow",
+				Name:             "slow-requests",
+				Type:             "duration_ms",
+				Pattern:          ">500",
+				Numeric:          true,
+				Negate:           true,
+				Level:            "trace",
+				OutputLevel:      "error",
+				Priority:         5,
+				Route:            "audit",
+				MaxPerSecond:     10,
+				ThrottlePerValue: time.Second,
+			},
+		},
+		{
+			name: "compound",
+			built: NewFilter("").
+				Condition("service", "auth").
+				Condition("user_id", "admin*").
+				MatchAny().
+				Level(slog.LevelDebug).
+				Build(),
+			want: LogFilter{
+				Conditions: []Condition{{Type: "service", Pattern: "auth"}, {Type: "user_id", Pattern: "admin*"}},
+				Match:      ConditionsAny,
+				Level:      "debug",
+				Enabled:    true,
+			},
+		},
+		{
+			name:  "regex confirmed",
+			built: NewFilter("path").Pattern(".*").Regex().Confirmed().Build(),
+			want:  LogFilter{Type: "path", Pattern: ".*", Regex: true, Confirmed: true, Enabled: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.built, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, tt.built)
+			}
+		})
+	}
+}
+
+func TestFilterBuilder_Reuse(t *testing.T) {
+	b := NewFilter("service").Pattern("auth").Condition("env", "prod").ExpiresAt(time.Unix(0, 0))
+	first := b.Build()
+	b.Pattern("billing").Condition("region", "eu").ExpiresAt(time.Unix(60, 0))
+
+	if first.Pattern != "auth" || len(first.Conditions) != 1 || !first.ExpiresAt.Equal(time.Unix(0, 0)) {
+		t.Errorf("Expected built filter to be unaffected by later builder changes, got %+v", first)
+	}
+}
+
+func TestFilterBuilder_Levels(t *testing.T) {
+	defer ClearLevelNames()
+	if err := RegisterLevelName("notice", slog.Level(2)); err != nil {
+		t.Fatalf("Expected RegisterLevelName to succeed, got %v", err)
+	}
+
+	for _, level := range []slog.Level{LevelTrace, slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, 2, 3, -2, 12} {
+		f := NewFilter("service").Level(level).Build()
+		got, err := ParseLevelStrict(f.Level)
+		if err != nil {
+			t.Errorf("Expected level %q to parse, got %v", f.Level, err)
+			continue
+		}
+		if got != level {
+			t.Errorf("Expected %q to round-trip to %v, got %v", f.Level, level, got)
+		}
+	}
+
+	if f := NewFilter("service").Level(2).Build(); f.Level != "notice" {
+		t.Errorf("Expected registered name \"notice\", got %q", f.Level)
+	}
+
+	// A negative level must not read back as a relative OutputLevel.
+	f := NewFilter("service").OutputLevel(-2).Build()
+	if got := f.GetOutputLevel(slog.LevelError); got != -2 {
+		t.Errorf("Expected OutputLevel %q to be absolute -2, got %v", f.OutputLevel, got)
+	}
+	if f := NewFilter("service").OutputLevelOffset(1).Build(); f.OutputLevel != "+1" {
+		t.Errorf("Expected offset \"+1\", got %q", f.OutputLevel)
+	}
+}
@@ -0,0 +1,63 @@
+package logfilter
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetFilteringEnabled turns the filter subsystem on or off without changing
+// the filters. While disabled, Handle skips filter evaluation and emits
+// records at or above the global level to the inner handler unchanged, and
+// drops the rest; filters, suppressions, sampling, routes, the firehose and
+// capture-on-error are all bypassed. Redaction still applies. Filtering is
+// enabled by default.
+func (h *Handler) SetFilteringEnabled(enabled bool) {
+	h.filteringDisabled.Store(!enabled)
+}
+
+// FilteringEnabled reports whether filtering is enabled (see
+// SetFilteringEnabled).
+func (h *Handler) FilteringEnabled() bool {
+	return !h.filteringDisabled.Load()
+}
+
+// handleUnfiltered is Handle while filtering is disabled: the global level
+// alone decides.
+func (h *Handler) handleUnfiltered(ctx context.Context, r slog.Record) error {
+	emit := r.Level >= h.floored(h.globalLevel.Level())
+	h.levelCounters.record(r.Level, emit)
+	h.recordMetrics(r.Level, nil, emit)
+	if !emit {
+		return nil
+	}
+	if h.redactor != nil {
+		r = h.redactor.record(r)
+	}
+	_, inner := h.resolveInner()
+	return h.reportError(h.handleWithRetry(ctx, inner, r))
+}
+
+// unmatchedDecision is the decision for a record at level that no filter
+// matches: the context's ContextWithLevel override, else the global level,
+// decides, unless WithAllowListMode suppresses it outright. Handle uses it
+// directly when there are no filters to evaluate.
+func (h *Handler) unmatchedDecision(ctx context.Context, level slog.Level) decision {
+	minLevel := h.globalLevel.Level()
+	if ctxLevel, ok := LevelFromContext(ctx); ok {
+		minLevel = ctxLevel
+	}
+	d := decision{level: h.floored(minLevel), outputLevel: level}
+	d.emit = level >= d.level && !h.allowList
+	return d
+}
+
+// SetFilteringEnabled turns filtering on the global handler on or off.
+func SetFilteringEnabled(enabled bool) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.SetFilteringEnabled(enabled)
+	}
+}
@@ -0,0 +1,97 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_SetFilteringEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithRedactKeys("password"))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "error", Enabled: true},
+		{Type: "service", Pattern: "noisy", Level: "error", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	if !handler.FilteringEnabled() {
+		t.Fatal("Expected filtering to be enabled by default")
+	}
+	handler.SetFilteringEnabled(false)
+	if handler.FilteringEnabled() {
+		t.Fatal("Expected filtering to be disabled")
+	}
+
+	tests := []struct {
+		name  string
+		log   func()
+		emits bool
+	}{
+		{"elevating filter ignored", func() { logger.Debug("step", "job_id", "job_1") }, false},
+		{"suppressing filter ignored", func() { logger.Info("tick", "service", "noisy") }, true},
+		{"global level applies", func() { logger.Info("hello") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected Enabled to follow the global level while filtering is disabled")
+	}
+
+	buf.Reset()
+	logger.Info("login", "password", "hunter2")
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Expected redaction to apply while filtering is disabled, got: %s", buf.String())
+	}
+
+	// Re-enabling restores the filters unchanged
+	handler.SetFilteringEnabled(true)
+	buf.Reset()
+	logger.Debug("step", "job_id", "job_1")
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("Expected filter to apply again, got: %s", buf.String())
+	}
+}
+
+func BenchmarkHandle_FilteringDisabled(b *testing.B) {
+	for _, mode := range []string{"enabled", "disabled"} {
+		b.Run(mode, func(b *testing.B) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+			handler.SetFilters([]LogFilter{
+				{Type: "service", Pattern: "auth*", Level: "debug", Enabled: true},
+				{Type: "user_id", Pattern: "admin_*", Level: "debug", Enabled: true},
+				{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+			})
+			handler.SetFilteringEnabled(mode == "enabled")
+
+			r := slog.NewRecord(time.Now(), slog.LevelInfo, "request", 0)
+			r.AddAttrs(slog.String("service", "billing"), slog.String("user_id", "u_1"))
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = handler.Handle(ctx, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package logfilter
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// maxCaptureIDs bounds the number of context IDs tracked by capture-on-error.
+// When exceeded, the least recently used ID and its records are discarded.
+const maxCaptureIDs = 1024
+
+// WithCaptureOnError enables capture-on-error mode. Records that would be
+// suppressed are held in a per-context ring buffer of up to window records,
+// keyed by the value returned by the context extractor registered for
+// contextKey (see RegisterContextExtractor). When an error-level record is
+// emitted with the same context ID, the held records are emitted first, in
+// order, followed by the error. Held records for IDs that never see an error
+// are eventually discarded.
+//
+// Records whose context yields no ID are suppressed as usual. Because every
+// record must reach Handle to be buffered, this mode disables slog's Enabled
+// short-circuit for levels below the global level.
+//
+// At most 1024 context IDs are tracked at once; the least recently used is
+// evicted first, so memory is bounded by roughly 1024 * window records.
+func WithCaptureOnError(contextKey string, window int) Option {
+	return func(o *options) {
+		o.captureKey = contextKey
+		o.captureWindow = window
+	}
+}
+
+// captureBuffer holds suppressed records per context ID until an error arrives.
+type captureBuffer struct {
+	key    string // Context extractor key
+	window int    // Max records retained per ID
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // ID -> element in order
+	order   *list.List               // Most recently used at front
+}
+
+// captureEntry is the set of held records for one context ID.
+type captureEntry struct {
+	id      string
+	records []capturedRecord
+}
+
+// capturedRecord is a held record along with the handler it should be emitted
+// through, so attributes and groups from WithAttrs/WithGroup are preserved.
+type capturedRecord struct {
+	handler slog.Handler
+	record  slog.Record
+}
+
+// newCaptureBuffer creates a capture buffer keyed by the given context key.
+func newCaptureBuffer(key string, window int) *captureBuffer {
+	return &captureBuffer{
+		key:     key,
+		window:  window,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// hold buffers a suppressed record under its context ID, if it has one.
+func (c *captureBuffer) hold(ctx context.Context, handler slog.Handler, r slog.Record) {
+	id, ok := extractFromContext(ctx, c.key)
+	if !ok || id == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entry *captureEntry
+	if el, ok := c.entries[id]; ok {
+		c.order.MoveToFront(el)
+		entry = el.Value.(*captureEntry)
+	} else {
+		if c.order.Len() >= maxCaptureIDs {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*captureEntry).id)
+		}
+		entry = &captureEntry{id: id}
+		c.entries[id] = c.order.PushFront(entry)
+	}
+
+	if len(entry.records) >= c.window {
+		copy(entry.records, entry.records[1:])
+		entry.records = entry.records[:len(entry.records)-1]
+	}
+	entry.records = append(entry.records, capturedRecord{handler: handler, record: r.Clone()})
+}
+
+// flush emits and discards the records held for the context's ID, joining
+// any errors.
+func (c *captureBuffer) flush(ctx context.Context) error {
+	id, ok := extractFromContext(ctx, c.key)
+	if !ok || id == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	el, ok := c.entries[id]
+	if ok {
+		c.order.Remove(el)
+		delete(c.entries, id)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, cr := range el.Value.(*captureEntry).records {
+		if err := cr.handler.Handle(ctx, cr.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pending returns the number of records currently held for id.
+func (c *captureBuffer) pending(id string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		return len(el.Value.(*captureEntry).records)
+	}
+	return 0
+}
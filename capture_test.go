@@ -0,0 +1,171 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type captureCtxKey struct{}
+
+func registerCaptureExtractor(t *testing.T) {
+	t.Helper()
+	RegisterContextExtractor("request_id", func(ctx context.Context) (string, bool) {
+		if v, ok := ctx.Value(captureCtxKey{}).(string); ok {
+			return v, true
+		}
+		return "", false
+	})
+	t.Cleanup(ClearContextExtractors)
+}
+
+func TestCaptureOnError_FlushesOnError(t *testing.T) {
+	registerCaptureExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithCaptureOnError("request_id", 10))
+	logger := slog.New(handler)
+
+	ctx := context.WithValue(context.Background(), captureCtxKey{}, "req-1")
+	logger.DebugContext(ctx, "step one")
+	logger.DebugContext(ctx, "step two")
+
+	if buf.Len() > 0 {
+		t.Fatalf("Expected debug records to be held, got: %s", buf.String())
+	}
+
+	logger.ErrorContext(ctx, "boom")
+
+	out := buf.String()
+	one, two, boom := strings.Index(out, "step one"), strings.Index(out, "step two"), strings.Index(out, "boom")
+	if one < 0 || two < 0 || boom < 0 {
+		t.Fatalf("Expected held records and error to be emitted, got: %s", out)
+	}
+	if !(one < two && two < boom) {
+		t.Errorf("Expected held records before the error in order, got: %s", out)
+	}
+	if !strings.Contains(out, "level=DEBUG") {
+		t.Errorf("Expected held records to keep their level, got: %s", out)
+	}
+	if n := handler.capture.pending("req-1"); n != 0 {
+		t.Errorf("Expected buffer to be cleared after flush, got %d", n)
+	}
+}
+
+func TestCaptureOnError_IsolatedByID(t *testing.T) {
+	registerCaptureExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithCaptureOnError("request_id", 10))
+	logger := slog.New(handler)
+
+	ctxA := context.WithValue(context.Background(), captureCtxKey{}, "a")
+	ctxB := context.WithValue(context.Background(), captureCtxKey{}, "b")
+	logger.DebugContext(ctxA, "from a")
+	logger.DebugContext(ctxB, "from b")
+	logger.ErrorContext(ctxA, "a failed")
+
+	out := buf.String()
+	if !strings.Contains(out, "from a") {
+		t.Errorf("Expected records for a to be flushed, got: %s", out)
+	}
+	if strings.Contains(out, "from b") {
+		t.Errorf("Expected records for b to stay held, got: %s", out)
+	}
+	if n := handler.capture.pending("b"); n != 1 {
+		t.Errorf("Expected 1 held record for b, got %d", n)
+	}
+}
+
+func TestCaptureOnError_WindowBounded(t *testing.T) {
+	registerCaptureExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithCaptureOnError("request_id", 3))
+	logger := slog.New(handler)
+
+	ctx := context.WithValue(context.Background(), captureCtxKey{}, "req")
+	for i := 0; i < 5; i++ {
+		logger.DebugContext(ctx, fmt.Sprintf("msg%d", i))
+	}
+	if n := handler.capture.pending("req"); n != 3 {
+		t.Errorf("Expected window to cap held records at 3, got %d", n)
+	}
+
+	logger.ErrorContext(ctx, "boom")
+	out := buf.String()
+	if strings.Contains(out, "msg0") || strings.Contains(out, "msg1") {
+		t.Errorf("Expected oldest records to be evicted, got: %s", out)
+	}
+	if !strings.Contains(out, "msg4") {
+		t.Errorf("Expected newest records to be flushed, got: %s", out)
+	}
+}
+
+func TestCaptureOnError_IDsBounded(t *testing.T) {
+	c := newCaptureBuffer("request_id", 1)
+	registerCaptureExtractor(t)
+
+	inner := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	for i := 0; i < maxCaptureIDs+10; i++ {
+		ctx := context.WithValue(context.Background(), captureCtxKey{}, fmt.Sprintf("id%d", i))
+		c.hold(ctx, inner, slog.Record{Message: "x"})
+	}
+
+	if got := len(c.entries); got != maxCaptureIDs {
+		t.Errorf("Expected %d tracked IDs, got %d", maxCaptureIDs, got)
+	}
+	if c.pending("id0") != 0 {
+		t.Error("Expected least recently used ID to be evicted")
+	}
+}
+
+func TestCaptureOnError_NoIDSuppressed(t *testing.T) {
+	registerCaptureExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithCaptureOnError("request_id", 10))
+	logger := slog.New(handler)
+
+	logger.Debug("no id")
+	logger.Error("boom")
+
+	if strings.Contains(buf.String(), "no id") {
+		t.Errorf("Expected record without context ID to be dropped, got: %s", buf.String())
+	}
+}
+
+func TestCaptureOnError_PreservesScope(t *testing.T) {
+	registerCaptureExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithCaptureOnError("request_id", 10))
+	logger := slog.New(handler).With("component", "db")
+
+	ctx := context.WithValue(context.Background(), captureCtxKey{}, "req")
+	logger.DebugContext(ctx, "query")
+	slog.New(handler).ErrorContext(ctx, "boom")
+
+	if !strings.Contains(buf.String(), `msg=query component=db`) {
+		t.Errorf("Expected held record to keep logger attributes, got: %s", buf.String())
+	}
+}
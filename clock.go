@@ -0,0 +1,86 @@
+package logfilter
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time for time-dependent behavior such as filter
+// expiry and throttling. Inject one with WithClock or SetDefaultClock to make
+// that behavior deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function, such as time.Now or a test's fake, to Clock.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time { return f() }
+
+// systemClock is the Clock backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clockRef wraps a Clock so it can be stored atomically whatever its type.
+type clockRef struct {
+	clock Clock
+}
+
+// defaultClock is the package-level clock, used by handlers without WithClock
+// and by LogFilter.IsExpired and IsActive.
+var defaultClock atomic.Pointer[clockRef]
+
+func init() {
+	defaultClock.Store(&clockRef{clock: systemClock{}})
+}
+
+// SetDefaultClock sets the package-level clock used by handlers created
+// without WithClock and by LogFilter.IsExpired and IsActive. Passing nil
+// restores the system clock.
+func SetDefaultClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	defaultClock.Store(&clockRef{clock: c})
+}
+
+// now returns the current time from the package-level clock.
+func now() time.Time {
+	return defaultClock.Load().clock.Now()
+}
+
+// WithClock sets the clock the handler reads for all time-dependent behavior
+// (filter expiry, throttling, rate limits). Defaults to the package-level
+// clock, see SetDefaultClock.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// SetClock replaces the handler's clock at runtime, e.g. to advance a fake
+// clock past a filter's expiry in a test. It applies to every handler
+// derived from the same root. Passing nil reverts to the package-level
+// clock.
+func (h *Handler) SetClock(c Clock) {
+	if c == nil {
+		h.clock.Store(nil)
+	} else {
+		h.clock.Store(&clockRef{clock: c})
+	}
+
+	// Which filters are unexpired depends on the clock.
+	h.filtersLock.Lock()
+	h.updateLowestLevel()
+	h.filtersLock.Unlock()
+}
+
+// now returns the current time from the handler's clock.
+func (h *Handler) now() time.Time {
+	if c := h.clock.Load(); c != nil {
+		return c.clock.Now()
+	}
+	return now()
+}
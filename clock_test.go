@@ -0,0 +1,155 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when advanced.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock {
+	return &fakeClock{t: t}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestHandler_WithClock_Expiry(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	expires := clock.Now().Add(time.Minute)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithClock(clock))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true, ExpiresAt: &expires},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("before expiry", "job_id", "job_1")
+	if buf.Len() == 0 {
+		t.Error("Expected debug to be emitted before expiry")
+	}
+
+	clock.Advance(2 * time.Minute)
+	buf.Reset()
+	logger.Debug("after expiry", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected debug to be suppressed after expiry, got: %s", buf.String())
+	}
+}
+
+func TestSetDefaultClock(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(nil)
+
+	expires := clock.Now().Add(time.Hour)
+	f := LogFilter{Enabled: true, ExpiresAt: &expires}
+
+	if f.IsExpired() || !f.IsActive() {
+		t.Error("Expected filter to be active before expiry")
+	}
+	clock.Advance(2 * time.Hour)
+	if !f.IsExpired() || f.IsActive() {
+		t.Error("Expected filter to be expired per the default clock")
+	}
+
+	// Handlers without WithClock follow the default clock
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	if !handler.now().Equal(clock.Now()) {
+		t.Errorf("Expected handler to use default clock, got %v", handler.now())
+	}
+}
+
+func TestHandler_WithClock_ThrottleWithoutRecordTime(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithClock(clock))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "*", Level: "info", Enabled: true, ThrottlePerValue: time.Second},
+	})
+
+	emit := func() bool {
+		buf.Reset()
+		r := slog.NewRecord(time.Time{}, slog.LevelInfo, "tick", 0)
+		r.AddAttrs(slog.String("job_id", "a"))
+		_ = handler.Handle(context.Background(), r)
+		return buf.Len() > 0
+	}
+
+	if !emit() {
+		t.Error("Expected first record to be emitted")
+	}
+	if emit() {
+		t.Error("Expected second record within interval to be throttled")
+	}
+	clock.Advance(time.Second)
+	if !emit() {
+		t.Error("Expected record after interval to be emitted")
+	}
+}
+
+func TestHandler_SetClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expires := start.Add(time.Minute)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true, ExpiresAt: &expires},
+	})
+	derived := slog.New(handler.WithAttrs([]slog.Attr{slog.String("service", "api")}))
+
+	current := start
+	handler.SetClock(ClockFunc(func() time.Time { return current }))
+
+	derived.Debug("before expiry", "job_id", "job_1")
+	if buf.Len() == 0 {
+		t.Error("Expected debug to be emitted before expiry")
+	}
+
+	current = start.Add(2 * time.Minute)
+	buf.Reset()
+	derived.Debug("after expiry", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected derived logger to follow the new clock past expiry, got: %s", buf.String())
+	}
+
+	// nil reverts to the package-level clock
+	SetDefaultClock(ClockFunc(func() time.Time { return start }))
+	defer SetDefaultClock(nil)
+	handler.SetClock(nil)
+	if got := handler.now(); !got.Equal(start) {
+		t.Errorf("Expected the package-level clock after SetClock(nil), got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package logfilter
+
+import "log/slog"
+
+// MetricsCollector receives the handler's per-record decisions, for export
+// to a metrics system; see WithMetrics. The prommetrics subpackage provides
+// an implementation that is a prometheus.Collector.
+//
+// level is the record's original level. filterID is the FilterID of the
+// filter that decided the record, or empty if none did (the global level
+// decided, a global suppression dropped the record, or baseline sampling
+// emitted it). Methods are called synchronously from Handle, concurrently
+// for concurrent records, so they must be safe for concurrent use and cheap.
+type MetricsCollector interface {
+	// RecordSeen is called for every record Handle receives.
+	RecordSeen(level slog.Level)
+
+	// RecordEmitted is called for every record passed to the inner handler.
+	RecordEmitted(level slog.Level, filterID string)
+
+	// RecordSuppressed is called for every record dropped.
+	RecordSuppressed(level slog.Level, filterID string)
+
+	// RecordElevated is called, besides RecordEmitted, for records below the
+	// global level that a filter let through.
+	RecordElevated(level slog.Level, filterID string)
+}
+
+// NopMetricsCollector is a MetricsCollector that discards everything. It is
+// the default, and can be embedded by collectors interested in only some
+// events.
+type NopMetricsCollector struct{}
+
+func (NopMetricsCollector) RecordSeen(slog.Level)               {}
+func (NopMetricsCollector) RecordEmitted(slog.Level, string)    {}
+func (NopMetricsCollector) RecordSuppressed(slog.Level, string) {}
+func (NopMetricsCollector) RecordElevated(slog.Level, string)   {}
+
+// WithMetrics reports the handler's decisions to collector. Unlike
+// WriteMetrics, whose counters are per level and per filter, collector sees
+// each record's level and deciding filter together.
+//
+// Example:
+//
+//	collector := prommetrics.NewCollector()
+//	prometheus.MustRegister(collector)
+//	logger := logfilter.New(logfilter.WithMetrics(collector))
+func WithMetrics(collector MetricsCollector) Option {
+	return func(o *options) {
+		o.metrics = collector
+	}
+}
+
+// recordMetrics reports a record's outcome to the handler's collector.
+func (h *Handler) recordMetrics(level slog.Level, f *LogFilter, emitted bool) {
+	var id string
+	if f != nil {
+		id = f.FilterID()
+	}
+	if !emitted {
+		h.metrics.RecordSuppressed(level, id)
+		return
+	}
+	h.metrics.RecordEmitted(level, id)
+	if f != nil && level < h.globalLevel.Level() {
+		h.metrics.RecordElevated(level, id)
+	}
+}
@@ -0,0 +1,118 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCollector counts MetricsCollector events by kind and filter ID.
+type fakeCollector struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newFakeCollector() *fakeCollector {
+	return &fakeCollector{counts: make(map[string]int)}
+}
+
+func (c *fakeCollector) add(event string) {
+	c.mu.Lock()
+	c.counts[event]++
+	c.mu.Unlock()
+}
+
+func (c *fakeCollector) RecordSeen(level slog.Level) { c.add("seen") }
+func (c *fakeCollector) RecordEmitted(level slog.Level, filterID string) {
+	c.add("emitted:" + filterID)
+}
+func (c *fakeCollector) RecordSuppressed(level slog.Level, filterID string) {
+	c.add("suppressed:" + filterID)
+}
+func (c *fakeCollector) RecordElevated(level slog.Level, filterID string) {
+	c.add("elevated:" + filterID)
+}
+
+func (c *fakeCollector) count(event string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[event]
+}
+
+func TestWithMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	collector := newFakeCollector()
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, WithMetrics(collector))
+	handler.SetFilters([]LogFilter{
+		{ID: "debug-jobs", Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+		{ID: "quiet-db", Type: "component", Pattern: "db", Level: "error", Enabled: true},
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("elevated", "job_id", "debug_1")
+	logger.Debug("elevated", "job_id", "debug_2")
+	logger.Debug("dropped", "job_id", "other")
+	logger.Info("emitted")
+	logger.Warn("quieted", "component", "db")
+	logger.Error("loud", "component", "db")
+
+	tests := []struct {
+		event string
+		want  int
+	}{
+		{"seen", 6},
+		{"emitted:debug-jobs", 2},
+		{"elevated:debug-jobs", 2},
+		{"suppressed:", 1},
+		{"emitted:", 1},
+		{"elevated:", 0},
+		{"suppressed:quiet-db", 1},
+		{"emitted:quiet-db", 1},
+		{"elevated:quiet-db", 0},
+	}
+	for _, tt := range tests {
+		if got := collector.count(tt.event); got != tt.want {
+			t.Errorf("Expected %d %q events, got %d", tt.want, tt.event, got)
+		}
+	}
+}
+
+func TestWithMetrics_FilteringDisabled(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	collector := newFakeCollector()
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithMetrics(collector))
+	handler.SetFilteringEnabled(false)
+
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "dropped", 0)
+	_ = handler.Handle(context.Background(), r)
+	r = slog.NewRecord(time.Now(), slog.LevelInfo, "emitted", 0)
+	_ = handler.Handle(context.Background(), r)
+
+	if got := collector.count("seen"); got != 2 {
+		t.Errorf("Expected 2 seen events, got %d", got)
+	}
+	if got := collector.count("emitted:"); got != 1 {
+		t.Errorf("Expected 1 emitted event, got %d", got)
+	}
+	if got := collector.count("suppressed:"); got != 1 {
+		t.Errorf("Expected 1 suppressed event, got %d", got)
+	}
+}
+
+func TestWithMetrics_Default(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	if _, ok := handler.metrics.(NopMetricsCollector); !ok {
+		t.Errorf("Expected NopMetricsCollector by default, got %T", handler.metrics)
+	}
+	slog.New(handler).Info("test") // Must not panic
+}
@@ -0,0 +1,72 @@
+package logfilter
+
+import (
+	"log/slog"
+)
+
+// DefaultComponentKey is the attribute key used to name a logger's subsystem
+// when WithComponentKey is not given.
+const DefaultComponentKey = "component"
+
+// ComponentType is the filter type that matches the logger's component, i.e.
+// the value of the attribute named by WithComponentKey. With the default key
+// it behaves exactly like an attribute filter on "component".
+const ComponentType = "component"
+
+// WithComponentKey sets the attribute key that names a logger's subsystem,
+// such as "component", "logger" or "subsystem" (default "component").
+//
+// Filters with Type ComponentType match against this attribute whatever its
+// key, so filter configuration stays the same across codebases with different
+// conventions. The component is usually attached once via Handler.WithComponent
+// or Component, and the handler caches it so filters don't scan attributes.
+func WithComponentKey(key string) Option {
+	return func(o *options) {
+		o.componentKey = key
+	}
+}
+
+// ComponentKey returns the attribute key the handler uses for components.
+func (h *Handler) ComponentKey() string {
+	return h.componentKey
+}
+
+// WithComponent returns a handler whose records carry the given component
+// name under the handler's component key.
+func (h *Handler) WithComponent(name string) slog.Handler {
+	return h.WithAttrs([]slog.Attr{slog.String(h.componentKey, name)})
+}
+
+// Component returns a logger from the global handler scoped to the named
+// component, e.g. Component("auth").Debug(...). Filters with Type
+// ComponentType and Pattern "auth" then control its verbosity.
+// If no global handler exists yet, it returns slog.Default() with the
+// component attribute added under DefaultComponentKey.
+func Component(name string) *slog.Logger {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h == nil {
+		return slog.Default().With(DefaultComponentKey, name)
+	}
+	return slog.New(h.WithComponent(name))
+}
+
+// IsComponentFilter returns true if this filter matches the logger's component.
+func (f *LogFilter) IsComponentFilter() bool {
+	return f.Type == ComponentType
+}
+
+// componentValue returns the record's component: a per-call attribute takes
+// precedence over the one cached from WithAttrs, matching attribute filters.
+func (h *Handler) componentValue(r slog.Record) (string, bool) {
+	value, found := h.component, h.hasComponent
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == h.componentKey {
+			value, found = attrValueToString(a.Value), true
+		}
+		return true
+	})
+	return value, found
+}
@@ -0,0 +1,112 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_ComponentFilter_DefaultKey(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: ComponentType, Pattern: "auth", Level: "debug", Enabled: true},
+	})
+
+	if handler.ComponentKey() != DefaultComponentKey {
+		t.Errorf("Expected default component key %q, got %q", DefaultComponentKey, handler.ComponentKey())
+	}
+
+	slog.New(handler.WithComponent("auth")).Debug("login attempt")
+	if !strings.Contains(buf.String(), "component=auth") {
+		t.Errorf("Expected auth component debug to be emitted, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	slog.New(handler.WithComponent("billing")).Debug("invoice")
+	if buf.Len() > 0 {
+		t.Errorf("Expected other component debug to be suppressed, got: %s", buf.String())
+	}
+
+	// Equivalent to a plain attribute on the default key
+	buf.Reset()
+	slog.New(handler).Debug("inline", "component", "auth")
+	if buf.Len() == 0 {
+		t.Error("Expected per-call component attribute to match")
+	}
+}
+
+func TestHandler_ComponentFilter_CustomKey(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithComponentKey("logger"))
+	handler.SetFilters([]LogFilter{
+		{Type: ComponentType, Pattern: "db.*", Level: "debug", Enabled: true},
+	})
+
+	logger := slog.New(handler.WithComponent("db.pool"))
+	logger.Debug("acquired")
+	if !strings.Contains(buf.String(), "logger=db.pool") {
+		t.Errorf("Expected component under custom key to be emitted, got: %s", buf.String())
+	}
+
+	// An attribute literally named "component" is not the component with a custom key
+	buf.Reset()
+	slog.New(handler).Debug("other", "component", "db.pool")
+	if buf.Len() > 0 {
+		t.Errorf("Expected attribute on non-component key not to match, got: %s", buf.String())
+	}
+
+	// Per-call attribute overrides the cached one
+	buf.Reset()
+	logger.Debug("override", "logger", "http")
+	if buf.Len() > 0 {
+		t.Errorf("Expected per-call component to override cached one, got: %s", buf.String())
+	}
+}
+
+func TestHandler_ComponentCachedThroughGroups(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: ComponentType, Pattern: "auth", Level: "debug", Enabled: true},
+	})
+
+	slog.New(handler.WithComponent("auth")).WithGroup("req").With("id", 1).Debug("nested")
+	if buf.Len() == 0 {
+		t.Error("Expected component to be retained through WithGroup/WithAttrs")
+	}
+}
+
+func TestComponent_Global(t *testing.T) {
+	var buf bytes.Buffer
+	New(WithLevel(slog.LevelInfo), WithFormat("text"), WithOutput(&buf), WithSource(false))
+	SetFilters([]LogFilter{
+		{Type: ComponentType, Pattern: "auth", Level: "debug", Enabled: true},
+	})
+	defer ClearFilters()
+
+	Component("auth").Debug("scoped")
+	if !strings.Contains(buf.String(), "component=auth") {
+		t.Errorf("Expected global component logger to be filtered, got: %s", buf.String())
+	}
+}
+
+func TestLogFilter_IsComponentFilter(t *testing.T) {
+	if !(&LogFilter{Type: ComponentType}).IsComponentFilter() {
+		t.Error("Expected component type to be a component filter")
+	}
+	if (&LogFilter{Type: "job_id"}).IsComponentFilter() {
+		t.Error("Expected attribute type not to be a component filter")
+	}
+}
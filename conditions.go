@@ -0,0 +1,108 @@
+package logfilter
+
+// Values for LogFilter.Match.
+const (
+	ConditionsAll = "all" // Every condition must match (the default)
+	ConditionsAny = "any" // At least one condition must match
+)
+
+// Condition is one test of a compound filter (see LogFilter.Conditions). Type
+// and Pattern have the same meaning as on LogFilter, including the special
+// types such as "context:key" and "source:file".
+type Condition struct {
+	Type       string `json:"type" yaml:"type" toml:"type"`
+	Pattern    string `json:"pattern" yaml:"pattern" toml:"pattern"`
+	MatchMode  string `json:"match_mode,omitempty" yaml:"match_mode,omitempty" toml:"match_mode,omitempty"`    // As LogFilter.MatchMode
+	MatchScope string `json:"match_scope,omitempty" yaml:"match_scope,omitempty" toml:"match_scope,omitempty"` // As LogFilter.MatchScope
+}
+
+// prepareConditions compiles the filter's conditions into f.conditions,
+// with the filter's own Type and Pattern, if it has a Type, as the first.
+// Filters without Conditions are left with none.
+func (f *LogFilter) prepareConditions() {
+	f.conditions = nil
+	if len(f.Conditions) == 0 {
+		return
+	}
+
+	conds := make([]LogFilter, 0, len(f.Conditions)+1)
+	if f.Type != "" {
+		conds = append(conds, LogFilter{Type: f.Type, Pattern: f.Pattern, Negate: f.Negate, Regex: f.Regex, Numeric: f.Numeric, MatchMode: f.MatchMode, MatchScope: f.MatchScope})
+	}
+	for _, c := range f.Conditions {
+		conds = append(conds, LogFilter{Type: c.Type, Pattern: c.Pattern, MatchMode: c.MatchMode, MatchScope: c.MatchScope})
+	}
+	for i := range conds {
+		conds[i].prepare()
+	}
+	f.conditions = conds
+}
+
+// matchAnyCondition reports whether the filter combines its conditions with
+// "any" rather than "all".
+func (f *LogFilter) matchAnyCondition() bool {
+	return f.Match == ConditionsAny
+}
+
+// needsSource reports whether matching f needs the record's source location.
+func (f *LogFilter) needsSource() bool {
+	if len(f.conditions) == 0 {
+		return f.kind == filterKindSourceFile || f.kind == filterKindSourceFunction || f.kind == filterKindSourcePackage
+	}
+	for i := range f.conditions {
+		if f.conditions[i].needsSource() {
+			return true
+		}
+	}
+	return false
+}
+
+// contextOnly reports whether matching f needs only the record's context, so
+// that it can be evaluated in Enabled.
+func (f *LogFilter) contextOnly() bool {
+	if len(f.conditions) == 0 {
+		return f.kind == filterKindContext
+	}
+	for i := range f.conditions {
+		if !f.conditions[i].contextOnly() {
+			return false
+		}
+	}
+	return true
+}
+
+// matchConditions reports whether the record satisfies f's conditions,
+// returning the value matched by the first condition that matched.
+func (v *recordView) matchConditions(f *LogFilter) (string, bool) {
+	anyMode := f.matchAnyCondition()
+	var first string
+	matched := false
+	for i := range f.conditions {
+		value, ok := v.matchOne(&f.conditions[i])
+		switch {
+		case ok && anyMode:
+			return value, true
+		case !ok && !anyMode:
+			return "", false
+		case ok && !matched:
+			first, matched = value, true
+		}
+	}
+	return first, matched
+}
+
+// conditionsMatchAll reports whether a compound filter matches every value of
+// its conditions' keys: all of its conditions are catch-alls, or with "any",
+// at least one is.
+func (f *LogFilter) conditionsMatchAll() bool {
+	anyMode := f.matchAnyCondition()
+	if f.Type != "" && f.patternMatchAll() == anyMode {
+		return anyMode
+	}
+	for _, c := range f.Conditions {
+		if (c.MatchMode == "" && NewMatcher(c.Pattern).kind == matchAll) == anyMode {
+			return anyMode
+		}
+	}
+	return !anyMode
+}
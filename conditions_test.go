@@ -0,0 +1,152 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_Conditions(t *testing.T) {
+	type ctxKey string
+	const envKey ctxKey = "env"
+
+	RegisterContextExtractor("env", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(envKey).(string)
+		return v, ok
+	})
+	defer ClearContextExtractors()
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	logger := slog.New(handler)
+
+	filters := map[string]LogFilter{
+		// Primary Type/Pattern plus a condition, combined with "all"
+		"all": {Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true,
+			Conditions: []Condition{{Type: "env", Pattern: "prod"}}},
+		// Conditions only, mixing an attribute and a context value
+		"all mixed": {Level: "debug", Enabled: true,
+			Conditions: []Condition{{Type: "job_id", Pattern: "job_*"}, {Type: "context:env", Pattern: "prod"}}},
+		"any mixed": {Level: "debug", Enabled: true, Match: ConditionsAny,
+			Conditions: []Condition{{Type: "job_id", Pattern: "job_*"}, {Type: "context:env", Pattern: "prod"}}},
+	}
+
+	tests := []struct {
+		filter string
+		name   string
+		ctxEnv string
+		args   []any
+		emits  bool
+	}{
+		{"all", "both match", "", []any{"job_id", "job_1", "env", "prod"}, true},
+		{"all", "only primary matches", "", []any{"job_id", "job_1", "env", "dev"}, false},
+		{"all", "only condition matches", "", []any{"job_id", "task_1", "env", "prod"}, false},
+		{"all", "condition absent", "", []any{"job_id", "job_1"}, false},
+
+		{"all mixed", "attribute and context match", "prod", []any{"job_id", "job_1"}, true},
+		{"all mixed", "context mismatch", "dev", []any{"job_id", "job_1"}, false},
+		{"all mixed", "context absent", "", []any{"job_id", "job_1"}, false},
+		{"all mixed", "env attribute is not context", "", []any{"job_id", "job_1", "env", "prod"}, false},
+
+		{"any mixed", "attribute only", "", []any{"job_id", "job_1"}, true},
+		{"any mixed", "context only", "prod", nil, true},
+		{"any mixed", "both", "prod", []any{"job_id", "job_1"}, true},
+		{"any mixed", "neither", "dev", []any{"job_id", "task_1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter+"/"+tt.name, func(t *testing.T) {
+			handler.SetFilters([]LogFilter{filters[tt.filter]})
+			buf.Reset()
+			ctx := context.Background()
+			if tt.ctxEnv != "" {
+				ctx = context.WithValue(ctx, envKey, tt.ctxEnv)
+			}
+			logger.DebugContext(ctx, "step", tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Conditions_FirstMatchWins(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "error", Enabled: true,
+			Conditions: []Condition{{Type: "env", Pattern: "prod"}}},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("prod", "job_id", "job_1", "env", "prod")
+	if buf.Len() > 0 {
+		t.Errorf("Expected compound filter to win for prod, got: %s", buf.String())
+	}
+	logger.Debug("dev", "job_id", "job_1", "env", "dev")
+	if buf.Len() == 0 {
+		t.Error("Expected simple filter to apply when the compound one doesn't match")
+	}
+}
+
+func TestHandler_Conditions_SourceCondition(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true,
+			Conditions: []Condition{{Type: SourceFilePrefix, Pattern: "*conditions_test.go"}}},
+	})
+	if !handler.hasSourceFilters {
+		t.Fatal("Expected a source condition to enable source extraction")
+	}
+
+	var buf bytes.Buffer
+	handler.SetInnerHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	slog.New(handler).Debug("here", "job_id", "job_1")
+	if buf.Len() == 0 {
+		t.Error("Expected record from this file to match the source condition")
+	}
+}
+
+func TestLogFilter_Conditions_IsMatchAll(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter LogFilter
+		want   bool
+	}{
+		{"all catch-all", LogFilter{Type: "a", Pattern: "*", Conditions: []Condition{{Type: "b", Pattern: "*"}}}, true},
+		{"all restricted", LogFilter{Type: "a", Pattern: "*", Conditions: []Condition{{Type: "b", Pattern: "x"}}}, false},
+		{"all primary restricted", LogFilter{Type: "a", Pattern: "x", Conditions: []Condition{{Type: "b", Pattern: "*"}}}, false},
+		{"all without primary", LogFilter{Conditions: []Condition{{Type: "b", Pattern: "*"}}}, true},
+		{"any one catch-all", LogFilter{Match: ConditionsAny, Type: "a", Pattern: "x", Conditions: []Condition{{Type: "b", Pattern: "*"}}}, true},
+		{"any none catch-all", LogFilter{Match: ConditionsAny, Type: "a", Pattern: "x", Conditions: []Condition{{Type: "b", Pattern: "y"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.IsMatchAll(); got != tt.want {
+				t.Errorf("Expected IsMatchAll=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeriveFilterID_Conditions(t *testing.T) {
+	simple := LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug"}
+	all := simple
+	all.Conditions = []Condition{{Type: "env", Pattern: "prod"}}
+	anyMode := all
+	anyMode.Match = ConditionsAny
+
+	ids := map[string]bool{DeriveFilterID(simple): true, DeriveFilterID(all): true, DeriveFilterID(anyMode): true}
+	if len(ids) != 3 {
+		t.Errorf("Expected conditions and match to change the derived ID, got %v", ids)
+	}
+}
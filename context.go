@@ -2,6 +2,10 @@ package logfilter
 
 import (
 	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -9,10 +13,30 @@ import (
 // It should return the value and true if found, or empty string and false if not.
 type ContextExtractor func(ctx context.Context) (string, bool)
 
+// ContextValueExtractor is like ContextExtractor but returns a typed value,
+// such as an int, so that it isn't stringified by the extractor.
+type ContextValueExtractor func(ctx context.Context) (slog.Value, bool)
+
+// contextExtractorEntry holds the extractors registered for a key. Either
+// may be nil; value is preferred when both are set.
+type contextExtractorEntry struct {
+	str   ContextExtractor
+	value ContextValueExtractor
+}
+
 // contextExtractors holds registered context extractors by key.
+// contextExtractorSeq records the order keys were first registered in, so
+// listings are deterministic.
 var (
-	contextExtractors     = make(map[string]ContextExtractor)
-	contextExtractorsLock sync.RWMutex
+	contextExtractors       = make(map[string]contextExtractorEntry)
+	contextExtractorSeq     = make(map[string]uint64)
+	nextContextExtractorSeq uint64
+	contextExtractorsLock   sync.RWMutex
+
+	// warnedContextKeys records the unregistered keys warnUnregisteredContextKeys
+	// has warned about; registering a key removes it. Guarded by
+	// contextExtractorsLock.
+	warnedContextKeys = make(map[string]bool)
 )
 
 // RegisterContextExtractor registers a function to extract a value from context
@@ -29,26 +53,110 @@ var (
 //	    return "", false
 //	})
 func RegisterContextExtractor(key string, extractor ContextExtractor) {
+	updateContextExtractor(key, func(e *contextExtractorEntry) { e.str = extractor })
+}
+
+// RegisterContextExtractorValue registers a function extracting a typed value
+// from context for the given key, e.g. an int for Numeric filters or a
+// slog.LogValuer for a UUID type. It takes precedence over an extractor
+// registered for the key with RegisterContextExtractor. Values are converted
+// to strings only for matching.
+//
+// Example:
+//
+//	logfilter.RegisterContextExtractorValue("retry", func(ctx context.Context) (slog.Value, bool) {
+//	    n, ok := ctx.Value(RetryKey).(int)
+//	    return slog.IntValue(n), ok
+//	})
+func RegisterContextExtractorValue(key string, extractor ContextValueExtractor) {
+	updateContextExtractor(key, func(e *contextExtractorEntry) { e.value = extractor })
+}
+
+// RegisterContextExtractorChain registers, for the given key, extractors
+// tried in order until one finds a value, for a value that different
+// middleware store under different context keys. It replaces any extractor
+// registered for the key with RegisterContextExtractor; a typed extractor
+// registered with RegisterContextExtractorValue still takes precedence. Nil
+// extractors are skipped.
+//
+// Example:
+//
+//	logfilter.RegisterContextExtractorChain("tenant", fromAuthMiddleware, fromGatewayHeader)
+func RegisterContextExtractorChain(key string, extractors ...ContextExtractor) {
+	chain := slices.DeleteFunc(slices.Clone(extractors), func(e ContextExtractor) bool { return e == nil })
+	RegisterContextExtractor(key, func(ctx context.Context) (string, bool) {
+		for _, extractor := range chain {
+			if v, ok := extractor(ctx); ok {
+				return v, true
+			}
+		}
+		return "", false
+	})
+}
+
+// updateContextExtractor applies update to key's entry, registering the key
+// if it is new.
+func updateContextExtractor(key string, update func(*contextExtractorEntry)) {
 	contextExtractorsLock.Lock()
 	defer contextExtractorsLock.Unlock()
-	contextExtractors[key] = extractor
+	delete(warnedContextKeys, key)
+	e, ok := contextExtractors[key]
+	if !ok {
+		contextExtractorSeq[key] = nextContextExtractorSeq
+		nextContextExtractorSeq++
+	}
+	update(&e)
+	contextExtractors[key] = e
 }
 
-// UnregisterContextExtractor removes a context extractor for the given key.
+// UnregisterContextExtractor removes the context extractors, string and
+// typed, for the given key.
 func UnregisterContextExtractor(key string) {
 	contextExtractorsLock.Lock()
 	defer contextExtractorsLock.Unlock()
 	delete(contextExtractors, key)
+	delete(contextExtractorSeq, key)
 }
 
-// GetContextExtractor returns the extractor for the given key, or nil if not registered.
+// GetContextExtractor returns the extractor for the given key, or nil if not
+// registered. For a key with a typed extractor, the returned extractor
+// converts its value to a string as filters do.
 func GetContextExtractor(key string) ContextExtractor {
 	contextExtractorsLock.RLock()
-	defer contextExtractorsLock.RUnlock()
-	return contextExtractors[key]
+	e := contextExtractors[key]
+	contextExtractorsLock.RUnlock()
+	if e.value == nil {
+		return e.str
+	}
+	return func(ctx context.Context) (string, bool) {
+		v, ok := e.value(ctx)
+		if !ok {
+			return "", false
+		}
+		return attrValueToString(v.Resolve()), true
+	}
 }
 
-// extractFromContext tries to extract a value from context using registered extractors.
+// GetContextExtractorValue returns the typed extractor for the given key, or
+// nil if none is registered. A key with only a string extractor returns one
+// wrapping its value with slog.StringValue.
+func GetContextExtractorValue(key string) ContextValueExtractor {
+	contextExtractorsLock.RLock()
+	e := contextExtractors[key]
+	contextExtractorsLock.RUnlock()
+	if e.value != nil || e.str == nil {
+		return e.value
+	}
+	return func(ctx context.Context) (slog.Value, bool) {
+		s, ok := e.str(ctx)
+		return slog.StringValue(s), ok
+	}
+}
+
+// extractFromContext tries to extract a value from context using registered
+// extractors, preferring a typed extractor and converting its value to a
+// string for matching, and falling back to a map extractor for a "name.label"
+// key (see RegisterContextMapExtractor).
 func extractFromContext(ctx context.Context, key string) (string, bool) {
 	if ctx == nil {
 		return "", false
@@ -56,21 +164,61 @@ func extractFromContext(ctx context.Context, key string) (string, bool) {
 
 	extractor := GetContextExtractor(key)
 	if extractor == nil {
-		return "", false
+		return extractLabelFromContext(ctx, key)
 	}
 
 	return extractor(ctx)
 }
 
-// ClearContextExtractors removes all registered context extractors.
-// Useful for testing.
+// ReplaceContextExtractors atomically replaces every registered context
+// extractor, including typed ones, with the given set. Concurrent lookups see
+// either the old or the new registry, never a mix, which avoids the window
+// that an UnregisterContextExtractor/RegisterContextExtractor sequence would
+// open.
+//
+// Since a map has no order, ContextExtractorKeys lists the new keys sorted
+// alphabetically until further keys are registered.
+//
+// Map extractors (RegisterContextMapExtractor) are registered by name rather
+// than by key and are kept; remove them with UnregisterContextMapExtractor.
+// ClearContextExtractors removes both.
+func ReplaceContextExtractors(extractors map[string]ContextExtractor) {
+	keys := make([]string, 0, len(extractors))
+	for k := range extractors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	next := make(map[string]contextExtractorEntry, len(extractors))
+	seq := make(map[string]uint64, len(extractors))
+	for i, k := range keys {
+		next[k] = contextExtractorEntry{str: extractors[k]}
+		seq[k] = uint64(i)
+	}
+
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	contextExtractors = next
+	contextExtractorSeq = seq
+	nextContextExtractorSeq = uint64(len(keys))
+	warnedContextKeys = make(map[string]bool)
+}
+
+// ClearContextExtractors removes all registered context extractors,
+// including map extractors. Useful for testing.
 func ClearContextExtractors() {
 	contextExtractorsLock.Lock()
 	defer contextExtractorsLock.Unlock()
-	contextExtractors = make(map[string]ContextExtractor)
+	contextExtractors = make(map[string]contextExtractorEntry)
+	contextExtractorSeq = make(map[string]uint64)
+	nextContextExtractorSeq = 0
+	contextMapExtractors = make(map[string]ContextMapExtractor)
+	warnedContextKeys = make(map[string]bool)
 }
 
-// ContextExtractorKeys returns the keys of all registered context extractors.
+// ContextExtractorKeys returns the keys of all registered context extractors
+// in the order they were first registered. Re-registering an existing key
+// keeps its original position.
 func ContextExtractorKeys() []string {
 	contextExtractorsLock.RLock()
 	defer contextExtractorsLock.RUnlock()
@@ -79,5 +227,42 @@ func ContextExtractorKeys() []string {
 	for k := range contextExtractors {
 		keys = append(keys, k)
 	}
+	sort.Slice(keys, func(i, j int) bool {
+		return contextExtractorSeq[keys[i]] < contextExtractorSeq[keys[j]]
+	})
 	return keys
 }
+
+// warnUnregisteredContextKeys logs a warning for each "context:" filter or
+// condition whose key has no registered extractor, since such filters never
+// match. Each key is warned about once, until an extractor is registered for
+// it. Like warnInvalid, it must not be called with filtersLock held.
+func warnUnregisteredContextKeys(filters []LogFilter) {
+	var keys []string
+	var collect func(typ string)
+	collect = func(typ string) {
+		key, ok := strings.CutPrefix(typ, ContextPrefix)
+		if !ok || key == "" {
+			return
+		}
+		if e := contextExtractors[key]; e.str != nil || e.value != nil || mapExtractorFor(key) != nil || warnedContextKeys[key] {
+			return
+		}
+		warnedContextKeys[key] = true
+		keys = append(keys, key)
+	}
+
+	contextExtractorsLock.Lock()
+	for _, f := range filters {
+		collect(f.Type)
+		for _, c := range f.Conditions {
+			collect(c.Type)
+		}
+	}
+	contextExtractorsLock.Unlock()
+
+	for _, key := range keys {
+		slog.Default().Warn("logfilter: no context extractor registered, context filter will never match",
+			"type", ContextPrefix+key)
+	}
+}
@@ -1,7 +1,13 @@
 package logfilter
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -63,7 +69,7 @@ func TestContextExtractor_Keys(t *testing.T) {
 		t.Errorf("Expected 3 keys, got %d", len(keys))
 	}
 
-	// Check all keys are present (order not guaranteed)
+	// Check all keys are present
 	keyMap := make(map[string]bool)
 	for _, k := range keys {
 		keyMap[k] = true
@@ -76,6 +82,34 @@ func TestContextExtractor_Keys(t *testing.T) {
 	}
 }
 
+func TestContextExtractor_KeysRegistrationOrder(t *testing.T) {
+	defer ClearContextExtractors()
+
+	noop := func(ctx context.Context) (string, bool) { return "", false }
+	want := []string{"zeta", "alpha", "mid", "beta", "omega"}
+	for _, k := range want {
+		RegisterContextExtractor(k, noop)
+	}
+
+	// Order is stable across repeated calls
+	for i := 0; i < 20; i++ {
+		keys := ContextExtractorKeys()
+		if strings.Join(keys, ",") != strings.Join(want, ",") {
+			t.Fatalf("Expected keys in registration order %v, got %v", want, keys)
+		}
+	}
+
+	// Re-registering keeps the original position; unregistered keys re-register at the end
+	RegisterContextExtractor("alpha", noop)
+	UnregisterContextExtractor("mid")
+	RegisterContextExtractor("mid", noop)
+
+	want = []string{"zeta", "alpha", "beta", "omega", "mid"}
+	if keys := ContextExtractorKeys(); strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected keys %v, got %v", want, keys)
+	}
+}
+
 func TestContextExtractor_ExtractFromContext(t *testing.T) {
 	defer ClearContextExtractors()
 
@@ -131,3 +165,283 @@ func TestContextExtractor_Clear(t *testing.T) {
 		t.Error("Expected 0 extractors after clear")
 	}
 }
+
+func TestContextExtractor_Replace(t *testing.T) {
+	defer ClearContextExtractors()
+
+	RegisterContextExtractor("old", func(ctx context.Context) (string, bool) { return "old", true })
+
+	ReplaceContextExtractors(map[string]ContextExtractor{
+		"tenant":  func(ctx context.Context) (string, bool) { return "acme", true },
+		"user_id": func(ctx context.Context) (string, bool) { return "u1", true },
+	})
+
+	if GetContextExtractor("old") != nil {
+		t.Error("Expected previous extractors to be removed")
+	}
+	if v, ok := extractFromContext(context.Background(), "tenant"); !ok || v != "acme" {
+		t.Errorf("Expected (acme, true), got (%s, %v)", v, ok)
+	}
+	if keys := ContextExtractorKeys(); strings.Join(keys, ",") != "tenant,user_id" {
+		t.Errorf("Expected sorted keys after replace, got %v", keys)
+	}
+
+	// Later registrations are ordered after the replaced set
+	RegisterContextExtractor("a_late", func(ctx context.Context) (string, bool) { return "", false })
+	if keys := ContextExtractorKeys(); strings.Join(keys, ",") != "tenant,user_id,a_late" {
+		t.Errorf("Expected late registration last, got %v", keys)
+	}
+
+	// Caller mutations to the map don't leak into the registry
+	m := map[string]ContextExtractor{"k": func(ctx context.Context) (string, bool) { return "", false }}
+	ReplaceContextExtractors(m)
+	delete(m, "k")
+	if GetContextExtractor("k") == nil {
+		t.Error("Expected registry to be independent of the caller's map")
+	}
+}
+
+func TestContextExtractor_ReplaceKeepsMapExtractors(t *testing.T) {
+	defer ClearContextExtractors()
+
+	RegisterContextMapExtractor("label", func(ctx context.Context) map[string]string {
+		return map[string]string{"team": "core"}
+	})
+	ReplaceContextExtractors(map[string]ContextExtractor{
+		"tenant": func(ctx context.Context) (string, bool) { return "acme", true },
+	})
+	if v, ok := extractFromContext(context.Background(), "label.team"); !ok || v != "core" {
+		t.Errorf("Expected the map extractor kept, got (%s, %v)", v, ok)
+	}
+
+	ClearContextExtractors()
+	if _, ok := extractFromContext(context.Background(), "label.team"); ok {
+		t.Error("Expected ClearContextExtractors to remove map extractors")
+	}
+}
+
+func TestContextExtractor_ReplaceConcurrentWithHandle(t *testing.T) {
+	defer ClearContextExtractors()
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	sets := []map[string]ContextExtractor{
+		{"tenant": func(ctx context.Context) (string, bool) { return "acme", true }},
+		{"tenant": func(ctx context.Context) (string, bool) { return "other", true }, "user": nil},
+		{},
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					logger.DebugContext(context.Background(), "tick")
+					_ = ContextExtractorKeys()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 500; i++ {
+		ReplaceContextExtractors(sets[i%len(sets)])
+	}
+	close(stop)
+	wg.Wait()
+}
+
+type retryKey struct{}
+
+func TestRegisterContextExtractorValue(t *testing.T) {
+	defer ClearContextExtractors()
+
+	RegisterContextExtractorValue("retry", func(ctx context.Context) (slog.Value, bool) {
+		n, ok := ctx.Value(retryKey{}).(int)
+		return slog.IntValue(n), ok
+	})
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "context:retry", Pattern: ">=3", Numeric: true, Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		emits bool
+	}{
+		{"at threshold", context.WithValue(context.Background(), retryKey{}, 3), true},
+		{"above threshold", context.WithValue(context.Background(), retryKey{}, 10), true},
+		{"below threshold", context.WithValue(context.Background(), retryKey{}, 2), false},
+		{"absent", context.Background(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.DebugContext(tt.ctx, "retrying")
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestRegisterContextExtractorValue_Precedence(t *testing.T) {
+	defer ClearContextExtractors()
+
+	RegisterContextExtractor("id", func(ctx context.Context) (string, bool) { return "string", true })
+	RegisterContextExtractorValue("id", func(ctx context.Context) (slog.Value, bool) { return slog.Int64Value(42), true })
+
+	if got, ok := extractFromContext(context.Background(), "id"); !ok || got != "42" {
+		t.Errorf("Expected typed extractor to take precedence, got (%q, %v)", got, ok)
+	}
+	if v, ok := GetContextExtractorValue("id")(context.Background()); !ok || v.Kind() != slog.KindInt64 {
+		t.Errorf("Expected typed value, got (%v, %v)", v, ok)
+	}
+	if keys := ContextExtractorKeys(); len(keys) != 1 {
+		t.Errorf("Expected one key for both extractors, got %v", keys)
+	}
+
+	// A string-only key is wrapped as a string value
+	RegisterContextExtractor("name", func(ctx context.Context) (string, bool) { return "alice", true })
+	if v, ok := GetContextExtractorValue("name")(context.Background()); !ok || v.String() != "alice" {
+		t.Errorf("Expected string value alice, got (%v, %v)", v, ok)
+	}
+	if GetContextExtractorValue("missing") != nil {
+		t.Error("Expected nil typed extractor for unregistered key")
+	}
+
+	UnregisterContextExtractor("id")
+	if GetContextExtractor("id") != nil || GetContextExtractorValue("id") != nil {
+		t.Error("Expected both extractors to be unregistered")
+	}
+}
+
+func TestWarnUnregisteredContextKeys(t *testing.T) {
+	defer ClearContextExtractors()
+	ClearContextExtractors()
+
+	var warnings bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&warnings, nil)))
+	defer slog.SetDefault(prev)
+
+	RegisterContextExtractor("request_id", func(ctx context.Context) (string, bool) { return "", false })
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+
+	filters := []LogFilter{
+		{Type: "context:tenant_id", Pattern: "acme", Level: "debug", Enabled: true},
+		{Type: "context:tenant_id", Pattern: "globex", Level: "debug", Enabled: true},
+		{Type: "context:request_id", Pattern: "req_*", Level: "debug", Enabled: true},
+		{Type: "any:user", Pattern: "admin", Level: "debug", Enabled: true}, // May match the attribute
+		{Conditions: []Condition{{Type: "context:region", Pattern: "eu"}}, Level: "debug", Enabled: true},
+	}
+	handler.SetFilters(filters)
+	handler.SetFilters(filters)
+	handler.AddFilter(LogFilter{Type: "context:tenant_id", Pattern: "initech", Level: "debug", Enabled: true})
+
+	out := warnings.String()
+	if n := strings.Count(out, "type=context:tenant_id"); n != 1 {
+		t.Errorf("Expected exactly one warning for tenant_id, got %d: %s", n, out)
+	}
+	if n := strings.Count(out, "type=context:region"); n != 1 {
+		t.Errorf("Expected exactly one warning for the region condition, got %d: %s", n, out)
+	}
+	if strings.Contains(out, "request_id") || strings.Contains(out, "user") {
+		t.Errorf("Expected no warning for registered or any: keys, got: %s", out)
+	}
+
+	// Registering the key clears it; a later unregistration warns again
+	warnings.Reset()
+	RegisterContextExtractor("tenant_id", func(ctx context.Context) (string, bool) { return "", false })
+	handler.SetFilters(filters)
+	if strings.Contains(warnings.String(), "tenant_id") {
+		t.Errorf("Expected no warning once registered, got: %s", warnings.String())
+	}
+	UnregisterContextExtractor("tenant_id")
+	handler.SetFilters(filters)
+	if n := strings.Count(warnings.String(), "type=context:tenant_id"); n != 1 {
+		t.Errorf("Expected one new warning after unregistering, got %d: %s", n, warnings.String())
+	}
+}
+
+type authTenantKey struct{}
+type gatewayTenantKey struct{}
+
+func TestRegisterContextExtractorChain(t *testing.T) {
+	defer ClearContextExtractors()
+
+	var calls []string
+	fromKey := func(name string, key any) ContextExtractor {
+		return func(ctx context.Context) (string, bool) {
+			calls = append(calls, name)
+			s, ok := ctx.Value(key).(string)
+			return s, ok
+		}
+	}
+	RegisterContextExtractorChain("tenant", fromKey("auth", authTenantKey{}), nil, fromKey("gateway", gatewayTenantKey{}))
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	bothCtx := context.WithValue(context.WithValue(context.Background(), authTenantKey{}, "acme"), gatewayTenantKey{}, "other")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		emits     bool
+		wantCalls []string
+	}{
+		{"first hits", context.WithValue(context.Background(), authTenantKey{}, "acme"), true, []string{"auth"}},
+		{"first misses, second hits", context.WithValue(context.Background(), gatewayTenantKey{}, "acme"), true, []string{"auth", "gateway"}},
+		{"first wins", bothCtx, true, []string{"auth"}},
+		{"all miss", context.Background(), false, []string{"auth", "gateway"}},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		logger.DebugContext(tt.ctx, "request")
+		if emitted := buf.Len() > 0; emitted != tt.emits {
+			t.Errorf("%s: expected emitted=%v, got output: %s", tt.name, tt.emits, buf.String())
+		}
+
+		calls = nil
+		got, ok := extractFromContext(tt.ctx, "tenant")
+		if ok != tt.emits || (ok && got != "acme") {
+			t.Errorf("%s: expected (acme, %v), got (%q, %v)", tt.name, tt.emits, got, ok)
+		}
+		if !slices.Equal(calls, tt.wantCalls) {
+			t.Errorf("%s: expected extractors %v tried, got %v", tt.name, tt.wantCalls, calls)
+		}
+	}
+
+	if keys := ContextExtractorKeys(); !slices.Equal(keys, []string{"tenant"}) {
+		t.Errorf("Expected the chain registered under one key, got %v", keys)
+	}
+}
@@ -0,0 +1,144 @@
+package logfilter
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// ContextMapExtractor extracts a set of labels from context, such as a map of
+// request labels, for filters with type "context:name.label". It should
+// return nil if the context carries no labels.
+type ContextMapExtractor func(ctx context.Context) map[string]string
+
+// contextMapExtractors holds registered map extractors by name. Guarded by
+// contextExtractorsLock.
+var contextMapExtractors = make(map[string]ContextMapExtractor)
+
+// RegisterContextMapExtractor registers a function extracting a set of labels
+// from context under name, so that one extractor serves any number of
+// dynamic keys. A filter with type "context:name.label" matches the value of
+// that label, and one whose label is a glob, such as "context:name.*" or
+// "context:name.team_*", matches if the value of any label whose name
+// matches does (or with Negate, if none does). name must not contain a dot;
+// label names may. An extractor registered with RegisterContextExtractor for
+// the full key "name.label" takes precedence.
+//
+// Example:
+//
+//	logfilter.RegisterContextMapExtractor("label", func(ctx context.Context) map[string]string {
+//	    labels, _ := ctx.Value(LabelsKey).(map[string]string)
+//	    return labels
+//	})
+func RegisterContextMapExtractor(name string, extractor ContextMapExtractor) {
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	contextMapExtractors[name] = extractor
+	for key := range warnedContextKeys {
+		if n, _, ok := strings.Cut(key, "."); ok && n == name {
+			delete(warnedContextKeys, key)
+		}
+	}
+}
+
+// UnregisterContextMapExtractor removes the map extractor registered under
+// name.
+func UnregisterContextMapExtractor(name string) {
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	delete(contextMapExtractors, name)
+}
+
+// contextLabel splits a context key into a map extractor name and a label,
+// reporting false for a key without a dot.
+func contextLabel(key string) (name, label string, ok bool) {
+	name, label, ok = strings.Cut(key, ".")
+	return name, label, ok && name != ""
+}
+
+// isLabelWildcard reports whether key names its labels with a glob, such as
+// "label.*".
+func isLabelWildcard(key string) bool {
+	_, label, ok := contextLabel(key)
+	return ok && strings.ContainsAny(label, "*?[")
+}
+
+// mapExtractorFor returns the map extractor serving key, or nil. Must be
+// called with contextExtractorsLock held.
+func mapExtractorFor(key string) ContextMapExtractor {
+	if name, _, ok := contextLabel(key); ok {
+		return contextMapExtractors[name]
+	}
+	return nil
+}
+
+// extractLabelFromContext returns the value of the label key names from the
+// map extractor serving it.
+func extractLabelFromContext(ctx context.Context, key string) (string, bool) {
+	contextExtractorsLock.RLock()
+	extractor := mapExtractorFor(key)
+	contextExtractorsLock.RUnlock()
+	if extractor == nil {
+		return "", false
+	}
+
+	_, label, _ := contextLabel(key)
+	value, ok := extractor(ctx)[label]
+	return value, ok
+}
+
+// extractLabelsFromContext returns the values of the labels whose names
+// labels matches, ordered by label name, from the map extractor serving key.
+// It reports false if there are none.
+func extractLabelsFromContext(ctx context.Context, key string, labels Matcher) ([]string, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	contextExtractorsLock.RLock()
+	extractor := mapExtractorFor(key)
+	contextExtractorsLock.RUnlock()
+	if extractor == nil {
+		return nil, false
+	}
+
+	m := extractor(ctx)
+	names := make([]string, 0, len(m))
+	for name := range m {
+		if labels.Match(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = m[name]
+	}
+	return values, true
+}
+
+// matchContextLabels reports whether the labels f's wildcard context key
+// names match f's pattern, element-wise as for a slice attribute (see
+// matchElems), returning the matched value.
+func (v *recordView) matchContextLabels(f *LogFilter) (string, bool) {
+	values, ok := extractLabelsFromContext(v.ctx, f.contextKey, *f.ctxLabels)
+	if !ok {
+		return "", false
+	}
+	return f.matchElems(f.matcher, values, strings.Join(values, ","))
+}
+
+// lookupContext returns the value f's context key names: the first matching
+// label's for a wildcard key.
+func (v *recordView) lookupContext(f *LogFilter) (string, bool) {
+	if f.ctxLabels == nil {
+		return extractFromContext(v.ctx, f.contextKey)
+	}
+	values, ok := extractLabelsFromContext(v.ctx, f.contextKey, *f.ctxLabels)
+	if !ok {
+		return "", false
+	}
+	return values[0], true
+}
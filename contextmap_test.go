@@ -0,0 +1,89 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+type labelsKey struct{}
+
+func withLabels(labels map[string]string) context.Context {
+	return context.WithValue(context.Background(), labelsKey{}, labels)
+}
+
+func registerLabels(t *testing.T) {
+	RegisterContextMapExtractor("label", func(ctx context.Context) map[string]string {
+		labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+		return labels
+	})
+	t.Cleanup(func() { UnregisterContextMapExtractor("label") })
+}
+
+func TestContextMapExtractor_Wildcard(t *testing.T) {
+	registerLabels(t)
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		labels map[string]string
+		emits  bool
+	}{
+		{"any label matches", LogFilter{Type: "context:label.*", Pattern: "canary"}, map[string]string{"env": "prod", "track": "canary"}, true},
+		{"no label matches", LogFilter{Type: "context:label.*", Pattern: "canary"}, map[string]string{"env": "prod"}, false},
+		{"no labels", LogFilter{Type: "context:label.*", Pattern: "*"}, nil, false},
+		{"glob label name", LogFilter{Type: "context:label.team_*", Pattern: "payments"}, map[string]string{"team_owner": "payments"}, true},
+		{"glob label name excludes", LogFilter{Type: "context:label.team_*", Pattern: "payments"}, map[string]string{"owner": "payments"}, false},
+		{"single label", LogFilter{Type: "context:label.env", Pattern: "staging"}, map[string]string{"env": "staging"}, true},
+		{"single label other value", LogFilter{Type: "context:label.env", Pattern: "staging"}, map[string]string{"env": "prod", "x": "staging"}, false},
+		{"dotted label name", LogFilter{Type: "context:label.app.kubernetes.io/name", Pattern: "api"}, map[string]string{"app.kubernetes.io/name": "api"}, true},
+		{"negated", LogFilter{Type: "context:label.*", Pattern: "prod", Negate: true}, map[string]string{"env": "staging"}, true},
+		{"negated any matches", LogFilter{Type: "context:label.*", Pattern: "prod", Negate: true}, map[string]string{"env": "prod", "x": "y"}, false},
+		{"present", LogFilter{Type: "context:label.team_*", MatchMode: MatchPresent}, map[string]string{"team_a": ""}, true},
+		{"absent", LogFilter{Type: "context:label.team_*", MatchMode: MatchAbsent}, map[string]string{"env": "prod"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+			f := tt.filter
+			f.Level, f.Enabled = "debug", true
+			handler.SetFilters([]LogFilter{f})
+
+			slog.New(handler).DebugContext(withLabels(tt.labels), "step")
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestContextMapExtractor_DirectExtractorWins(t *testing.T) {
+	registerLabels(t)
+	RegisterContextExtractor("label.env", func(ctx context.Context) (string, bool) { return "direct", true })
+	defer UnregisterContextExtractor("label.env")
+
+	if v, ok := extractFromContext(withLabels(map[string]string{"env": "mapped"}), "label.env"); !ok || v != "direct" {
+		t.Errorf("Expected the key's own extractor to take precedence, got %q, %v", v, ok)
+	}
+	if v, ok := extractFromContext(withLabels(map[string]string{"zone": "eu"}), "label.zone"); !ok || v != "eu" {
+		t.Errorf("Expected the map extractor for other labels, got %q, %v", v, ok)
+	}
+}
+
+func TestContextMapExtractor_Unregistered(t *testing.T) {
+	if _, ok := extractLabelsFromContext(withLabels(map[string]string{"env": "prod"}), "nolabels.*", NewMatcher("*")); ok {
+		t.Error("Expected no values without a registered map extractor")
+	}
+
+	registerLabels(t)
+	UnregisterContextMapExtractor("label")
+	if _, ok := extractFromContext(withLabels(map[string]string{"env": "prod"}), "label.env"); ok {
+		t.Error("Expected no value once the map extractor is unregistered")
+	}
+}
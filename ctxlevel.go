@@ -0,0 +1,37 @@
+package logfilter
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextLevelKey is the context key for ContextWithLevel.
+type contextLevelKey struct{}
+
+// ContextWithLevel returns a copy of ctx carrying a level override for the
+// records logged with it, e.g. from a request's X-Debug-Level header. The
+// handler uses level as the minimum level for those records in place of the
+// global level and the level of any matching filter, so a request can ask
+// for debug logging, or quieten itself, without changing filters. Global
+// suppressions and the WithLevelFloor floor still apply, and the override is
+// ignored while filtering is disabled.
+//
+// Example:
+//
+//	if lvl := r.Header.Get("X-Debug-Level"); lvl != "" {
+//	    ctx = logfilter.ContextWithLevel(ctx, logfilter.ParseLevel(lvl))
+//	}
+//	slog.DebugContext(ctx, "emitted for this request only")
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, contextLevelKey{}, level)
+}
+
+// LevelFromContext returns the level override set by ContextWithLevel, if
+// any.
+func LevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(contextLevelKey{}).(slog.Level)
+	return level, ok
+}
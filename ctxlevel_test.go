@@ -0,0 +1,132 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelFromContext(t *testing.T) {
+	if _, ok := LevelFromContext(context.Background()); ok {
+		t.Error("Expected no level in a plain context")
+	}
+
+	ctx := ContextWithLevel(context.Background(), slog.LevelDebug)
+	if level, ok := LevelFromContext(ctx); !ok || level != slog.LevelDebug {
+		t.Errorf("Expected (DEBUG, true), got (%v, %v)", level, ok)
+	}
+
+	ctx = ContextWithLevel(ctx, slog.LevelWarn)
+	if level, ok := LevelFromContext(ctx); !ok || level != slog.LevelWarn {
+		t.Errorf("Expected the innermost override (WARN, true), got (%v, %v)", level, ok)
+	}
+}
+
+func TestHandler_ContextLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		override bool
+		ctxLevel slog.Level
+		filters  []LogFilter
+		record   slog.Level
+		want     bool
+	}{
+		{"no override uses global level", false, 0, nil, slog.LevelDebug, false},
+		{"debug override emits debug", true, slog.LevelDebug, nil, slog.LevelDebug, true},
+		{"info override suppresses debug", true, slog.LevelInfo, nil, slog.LevelDebug, false},
+		{"warn override suppresses info", true, slog.LevelWarn, nil, slog.LevelInfo, false},
+		{"warn override emits warn", true, slog.LevelWarn, nil, slog.LevelWarn, true},
+		{
+			"override wins over quieting filter",
+			true, slog.LevelDebug,
+			[]LogFilter{{Type: "component", Pattern: "db", Level: "error", Enabled: true}},
+			slog.LevelDebug,
+			true,
+		},
+		{
+			"override wins over verbose filter",
+			true, slog.LevelWarn,
+			[]LogFilter{{Type: "component", Pattern: "db", Level: "debug", Enabled: true}},
+			slog.LevelInfo,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+			handler := NewHandler(inner, level)
+			handler.SetFilters(tt.filters)
+
+			ctx := context.Background()
+			if tt.override {
+				ctx = ContextWithLevel(ctx, tt.ctxLevel)
+			}
+			slog.New(handler).Log(ctx, tt.record, "test", "component", "db")
+			if got := buf.Len() > 0; got != tt.want {
+				t.Errorf("Expected emitted=%v, got %v: %s", tt.want, got, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ContextLevel_PerRequest(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(NewHandler(inner, level))
+
+	debugReq := ContextWithLevel(context.Background(), slog.LevelDebug)
+	plainReq := context.Background()
+
+	logger.DebugContext(plainReq, "plain request")
+	logger.DebugContext(debugReq, "debug request")
+	logger.DebugContext(plainReq, "plain request again")
+
+	output := buf.String()
+	if !strings.Contains(output, "debug request") {
+		t.Errorf("Expected debug record from the debug request, got: %s", output)
+	}
+	if strings.Contains(output, "plain request") {
+		t.Errorf("Expected debug records from plain requests to be suppressed, got: %s", output)
+	}
+}
+
+func TestHandler_ContextLevel_Floor(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, WithLevelFloor(slog.LevelInfo))
+
+	ctx := ContextWithLevel(context.Background(), slog.LevelDebug)
+	if handler.Enabled(ctx, slog.LevelDebug) {
+		t.Error("Expected the level floor to override the context level in Enabled")
+	}
+	slog.New(handler).DebugContext(ctx, "test")
+	if buf.Len() != 0 {
+		t.Errorf("Expected the level floor to override the context level, got: %s", buf.String())
+	}
+}
+
+func TestHandler_ContextLevel_Explain(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	ctx := ContextWithLevel(context.Background(), slog.LevelDebug)
+	d := handler.Explain(ctx, slog.NewRecord(time.Now(), slog.LevelDebug, "test", 0))
+	if !d.Emit || d.Level != slog.LevelDebug {
+		t.Errorf("Expected emit at effective level DEBUG, got emit=%v level=%v", d.Emit, d.Level)
+	}
+}
@@ -0,0 +1,120 @@
+package logfilter
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FilterDiff describes the changes between two filter sets.
+type FilterDiff struct {
+	Added    []LogFilter    `json:"added,omitempty"`
+	Removed  []LogFilter    `json:"removed,omitempty"`
+	Modified []FilterChange `json:"modified,omitempty"`
+}
+
+// FilterChange describes a filter present in both sets whose fields differ.
+type FilterChange struct {
+	Old    LogFilter `json:"old"`
+	New    LogFilter `json:"new"`
+	Fields []string  `json:"fields"` // JSON names of the changed fields, in struct order
+}
+
+// IsEmpty returns true if the diff contains no changes.
+func (d FilterDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// DiffFilters computes the changes from one filter set to another.
+//
+// Filters are paired by ID when set, otherwise by Name when set, otherwise by
+// Type and Pattern, so a named filter whose pattern changes is reported as
+// modified rather than removed and re-added. If several
+// filters share a key they are paired in order of appearance. Paired filters
+// with differing fields are reported as modified; unpaired filters are
+// reported as added or removed. Ordering changes alone are not reported.
+//
+// DiffFilters is pure and does not touch any handler state.
+func DiffFilters(from, to []LogFilter) FilterDiff {
+	var diff FilterDiff
+
+	// Queue the target filters by key so duplicates pair up in order.
+	pending := make(map[string][]int, len(to))
+	for i := range to {
+		k := diffKey(&to[i])
+		pending[k] = append(pending[k], i)
+	}
+
+	paired := make([]bool, len(to))
+	for i := range from {
+		k := diffKey(&from[i])
+		queue := pending[k]
+		if len(queue) == 0 {
+			diff.Removed = append(diff.Removed, from[i])
+			continue
+		}
+		j := queue[0]
+		pending[k] = queue[1:]
+		paired[j] = true
+
+		if fields := changedFields(&from[i], &to[j]); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, FilterChange{Old: from[i], New: to[j], Fields: fields})
+		}
+	}
+
+	for j := range to {
+		if !paired[j] {
+			diff.Added = append(diff.Added, to[j])
+		}
+	}
+	return diff
+}
+
+// diffKey returns the identity used to pair filters across sets.
+func diffKey(f *LogFilter) string {
+	if f.ID != "" {
+		return "id:" + f.ID
+	}
+	if f.Name != "" {
+		return "name:" + f.Name
+	}
+	return "tp:" + f.Type + "\x00" + f.Pattern
+}
+
+// changedFields returns the JSON names of the serialized fields that differ
+// between a and b. Times are compared by instant, so a change of location
+// alone is not a modification.
+func changedFields(a, b *LogFilter) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	t := va.Type()
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if !fieldEqual(va.Field(i), vb.Field(i)) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// fieldEqual compares two field values, treating *time.Time by instant.
+func fieldEqual(a, b reflect.Value) bool {
+	if ta, ok := a.Interface().(*time.Time); ok {
+		tb := b.Interface().(*time.Time)
+		za := ta == nil || ta.IsZero()
+		zb := tb == nil || tb.IsZero()
+		if za || zb {
+			return za == zb
+		}
+		return ta.Equal(*tb)
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
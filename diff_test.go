@@ -0,0 +1,143 @@
+package logfilter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffFilters_AddRemoveModify(t *testing.T) {
+	from := []LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "user_id", Pattern: "u1", Level: "debug", Enabled: true},
+		{ID: "health", Type: "path", Pattern: "/healthz", Level: "error", Enabled: true},
+	}
+	to := []LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "warn", OutputLevel: "info", Enabled: true},
+		{ID: "health", Type: "path", Pattern: "/ready*", Level: "error", Enabled: true},
+		{Type: "tenant", Pattern: "acme", Level: "debug", Enabled: true},
+	}
+
+	diff := DiffFilters(from, to)
+
+	if len(diff.Added) != 1 || diff.Added[0].Type != "tenant" {
+		t.Errorf("Expected tenant filter added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Type != "user_id" {
+		t.Errorf("Expected user_id filter removed, got %+v", diff.Removed)
+	}
+	if len(diff.Modified) != 2 {
+		t.Fatalf("Expected 2 modified filters, got %+v", diff.Modified)
+	}
+
+	// Matched by type+pattern
+	if got := diff.Modified[0].Fields; !reflect.DeepEqual(got, []string{"level", "output_level"}) {
+		t.Errorf("Expected level and output_level changed, got %v", got)
+	}
+	// Matched by ID even though the pattern changed
+	if got := diff.Modified[1].Fields; !reflect.DeepEqual(got, []string{"pattern"}) {
+		t.Errorf("Expected pattern changed, got %v", got)
+	}
+	if diff.Modified[1].Old.Pattern != "/healthz" || diff.Modified[1].New.Pattern != "/ready*" {
+		t.Errorf("Expected old and new filters in change, got %+v", diff.Modified[1])
+	}
+}
+
+func TestDiffFilters_NoChanges(t *testing.T) {
+	filters := []LogFilter{
+		{Type: "a", Pattern: "1", Level: "debug", Enabled: true},
+		{Type: "b", Pattern: "2", Level: "info", Enabled: true},
+	}
+	reordered := []LogFilter{filters[1], filters[0]}
+
+	if diff := DiffFilters(filters, reordered); !diff.IsEmpty() {
+		t.Errorf("Expected no diff for reordered filters, got %+v", diff)
+	}
+	if diff := DiffFilters(nil, nil); !diff.IsEmpty() {
+		t.Errorf("Expected no diff for empty sets, got %+v", diff)
+	}
+}
+
+func TestDiffFilters_Expiry(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sameInstant := at.In(time.FixedZone("X", 3600))
+	later := at.Add(time.Hour)
+
+	base := LogFilter{Type: "a", Pattern: "1", Level: "debug", Enabled: true}
+	withExpiry := func(t *time.Time) LogFilter {
+		f := base
+		f.ExpiresAt = t
+		return f
+	}
+
+	if d := DiffFilters([]LogFilter{withExpiry(&at)}, []LogFilter{withExpiry(&sameInstant)}); !d.IsEmpty() {
+		t.Errorf("Expected same instant in another zone to be unchanged, got %+v", d)
+	}
+	if d := DiffFilters([]LogFilter{withExpiry(nil)}, []LogFilter{withExpiry(&time.Time{})}); !d.IsEmpty() {
+		t.Errorf("Expected nil and zero expiry to be equivalent, got %+v", d)
+	}
+
+	d := DiffFilters([]LogFilter{withExpiry(&at)}, []LogFilter{withExpiry(&later)})
+	if len(d.Modified) != 1 || !reflect.DeepEqual(d.Modified[0].Fields, []string{"expires_at"}) {
+		t.Errorf("Expected expires_at change, got %+v", d)
+	}
+
+	d = DiffFilters([]LogFilter{withExpiry(nil)}, []LogFilter{withExpiry(&later)})
+	if len(d.Modified) != 1 || !reflect.DeepEqual(d.Modified[0].Fields, []string{"expires_at"}) {
+		t.Errorf("Expected expires_at change when adding expiry, got %+v", d)
+	}
+}
+
+func TestDiffFilters_DuplicateKeys(t *testing.T) {
+	from := []LogFilter{
+		{Type: "a", Pattern: "1", Level: "debug"},
+		{Type: "a", Pattern: "1", Level: "warn"},
+	}
+	to := []LogFilter{
+		{Type: "a", Pattern: "1", Level: "debug"},
+	}
+
+	diff := DiffFilters(from, to)
+	if len(diff.Removed) != 1 || diff.Removed[0].Level != "warn" {
+		t.Errorf("Expected second duplicate removed, got %+v", diff)
+	}
+	if len(diff.Added) != 0 || len(diff.Modified) != 0 {
+		t.Errorf("Expected only a removal, got %+v", diff)
+	}
+}
+
+func TestDiffFilters_PairsByName(t *testing.T) {
+	from := []LogFilter{
+		{Name: "checkout", Type: "job_id", Pattern: "job_1", Level: "debug"},
+		{Type: "user", Pattern: "u_1", Level: "debug"},
+	}
+	to := []LogFilter{
+		{Name: "checkout", Type: "job_id", Pattern: "job_*", Level: "debug"},
+		{Name: "user-debug", Type: "user", Pattern: "u_1", Level: "debug"},
+	}
+
+	diff := DiffFilters(from, to)
+	if len(diff.Modified) != 1 || diff.Modified[0].New.Name != "checkout" {
+		t.Fatalf("Expected named filter paired and modified, got %+v", diff)
+	}
+	if got := diff.Modified[0].Fields; len(got) != 1 || got[0] != "pattern" {
+		t.Errorf("Expected [pattern] changed, got %v", got)
+	}
+	// Naming a filter changes its key, so it is a removal and an addition.
+	if len(diff.Removed) != 1 || diff.Removed[0].Type != "user" {
+		t.Errorf("Expected unnamed user filter removed, got %+v", diff.Removed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Name != "user-debug" {
+		t.Errorf("Expected named user filter added, got %+v", diff.Added)
+	}
+}
+
+func TestDiffFilters_IgnoresCachedFields(t *testing.T) {
+	a := LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug"}
+	b := a
+	b.prepare()
+
+	if diff := DiffFilters([]LogFilter{a}, []LogFilter{b}); !diff.IsEmpty() {
+		t.Errorf("Expected prepared filter to equal unprepared one, got %+v", diff)
+	}
+}
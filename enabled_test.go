@@ -0,0 +1,145 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type enabledCtxKey struct{}
+
+func registerTenantExtractor(t testing.TB) {
+	RegisterContextExtractor("tenant", func(ctx context.Context) (string, bool) {
+		s, ok := ctx.Value(enabledCtxKey{}).(string)
+		return s, ok
+	})
+	t.Cleanup(func() { UnregisterContextExtractor("tenant") })
+}
+
+func TestHandler_Enabled_ContextFilters(t *testing.T) {
+	registerTenantExtractor(t)
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+
+	acme := context.WithValue(context.Background(), enabledCtxKey{}, "acme")
+	other := context.WithValue(context.Background(), enabledCtxKey{}, "other")
+	expired := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		filters []LogFilter
+		ctx     context.Context
+		enabled bool
+	}{
+		{"no filters", nil, acme, false},
+		{"context match", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}}, acme, true},
+		{"context mismatch", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}}, other, false},
+		{"context absent", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}}, context.Background(), false},
+		{"context level too high", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "warn", Enabled: true}}, acme, false},
+		{"context negated", []LogFilter{{Type: "context:tenant", Pattern: "acme", Negate: true, Level: "debug", Enabled: true}}, other, true},
+		{"context expired", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true, ExpiresAt: &expired}}, acme, false},
+		{"context conditions", []LogFilter{{
+			Conditions: []Condition{{Type: "context:tenant", Pattern: "acme"}},
+			Level:      "debug",
+			Enabled:    true,
+		}}, other, false},
+		{"attribute filter", []LogFilter{{Type: "tenant", Pattern: "acme", Level: "debug", Enabled: true}}, other, true},
+		{"attribute condition", []LogFilter{{
+			Type:       "context:tenant",
+			Pattern:    "acme",
+			Conditions: []Condition{{Type: "user", Pattern: "*"}},
+			Level:      "debug",
+			Enabled:    true,
+		}}, other, true},
+		{"attribute filter above level", []LogFilter{
+			{Type: "tenant", Pattern: "acme", Level: "warn", Enabled: true},
+			{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+		}, other, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler.SetFilters(tt.filters)
+			if got := handler.Enabled(tt.ctx, slog.LevelDebug); got != tt.enabled {
+				t.Errorf("Expected Enabled(debug) = %v, got %v", tt.enabled, got)
+			}
+			if !handler.Enabled(tt.ctx, slog.LevelInfo) {
+				t.Error("Expected info to stay enabled at the global level")
+			}
+		})
+	}
+}
+
+func TestHandler_Enabled_ContextFilterEmits(t *testing.T) {
+	registerTenantExtractor(t)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}})
+	logger := slog.New(handler)
+
+	logger.DebugContext(context.WithValue(context.Background(), enabledCtxKey{}, "other"), "skipped")
+	if buf.Len() > 0 {
+		t.Errorf("Expected debug record for another tenant suppressed, got: %s", buf.String())
+	}
+	logger.DebugContext(context.WithValue(context.Background(), enabledCtxKey{}, "acme"), "traced")
+	if buf.Len() == 0 {
+		t.Error("Expected debug record for the matching tenant to be emitted")
+	}
+}
+
+func BenchmarkEnabled(b *testing.B) {
+	registerTenantExtractor(b)
+
+	cases := []struct {
+		name    string
+		filters []LogFilter
+	}{
+		{"no filters", nil},
+		{"context filter", []LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}}},
+		{"attribute filter", []LogFilter{{Type: "tenant", Pattern: "acme", Level: "debug", Enabled: true}}},
+	}
+
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+			handler.SetFilters(c.filters)
+			ctx := context.WithValue(context.Background(), enabledCtxKey{}, "other")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = handler.Enabled(ctx, slog.LevelDebug)
+			}
+		})
+	}
+}
+
+func BenchmarkLogger_DebugContextFilter(b *testing.B) {
+	registerTenantExtractor(b)
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true}})
+	logger := slog.New(handler)
+	ctx := context.WithValue(context.Background(), enabledCtxKey{}, "other")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.DebugContext(ctx, "step", "i", i)
+	}
+}
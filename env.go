@@ -0,0 +1,90 @@
+package logfilter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultEnvPrefix is the variable prefix LoadFiltersFromEnv uses when given
+// an empty prefix.
+const DefaultEnvPrefix = "LOGFILTER"
+
+// LoadFiltersFromEnv reads filters from environment variables named
+// prefix_N, where N is a non-negative integer, in order of N. Each value has
+// the form
+//
+//	type:pattern:level[:output_level]
+//
+// for example LOGFILTER_0=job_id:job_*:debug or
+// LOGFILTER_1=context:user_id:alice:debug:info. A type starting with
+// "context:", "any:" or "source:" spans two colon-separated fields; patterns
+// can't contain colons. The filters are enabled.
+//
+// Variables whose suffix isn't an integer are ignored. Malformed entries, or
+// ones failing LogFilter.Validate, are skipped; the remaining filters are
+// returned with an error describing each skipped entry. An empty prefix means
+// DefaultEnvPrefix.
+func LoadFiltersFromEnv(prefix string) ([]LogFilter, error) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	prefix += "_"
+
+	type entry struct {
+		index       int
+		name, value string
+	}
+	var entries []entry
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < 0 || strconv.Itoa(index) != suffix {
+			continue
+		}
+		entries = append(entries, entry{index, name, value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].index < entries[j].index })
+
+	var filters []LogFilter
+	var errs []error
+	for _, e := range entries {
+		f, err := parseEnvFilter(e.value)
+		if err == nil {
+			err = f.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("logfilter: %s=%q: %w", e.name, e.value, err))
+			continue
+		}
+		filters = append(filters, f)
+	}
+	return filters, errors.Join(errs...)
+}
+
+// parseEnvFilter parses a LoadFiltersFromEnv value.
+func parseEnvFilter(value string) (LogFilter, error) {
+	fields := strings.Split(strings.TrimSpace(value), ":")
+	switch fields[0] {
+	case strings.TrimSuffix(ContextPrefix, ":"), strings.TrimSuffix(AnyPrefix, ":"), strings.TrimSuffix(sourceTypePrefix, ":"):
+		if len(fields) > 1 {
+			fields = append([]string{fields[0] + ":" + fields[1]}, fields[2:]...)
+		}
+	}
+	if len(fields) != 3 && len(fields) != 4 {
+		return LogFilter{}, fmt.Errorf("want type:pattern:level[:output_level], got %d fields", len(fields))
+	}
+
+	f := LogFilter{Type: fields[0], Pattern: fields[1], Level: fields[2], Enabled: true}
+	if len(fields) == 4 {
+		f.OutputLevel = fields[3]
+	}
+	return f, nil
+}
@@ -0,0 +1,74 @@
+package logfilter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadFiltersFromEnv(t *testing.T) {
+	t.Setenv("APPLOG_0", "job_id:job_*:debug")
+	t.Setenv("APPLOG_2", "context:user_id:alice:debug:info")
+	t.Setenv("APPLOG_10", "source:file:internal/service/*:debug")
+	t.Setenv("APPLOG_1", "any:tenant:acme:warn")
+	t.Setenv("APPLOG_LEVEL", "debug") // Not an indexed entry, ignored
+	t.Setenv("APPLOGX_0", "service:auth:debug")
+
+	filters, err := LoadFiltersFromEnv("APPLOG")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "any:tenant", Pattern: "acme", Level: "warn", Enabled: true},
+		{Type: "context:user_id", Pattern: "alice", Level: "debug", OutputLevel: "info", Enabled: true},
+		{Type: "source:file", Pattern: "internal/service/*", Level: "debug", Enabled: true},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Expected %+v, got %+v", want, filters)
+	}
+}
+
+func TestLoadFiltersFromEnv_DefaultPrefix(t *testing.T) {
+	t.Setenv("LOGFILTER_0", "service:auth:debug")
+
+	filters, err := LoadFiltersFromEnv("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(filters) != 1 || filters[0].Type != "service" {
+		t.Errorf("Expected one service filter, got %+v", filters)
+	}
+}
+
+func TestLoadFiltersFromEnv_Malformed(t *testing.T) {
+	t.Setenv("BADLOG_0", "service:auth:debug")
+	t.Setenv("BADLOG_1", "service:auth")                  // Too few fields
+	t.Setenv("BADLOG_2", "service:auth:debug:info:extra") // Too many fields
+	t.Setenv("BADLOG_3", "service:auth:loud")             // Unknown level
+	t.Setenv("BADLOG_4", "context::alice:debug")          // Empty context key
+	t.Setenv("BADLOG_5", "job_id:job_*:warn:error")
+
+	filters, err := LoadFiltersFromEnv("BADLOG")
+	if err == nil {
+		t.Fatal("Expected an error for the malformed entries")
+	}
+	for _, name := range []string{"BADLOG_1", "BADLOG_2", "BADLOG_3", "BADLOG_4"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected error to mention %s, got %v", name, err)
+		}
+	}
+	if !errors.Is(err, ErrUnknownLevel) || !errors.Is(err, ErrEmptyContextKey) {
+		t.Errorf("Expected validation errors to be wrapped, got %v", err)
+	}
+
+	want := []LogFilter{
+		{Type: "service", Pattern: "auth", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_*", Level: "warn", OutputLevel: "error", Enabled: true},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Expected valid entries %+v, got %+v", want, filters)
+	}
+}
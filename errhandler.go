@@ -0,0 +1,26 @@
+package logfilter
+
+// WithErrorHandler sets a function called with each error returned by the
+// inner handler (or a route's handler) while handling a record, including
+// when records held for capture-on-error or the suppressed-record buffer are
+// flushed by an error record. slog.Logger discards Handle's error, so without
+// this a failing destination, such as a broken pipe, goes unnoticed; the
+// callback can count failures or fail over with SetInnerHandler. Handle
+// still returns the error. Firehose errors are not reported.
+//
+// The callback runs synchronously in Handle, possibly concurrently, and must
+// not log through the same handler.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+// reportError passes a non-nil err to the WithErrorHandler callback, if any,
+// and returns it.
+func (h *Handler) reportError(err error) error {
+	if err != nil && h.errorHandler != nil {
+		h.errorHandler(err)
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWithErrorHandler(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	var reported []error
+	inner := failingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), err: errBroken}
+	handler := NewHandler(inner, level, WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	if err := handler.Handle(context.Background(), r); !errors.Is(err, errBroken) {
+		t.Errorf("Expected Handle to return the inner error, got %v", err)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errBroken) {
+		t.Fatalf("Expected the error handler to get the inner error once, got %v", reported)
+	}
+
+	// Suppressed records never reach the inner handler.
+	r = slog.NewRecord(time.Now(), slog.LevelDebug, "test", 0)
+	if err := handler.Handle(context.Background(), r); err != nil {
+		t.Errorf("Expected no error for a suppressed record, got %v", err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("Expected no report for a suppressed record, got %v", reported)
+	}
+
+	// The error is reported while filtering is disabled too.
+	handler.SetFilteringEnabled(false)
+	r = slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	_ = handler.Handle(context.Background(), r)
+	if len(reported) != 2 {
+		t.Errorf("Expected the error to be reported with filtering disabled, got %v", reported)
+	}
+}
+
+func TestWithErrorHandler_SuppressedFlush(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	var reported []error
+	inner := failingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), err: errBroken}
+	handler := NewHandler(inner, level,
+		WithSuppressedBuffer(4),
+		WithFlushSuppressedOnError(true),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+
+	logger := slog.New(handler)
+	logger.Debug("held")
+	logger.Error("failure")
+
+	// One report for the replayed record, one for the error record itself.
+	if len(reported) != 2 {
+		t.Errorf("Expected 2 reported errors, got %d: %v", len(reported), reported)
+	}
+}
+
+func TestWithErrorHandler_Unset(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	level := new(slog.LevelVar)
+	inner := failingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), err: errBroken}
+	handler := NewHandler(inner, level)
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	if err := handler.Handle(context.Background(), r); !errors.Is(err, errBroken) {
+		t.Errorf("Expected Handle to return the inner error, got %v", err)
+	}
+}
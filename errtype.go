@@ -0,0 +1,60 @@
+package logfilter
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// MatchErrorType is the LogFilter.MatchMode that matches Pattern against the
+// concrete type of the error held by the attribute Type names, as printed by
+// fmt's %T, e.g. "*net.OpError" or "*fs.PathError", instead of against its
+// text. Negate and Regex apply as usual. Values that aren't errors never
+// match. With Type "has-error" it matches the record's first error
+// attribute (see HasErrorType).
+//
+// Example, elevating network errors logged with slog.Any("err", err):
+//
+//	{Type: "err", Pattern: "*net.OpError", MatchMode: "errtype", Level: "debug", OutputLevel: "error"}
+const MatchErrorType = "errtype"
+
+// presenceMode reports whether mode is a presence check, which ignores
+// Pattern, rather than empty or MatchErrorType.
+func presenceMode(mode string) bool {
+	return mode != "" && mode != MatchErrorType
+}
+
+// matchErrorType reports whether the type of the error f's Type names
+// matches f's Pattern, returning the type name.
+func (v *recordView) matchErrorType(f *LogFilter) (string, bool) {
+	var val slog.Value
+	var found bool
+	switch f.kind {
+	case filterKindAttribute, filterKindAny:
+		key := f.AttributeKey()
+		val, found = v.rawAttr(key, f.MatchScope)
+		if !found {
+			val, found = v.rawPath(key, f.MatchScope)
+		}
+	case filterKindHasError:
+		val, found = v.h.recordErrorValue(*v.r)
+	}
+	if !found {
+		return "", false
+	}
+	err, ok := errorOf(val)
+	if !ok {
+		return "", false
+	}
+	name := fmt.Sprintf("%T", err)
+	return name, f.matchValue(f.matcher, name)
+}
+
+// errorOf returns the error held by val, if any.
+func errorOf(val slog.Value) (error, bool) {
+	val = val.Resolve()
+	if val.Kind() != slog.KindAny {
+		return nil, false
+	}
+	err, ok := val.Any().(error)
+	return err, ok
+}
@@ -0,0 +1,157 @@
+package logfilter
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+// quotaError is a custom error type for errtype tests.
+type quotaError struct{}
+
+func (e quotaError) Error() string { return "quota exceeded" }
+
+func TestMatchErrorType(t *testing.T) {
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	pathErr := &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist}
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		log    func(l *slog.Logger)
+		want   bool
+	}{
+		{
+			"net error",
+			LogFilter{Type: "err", Pattern: "*net.OpError"},
+			func(l *slog.Logger) { l.Debug("dial failed", "err", opErr) },
+			true,
+		},
+		{
+			"path error against net pattern",
+			LogFilter{Type: "err", Pattern: "*net.OpError"},
+			func(l *slog.Logger) { l.Debug("open failed", "err", pathErr) },
+			false,
+		},
+		{
+			"path error",
+			LogFilter{Type: "err", Pattern: "*fs.PathError"},
+			func(l *slog.Logger) { l.Debug("open failed", "err", pathErr) },
+			true,
+		},
+		{
+			"value type",
+			LogFilter{Type: "err", Pattern: "logfilter.quotaError"},
+			func(l *slog.Logger) { l.Debug("rejected", "err", quotaError{}) },
+			true,
+		},
+		{
+			"errors.New",
+			LogFilter{Type: "err", Pattern: "*errors.errorString"},
+			func(l *slog.Logger) { l.Debug("failed", "err", errors.New("boom")) },
+			true,
+		},
+		{
+			"text is not matched",
+			LogFilter{Type: "err", Pattern: "*refused*"},
+			func(l *slog.Logger) { l.Debug("dial failed", "err", opErr) },
+			false,
+		},
+		{
+			"non-error value",
+			LogFilter{Type: "err", Pattern: "*"},
+			func(l *slog.Logger) { l.Debug("failed", "err", "connection refused") },
+			false,
+		},
+		{
+			"absent attribute",
+			LogFilter{Type: "err", Pattern: "*"},
+			func(l *slog.Logger) { l.Debug("failed") },
+			false,
+		},
+		{
+			"negate",
+			LogFilter{Type: "err", Pattern: "*net.OpError", Negate: true},
+			func(l *slog.Logger) { l.Debug("open failed", "err", pathErr) },
+			true,
+		},
+		{
+			"regex",
+			LogFilter{Type: "err", Pattern: `\*(net|fs)\.\w+Error`, Regex: true},
+			func(l *slog.Logger) { l.Debug("open failed", "err", pathErr) },
+			true,
+		},
+		{
+			"logger attribute",
+			LogFilter{Type: "err", Pattern: "*net.OpError"},
+			func(l *slog.Logger) { l.With("err", opErr).Debug("dial failed") },
+			true,
+		},
+		{
+			"any: filter",
+			LogFilter{Type: "any:err", Pattern: "*net.OpError"},
+			func(l *slog.Logger) { l.Debug("dial failed", "err", opErr) },
+			true,
+		},
+		{
+			"has-error",
+			LogFilter{Type: HasErrorType, Pattern: "*net.OpError"},
+			func(l *slog.Logger) { l.Debug("dial failed", "cause", opErr) },
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+			handler := NewHandler(inner, level)
+			f := tt.filter
+			f.MatchMode = MatchErrorType
+			f.Level = "debug"
+			f.Enabled = true
+			handler.SetFilters([]LogFilter{f})
+
+			tt.log(slog.New(handler))
+			if got := buf.Len() > 0; got != tt.want {
+				t.Errorf("Expected emitted=%v, got %v: %s", tt.want, got, buf.String())
+			}
+		})
+	}
+}
+
+func TestMatchErrorType_OutputLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "err", Pattern: "*net.OpError", MatchMode: MatchErrorType, Level: "debug", OutputLevel: "error", Enabled: true},
+	})
+
+	slog.New(handler).Debug("dial failed", "err", &net.OpError{Op: "dial", Err: errors.New("refused")})
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("Expected the net error to be bumped to ERROR, got: %s", buf.String())
+	}
+}
+
+func TestMatchErrorType_Validate(t *testing.T) {
+	f := LogFilter{Type: "err", Pattern: "*net.OpError", MatchMode: MatchErrorType}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected errtype filter to be valid, got %v", err)
+	}
+
+	f = LogFilter{Type: "err", MatchMode: MatchErrorType}
+	if err := f.Validate(); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("Expected ErrEmptyPattern for errtype without a pattern, got %v", err)
+	}
+}
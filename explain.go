@@ -0,0 +1,135 @@
+package logfilter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// FilterDecision describes how the handler would treat a record, as reported
+// by Explain.
+type FilterDecision struct {
+	// Filter is a copy of the filter that matched, or nil if none did and the
+	// global level applied.
+	Filter *LogFilter
+
+	// Value is the value Filter matched against.
+	Value string
+
+	// Suppression is a copy of the global suppression that dropped the
+	// record, if any.
+	Suppression *Suppression
+
+	// Level is the effective minimum level: the context's ContextWithLevel
+	// override, else the filter's Level, or the global level if no filter
+	// matched, raised to the WithLevelFloor floor.
+	Level slog.Level
+
+	// OutputLevel is the level the record would be emitted at.
+	OutputLevel slog.Level
+
+	// Emit reports whether the record would be emitted.
+	Emit bool
+}
+
+// String describes the decision, e.g.
+//
+//	emit at DEBUG: filter "f-1a2b3c4d" (job_id job_*) matched "job_42", level DEBUG
+func (d FilterDecision) String() string {
+	verdict := "suppress"
+	if d.Emit {
+		verdict = "emit at " + d.OutputLevel.String()
+	}
+	switch {
+	case d.Suppression != nil:
+		return fmt.Sprintf("%s: global suppression (%s %s)", verdict, d.Suppression.Key, d.Suppression.Pattern)
+	case d.Filter != nil:
+		return fmt.Sprintf("%s: filter %q (%s %s) matched %q, level %s",
+			verdict, d.Filter.FilterID(), d.Filter.Type, d.Filter.Pattern, d.Value, d.Level)
+	default:
+		return fmt.Sprintf("%s: no filter matched, global level %s", verdict, d.Level)
+	}
+}
+
+// Explain reports which filter, if any, matches r, the effective level, and
+// whether Handle would emit r, without emitting it. It runs the same
+// evaluation as Handle, including redaction, base filters, filter groups and
+// global suppressions, and a filter stuck to the context's ID by
+// WithStickyMatches, but doesn't count the match, stick a match to the ID, or
+// consult the stateful limits (ThrottlePerValue, MaxPerSecond) or baseline
+// sampling, so explaining a record never changes how later records are
+// handled.
+func (h *Handler) Explain(ctx context.Context, r slog.Record) FilterDecision {
+	if h.filteringDisabled.Load() {
+		level := h.floored(h.globalLevel.Level())
+		return FilterDecision{Level: level, OutputLevel: r.Level, Emit: r.Level >= level}
+	}
+	if h.redactor != nil {
+		r = h.redactor.record(r)
+	}
+
+	d := h.evaluate(ctx, r, nil)
+	if h.sticky != nil {
+		d = h.applySticky(ctx, r, d, true)
+	}
+	fd := FilterDecision{
+		Value:       d.value,
+		Level:       d.level,
+		OutputLevel: d.outputLevel,
+		Emit:        d.emit,
+	}
+	if d.filter != nil {
+		f := *d.filter
+		fd.Filter = &f
+	}
+	if d.suppression != nil {
+		s := *d.suppression
+		fd.Suppression = &s
+	}
+	return fd
+}
+
+// Explain explains r against the global handler (see Handler.Explain). It
+// returns the zero FilterDecision if there is no global handler.
+func Explain(ctx context.Context, r slog.Record) FilterDecision {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.Explain(ctx, r)
+	}
+	return FilterDecision{}
+}
+
+// WouldEmit reports whether a record at level carrying attrs would be
+// emitted, for tests asserting that, with the filters set, a given call is
+// logged. It synthesizes a record at the handler's current time, with an
+// empty message and no source location, and evaluates it as Explain does:
+// the inner handler isn't called and nothing is counted. Attributes bound to
+// the handler with WithAttrs take part, as for a real record.
+//
+// Example:
+//
+//	if !handler.WouldEmit(ctx, slog.LevelDebug, slog.String("job_id", "job_42")) {
+//	    t.Error("Expected job_42's debug records to be emitted")
+//	}
+func (h *Handler) WouldEmit(ctx context.Context, level slog.Level, attrs ...slog.Attr) bool {
+	r := slog.NewRecord(h.now(), level, "", 0)
+	r.AddAttrs(attrs...)
+	return h.Explain(ctx, r).Emit
+}
+
+// WouldEmit reports whether the global handler would emit a record at level
+// carrying attrs (see Handler.WouldEmit). It returns false if there is no
+// global handler.
+func WouldEmit(ctx context.Context, level slog.Level, attrs ...slog.Attr) bool {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.WouldEmit(ctx, level, attrs...)
+	}
+	return false
+}
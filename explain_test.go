@@ -0,0 +1,213 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_Explain(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{
+		{ID: "jobs", Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "warn", Enabled: true},
+		{ID: "quiet", Type: "service", Pattern: "noisy", Level: "error", Enabled: true},
+	})
+	handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/health"}})
+
+	record := func(level slog.Level, args ...any) slog.Record {
+		r := slog.NewRecord(time.Now(), level, "msg", 0)
+		r.Add(args...)
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		r           slog.Record
+		filter      string // Expected filter ID, "" for none
+		level       slog.Level
+		outputLevel slog.Level
+		emit        bool
+		suppression bool
+		reason      string
+	}{
+		{
+			name: "filter elevates", r: record(slog.LevelDebug, "job_id", "job_1"),
+			filter: "jobs", level: slog.LevelDebug, outputLevel: slog.LevelWarn, emit: true,
+			reason: `emit at WARN: filter "jobs" (job_id job_*) matched "job_1", level DEBUG`,
+		},
+		{
+			name: "filter suppresses", r: record(slog.LevelWarn, "service", "noisy"),
+			filter: "quiet", level: slog.LevelError, outputLevel: slog.LevelWarn,
+			reason: `suppress: filter "quiet" (service noisy) matched "noisy", level ERROR`,
+		},
+		{
+			name: "no match below global", r: record(slog.LevelDebug, "job_id", "task_1"),
+			level: slog.LevelInfo, outputLevel: slog.LevelDebug,
+			reason: "suppress: no filter matched, global level INFO",
+		},
+		{
+			name: "no match at global", r: record(slog.LevelInfo),
+			level: slog.LevelInfo, outputLevel: slog.LevelInfo, emit: true,
+			reason: "emit at INFO: no filter matched, global level INFO",
+		},
+		{
+			name: "global suppression", r: record(slog.LevelInfo, "path", "/health", "job_id", "job_1"),
+			level: slog.LevelInfo, outputLevel: slog.LevelInfo, suppression: true,
+			reason: "suppress: global suppression (path /health)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := handler.Explain(context.Background(), tt.r)
+			id := ""
+			if d.Filter != nil {
+				id = d.Filter.ID
+			}
+			if id != tt.filter {
+				t.Errorf("Expected filter %q, got %q", tt.filter, id)
+			}
+			if d.Level != tt.level || d.OutputLevel != tt.outputLevel || d.Emit != tt.emit {
+				t.Errorf("Expected level=%v output=%v emit=%v, got %+v", tt.level, tt.outputLevel, tt.emit, d)
+			}
+			if (d.Suppression != nil) != tt.suppression {
+				t.Errorf("Expected suppression=%v, got %+v", tt.suppression, d.Suppression)
+			}
+			if got := d.String(); got != tt.reason {
+				t.Errorf("Expected reason %q, got %q", tt.reason, got)
+			}
+		})
+	}
+}
+
+func TestHandler_Explain_NoSideEffects(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", MaxHits: 1, ThrottlePerValue: time.Hour, Enabled: true},
+	})
+
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "step", 0)
+	r.AddAttrs(slog.String("job_id", "job_1"))
+	for i := 0; i < 3; i++ {
+		if d := handler.Explain(context.Background(), r); !d.Emit {
+			t.Fatalf("Expected Explain %d to report emit, got %s", i, d)
+		}
+	}
+	if s := handler.Stats(); s[0].Matches != 0 {
+		t.Errorf("Expected Explain not to count matches, got %d", s[0].Matches)
+	}
+
+	// The filter still has its one hit and its throttle unused
+	var emitted []string
+	handler.SetMatchHook(func(f LogFilter, r slog.Record) { emitted = append(emitted, r.Message) })
+	if err := handler.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitted) != 1 {
+		t.Errorf("Expected the filter to match after Explain, got %d matches", len(emitted))
+	}
+}
+
+func TestHandler_Explain_FilteringDisabled(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true}})
+	handler.SetFilteringEnabled(false)
+
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "step", 0)
+	r.AddAttrs(slog.String("job_id", "job_1"))
+	d := handler.Explain(context.Background(), r)
+	if d.Emit || d.Filter != nil || !strings.Contains(d.String(), "global level INFO") {
+		t.Errorf("Expected filters to be bypassed, got %s", d)
+	}
+}
+
+type wouldEmitCtxKey struct{}
+
+func TestHandler_WouldEmit(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", MaxHits: 1, Enabled: true},
+		{Type: "component", Pattern: "db", Level: "error", Enabled: true},
+		{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+	})
+	RegisterContextExtractor("tenant", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(wouldEmitCtxKey{}).(string)
+		return v, ok
+	})
+	t.Cleanup(ClearContextExtractors)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		level slog.Level
+		attrs []slog.Attr
+		want  bool
+	}{
+		{"matched debug", context.Background(), slog.LevelDebug, []slog.Attr{slog.String("job_id", "job_42")}, true},
+		{"unmatched debug", context.Background(), slog.LevelDebug, []slog.Attr{slog.String("job_id", "job_7")}, false},
+		{"no attributes info", context.Background(), slog.LevelInfo, nil, true},
+		{"no attributes debug", context.Background(), slog.LevelDebug, nil, false},
+		{"suppressed warn", context.Background(), slog.LevelWarn, []slog.Attr{slog.String("component", "db")}, false},
+		{"suppressing filter's level", context.Background(), slog.LevelError, []slog.Attr{slog.String("component", "db")}, true},
+		{"context match", context.WithValue(context.Background(), wouldEmitCtxKey{}, "acme"), slog.LevelDebug, nil, true},
+	}
+
+	for _, tt := range tests {
+		if got := handler.WouldEmit(tt.ctx, tt.level, tt.attrs...); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// Repeated calls neither emit nor use up the filter's one hit.
+	if buf.Len() > 0 {
+		t.Errorf("Expected WouldEmit not to emit, got: %s", buf.String())
+	}
+	if s := handler.Stats(); s[0].Matches != 0 {
+		t.Errorf("Expected WouldEmit not to count matches, got %d", s[0].Matches)
+	}
+}
+
+func TestHandler_WouldEmit_BoundAttrs(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_42", Level: "debug", Enabled: true}})
+
+	bound := handler.WithAttrs([]slog.Attr{slog.String("job_id", "job_42")}).(*Handler)
+	if !bound.WouldEmit(context.Background(), slog.LevelDebug) {
+		t.Error("Expected attributes bound with WithAttrs to match")
+	}
+	if handler.WouldEmit(context.Background(), slog.LevelDebug) {
+		t.Error("Expected the parent handler without the attribute not to match")
+	}
+}
+
+func TestWouldEmit_Global(t *testing.T) {
+	_ = New(WithLevel(slog.LevelInfo))
+	SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_42", Level: "debug", Enabled: true}})
+
+	if !WouldEmit(context.Background(), slog.LevelDebug, slog.String("job_id", "job_42")) {
+		t.Error("Expected the matched debug record to be emitted")
+	}
+	if WouldEmit(context.Background(), slog.LevelDebug, slog.String("job_id", "job_7")) {
+		t.Error("Expected the unmatched debug record to be suppressed")
+	}
+}
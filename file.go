@@ -0,0 +1,113 @@
+package logfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadFiltersFromFile reads a JSON array of filters, as written by
+// SaveFiltersToFile, from path. Each filter's Level and OutputLevel must be
+// empty or recognized by ParseLevelStrict, as must its SourceLevels;
+// otherwise an error naming the filter and the level is returned, so that a
+// typo doesn't silently become "info".
+func LoadFiltersFromFile(path string) ([]LogFilter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []LogFilter
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return nil, fmt.Errorf("logfilter: parsing %s: %w", path, err)
+	}
+	for i, f := range filters {
+		for _, level := range [...]struct{ field, value string }{{"level", f.Level}, {"output_level", f.OutputLevel}} {
+			if !validLevelName(level.value) {
+				return nil, fmt.Errorf("logfilter: %s: filter %d (type %q, pattern %q): unknown %s %q",
+					path, i, f.Type, f.Pattern, level.field, level.value)
+			}
+		}
+		for _, l := range f.SourceLevels {
+			if _, err := ParseLevelStrict(l); err != nil {
+				return nil, fmt.Errorf("logfilter: %s: filter %d (type %q, pattern %q): unknown source_levels %q",
+					path, i, f.Type, f.Pattern, l)
+			}
+		}
+	}
+	return filters, nil
+}
+
+// SaveFiltersToFile writes filters to path as an indented JSON array, using
+// LogFilter's JSON field names. Levels are written in canonical form (see
+// LevelToString), e.g. "DEBUG" as "debug" and "8" as "error", so that they
+// read back to the same levels; empty, relative and unknown levels are
+// written as given. The file is replaced atomically, so a process watching
+// path never reads a partial write.
+func SaveFiltersToFile(path string, filters []LogFilter) error {
+	out := make([]LogFilter, len(filters)) // Write [] rather than null
+	for i, f := range filters {
+		f.Level = canonicalLevel(f.Level, false)
+		f.OutputLevel = canonicalLevel(f.OutputLevel, true)
+		if f.SourceLevels != nil {
+			levels := make([]string, len(f.SourceLevels))
+			for j, l := range f.SourceLevels {
+				levels[j] = canonicalLevel(l, false)
+			}
+			f.SourceLevels = levels
+		}
+		out[i] = f
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// canonicalLevel returns LevelToString of the level s names, or s itself if
+// it is empty, not a level or, for an OutputLevel (relative set), an offset.
+func canonicalLevel(s string, relative bool) string {
+	if strings.TrimSpace(s) == "" {
+		return s
+	}
+	if _, ok := parseLevelOffset(s); ok && relative {
+		return s
+	}
+	l, err := ParseLevelStrict(s)
+	if err != nil {
+		return s
+	}
+	return LevelToString(l)
+}
+
+// validLevelName reports whether ParseLevelStrict recognizes s, or s is empty
+// (the default).
+func validLevelName(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return true
+	}
+	_, err := ParseLevelStrict(s)
+	return err == nil
+}
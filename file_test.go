@@ -0,0 +1,167 @@
+package logfilter
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadFilters_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filters.json")
+
+	expiry := time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.FixedZone("CET", 3600))
+	filters := []LogFilter{
+		{
+			ID: "job-debug", Name: "checkout", Type: "job_id", Pattern: "job_*",
+			Level: "debug", OutputLevel: "info", Enabled: true, ExpiresAt: &expiry,
+			ThrottlePerValue: 5 * time.Second,
+		},
+		{Type: "duration_ms", Pattern: ">500", Numeric: true, Negate: true, Level: "warn", Enabled: true},
+		{Type: "user_id", Pattern: "u_[0-9]+", Regex: true, Level: "error", Confirmed: true},
+	}
+
+	if err := SaveFiltersToFile(path, filters); err != nil {
+		t.Fatalf("SaveFiltersToFile returned error: %v", err)
+	}
+	loaded, err := LoadFiltersFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFiltersFromFile returned error: %v", err)
+	}
+	if len(loaded) != len(filters) {
+		t.Fatalf("Expected %d filters, got %d", len(filters), len(loaded))
+	}
+
+	if loaded[0].ExpiresAt == nil || !loaded[0].ExpiresAt.Equal(expiry) {
+		t.Errorf("Expected ExpiresAt %v, got %v", expiry, loaded[0].ExpiresAt)
+	}
+	if loaded[1].ExpiresAt != nil {
+		t.Errorf("Expected nil ExpiresAt to stay nil, got %v", loaded[1].ExpiresAt)
+	}
+
+	// Compare the remaining fields with ExpiresAt cleared
+	for i := range filters {
+		want, got := filters[i], loaded[i]
+		want.ExpiresAt, got.ExpiresAt = nil, nil
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("Filter %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestSaveFiltersToFile_CanonicalLevels(t *testing.T) {
+	defer ClearLevelNames()
+	if err := RegisterLevelName("notice", slog.Level(2)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "filters.json")
+
+	filters := []LogFilter{
+		{Type: "a", Pattern: "1", Level: "DEBUG", OutputLevel: " Warning ", SourceLevels: []string{"8", "NOTICE"}},
+		{Type: "b", Pattern: "2", Level: "INFO+2", OutputLevel: "+1"},
+		{Type: "c", Pattern: "3", Level: "", OutputLevel: "loud"},
+		{Type: "d", Pattern: "4", Level: "-8"},
+	}
+	if err := SaveFiltersToFile(path, filters); err != nil {
+		t.Fatalf("SaveFiltersToFile returned error: %v", err)
+	}
+	if filters[0].Level != "DEBUG" || filters[0].SourceLevels[0] != "8" {
+		t.Errorf("Expected the caller's filters unchanged, got %+v", filters[0])
+	}
+
+	var saved []LogFilter
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][3]string{
+		{"debug", "warn", "error,notice"},
+		{"notice", "+1", ""},
+		{"", "loud", ""},
+		{"trace", "", ""},
+	}
+	for i, w := range want {
+		got := [3]string{saved[i].Level, saved[i].OutputLevel, strings.Join(saved[i].SourceLevels, ",")}
+		if got != w {
+			t.Errorf("Filter %d: expected levels %q, got %q", i, w, got)
+		}
+	}
+}
+
+func TestSaveFiltersToFile_Overwrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "filters.json")
+
+	if err := SaveFiltersToFile(path, []LogFilter{{Type: "a", Pattern: "1", Level: "debug"}}); err != nil {
+		t.Fatalf("SaveFiltersToFile returned error: %v", err)
+	}
+	if err := SaveFiltersToFile(path, nil); err != nil {
+		t.Fatalf("SaveFiltersToFile returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(data)) != "[]" {
+		t.Errorf("Expected nil filters saved as [], got %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestLoadFiltersFromFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown level", `[{"type": "job_id", "pattern": "x", "level": "verbose"}]`, `unknown level "verbose"`},
+		{"unknown output level", `[{"type": "job_id", "pattern": "x", "level": "debug", "output_level": "loud"}]`, `unknown output_level "loud"`},
+		{"names the filter", `[{"type": "ok", "level": "info"}, {"type": "job_id", "pattern": "x", "level": "dbg"}]`, `filter 1 (type "job_id", pattern "x")`},
+		{"malformed JSON", `[{"type": }]`, "parsing"},
+		{"bad expiry", `[{"type": "job_id", "expires_at": "tomorrow"}]`, "parsing"},
+		{"unknown source level", `[{"type": "job_id", "pattern": "x", "source_levels": ["warn", "oops"]}]`, `unknown source_levels "oops"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFiltersFromFile(write("filters.json", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Level names are accepted in any case, and may be omitted
+	path := write("ok.json", `[{"type": "a", "level": "WARNING", "output_level": " Error "}, {"type": "b"}]`)
+	if _, err := LoadFiltersFromFile(path); err != nil {
+		t.Errorf("Expected valid levels to load, got %v", err)
+	}
+
+	if _, err := LoadFiltersFromFile(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for a missing file, got %v", err)
+	}
+}
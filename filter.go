@@ -9,10 +9,16 @@ import (
 // Source filter type prefixes.
 const (
 	ContextPrefix        = "context:"
+	AnyPrefix            = "any:"
 	SourceFilePrefix     = "source:file"
 	SourceFunctionPrefix = "source:function"
+	SourcePackagePrefix  = "source:package"
 )
 
+// MessageType is the filter type that matches the record's message text.
+// It takes precedence over an attribute named "message".
+const MessageType = "message"
+
 // filterKind classifies a filter's type for fast dispatch in the hot path.
 type filterKind int
 
@@ -20,17 +26,46 @@ const (
 	filterKindAttribute      filterKind = iota // Match against record/preformatted attributes
 	filterKindSourceFile                       // Match against source file path
 	filterKindSourceFunction                   // Match against function name
+	filterKindSourcePackage                    // Match against the function's package path
 	filterKindContext                          // Match against context value
+	filterKindComponent                        // Match against the logger's component attribute
+	filterKindHasError                         // Match records carrying an error
+	filterKindAny                              // Match against attribute, then context value
+	filterKindMessage                          // Match against the record's message
 )
 
 // LogFilter defines a log level override based on attribute matching.
 type LogFilter struct {
+	// ID optionally identifies the filter across updates, e.g. for DiffFilters.
+	// When empty, FilterID derives a stable ID from the filter's content.
+	ID string `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"`
+
+	// Name optionally labels the filter for humans and targeted management,
+	// e.g. "debug-checkout-job" (see RemoveFilterByName, GetFilterByName).
+	// Unlike ID it is never derived, and it does not affect FilterID.
+	Name string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+
+	// Tag optionally marks the filter as one of a set of related filters,
+	// e.g. "payments", that operators toggle or remove together (see
+	// EnableTag, DisableTag and RemoveTag). It does not affect matching or
+	// FilterID, and is unrelated to filter groups (SetFilterGroups).
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty" toml:"tag,omitempty"`
+
 	// Type is the attribute key to match (e.g., "job_id", "user_id", "package").
+	// A dotted key such as "labels.env" that names no attribute navigates into
+	// a map-valued (string keys) or group attribute, here "labels". A glob
+	// such as "*_id" matches every attribute whose key it matches; the filter
+	// matches if any of their values does.
 	// Special prefixes:
+	//   - "component" for the logger's component (see WithComponentKey)
+	//   - "message" for the record's message text
+	//   - "has-error" for records at error level or carrying an error (see HasErrorType)
 	//   - "context:key" for context values (e.g., "context:job_id")
+	//   - "any:key" for an attribute or, failing that, a context value (e.g., "any:job_id")
 	//   - "source:file" for source file path filtering
 	//   - "source:function" for function name filtering
-	Type string `json:"type"`
+	//   - "source:package" for the import path of the function's package
+	Type string `json:"type" yaml:"type" toml:"type"`
 
 	// Pattern for matching the attribute value.
 	// Supports simple glob-style patterns:
@@ -38,33 +73,161 @@ type LogFilter struct {
 	//   - "prefix*"  prefix match
 	//   - "*suffix"  suffix match
 	//   - "*contains*" contains match
-	Pattern string `json:"pattern"`
+	//   - "a*b*c"    inner wildcards: segments appear in order, each "*"
+	//                matching any run of characters (including none)
+	//   - "v?"       "?" matches any one character
+	//   - "job_[0-9]*" "[...]" matches one character from a class: "[abc]",
+	//                a range "[0-9]", or negated "[!0-9]" (or "[^0-9]")
+	// In patterns using "?" or "[...]", a backslash escapes the next
+	// character. A "[" that doesn't open a well-formed class leaves the whole
+	// pattern with "*" as its only wildcard.
+	Pattern string `json:"pattern" yaml:"pattern" toml:"pattern"`
+
+	// Negate inverts the pattern match, e.g. Pattern "job_critical" with Negate
+	// matches every job_id except job_critical. The attribute (or context
+	// value, source location, ...) must still be present: a record without a
+	// job_id matches neither way. Under first match wins, a negated filter
+	// claims every other value, so place exceptions for it before it.
+	Negate bool `json:"negate,omitempty" yaml:"negate,omitempty" toml:"negate,omitempty"`
+
+	// Regex treats Pattern as a Go regular expression instead of a glob, e.g.
+	// "job_[0-9a-f]{8}". It must match the whole value. A filter with an
+	// invalid regex never matches, and a warning is logged when it is set.
+	Regex bool `json:"regex,omitempty" yaml:"regex,omitempty" toml:"regex,omitempty"`
+
+	// Numeric treats Pattern as a numeric comparison instead of a glob:
+	// ">500", ">=3", "<10", "<=10", "=42" (or just "42"), or the inclusive
+	// range "100..500". The value must parse as an integer or float; other
+	// values never match, even with Negate. An invalid comparison never
+	// matches, and a warning is logged when it is set. Numeric takes
+	// precedence over Regex.
+	Numeric bool `json:"numeric,omitempty" yaml:"numeric,omitempty" toml:"numeric,omitempty"`
+
+	// Conditions makes this a compound filter: it matches only when all (or,
+	// with Match "any", at least one) of its conditions match, e.g. job_id
+	// "job_*" and env "prod". If Type is set, Type and Pattern (with Negate,
+	// Regex and Numeric) are the first condition; otherwise Type may be empty.
+	// Without Conditions, the filter matches on Type and Pattern alone.
+	Conditions []Condition `json:"conditions,omitempty" yaml:"conditions,omitempty" toml:"conditions,omitempty"`
+
+	// Match combines Conditions: "all" (ConditionsAll, the default) or "any"
+	// (ConditionsAny). It is ignored for filters without Conditions.
+	Match string `json:"match,omitempty" yaml:"match,omitempty" toml:"match,omitempty"`
+
+	// MatchMode matches on whether the value Type names exists, instead of on
+	// Pattern: "present" (MatchPresent) whatever its value, "absent"
+	// (MatchAbsent), "empty" (MatchEmpty) or "nonempty" (MatchNonEmpty), which
+	// also require it to exist. Pattern, Negate, Regex and Numeric are ignored
+	// when it is set, and Pattern may be empty. "errtype" (MatchErrorType)
+	// instead matches Pattern against the type of the error the value holds.
+	MatchMode string `json:"match_mode,omitempty" yaml:"match_mode,omitempty" toml:"match_mode,omitempty"`
+
+	// MatchScope restricts which attributes an attribute filter (or the
+	// attribute half of an "any:" filter) consults: "record"
+	// (MatchScopeRecord) for those passed with the log call, "preformatted"
+	// (MatchScopePreformatted) for those bound to the logger via With, or
+	// "both" (MatchScopeBoth, the default), where the record's take
+	// precedence. With a single scope, a per-call attribute can't match a
+	// filter meant for a bound one of the same key, or vice versa.
+	MatchScope string `json:"match_scope,omitempty" yaml:"match_scope,omitempty" toml:"match_scope,omitempty"`
 
 	// Level is the minimum threshold for logs matching this filter.
 	// Logs below this level are suppressed, logs at or above pass through.
-	// Valid values: "debug", "info", "warn", "error"
-	Level string `json:"level"`
+	// Valid values: "trace", "debug", "info", "warn", "error", a name registered
+	// via RegisterLevelName, or a numeric level (see ParseLevelStrict)
+	Level string `json:"level" yaml:"level" toml:"level"`
 
 	// OutputLevel optionally transforms the log level in the output.
 	// If set, matching logs are emitted at this level instead of their original level.
 	// This is useful for elevating debug logs to info so they appear in normal log streams.
 	// If empty, the original log level is preserved.
-	// Valid values: "", "debug", "info", "warn", "error"
-	OutputLevel string `json:"output_level,omitempty"`
+	// A signed integer such as "+1" or "-1" is relative instead: the record's
+	// level moves that many built-in levels (trace, debug, info, warn, error),
+	// so debug +1 is info, clamped to the range trace..error.
+	// Valid values: "", a signed offset, or any value valid for Level
+	OutputLevel string `json:"output_level,omitempty" yaml:"output_level,omitempty" toml:"output_level,omitempty"`
+
+	// SourceLevels optionally restricts the filter to records whose own level
+	// is one of these, e.g. ["warn"] with OutputLevel "info" downgrades warn
+	// records but leaves error records to the other filters. Records at other
+	// levels are evaluated as if the filter didn't exist. Each entry is any
+	// value valid for Level. If empty, the filter applies at every level.
+	SourceLevels []string `json:"source_levels,omitempty" yaml:"source_levels,omitempty" toml:"source_levels,omitempty"`
+
+	// Priority orders evaluation: filters with a higher Priority are tried
+	// first, and filters with equal Priority (by default 0) in the order they
+	// were set or added. Since the first matching filter wins, a filter can
+	// take precedence over others regardless of where it was added.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty" toml:"priority,omitempty"`
+
+	// Route optionally names a route (see WithRouteOnMatch and
+	// RegisterNamedHandler) that records emitted through this filter are sent
+	// to instead of the inner handler, e.g. to emit them in another format.
+	// An unknown route name is ignored.
+	Route string `json:"route,omitempty" yaml:"route,omitempty" toml:"route,omitempty"`
+
+	// RouteTee sends records to the inner handler as well as the Route.
+	RouteTee bool `json:"route_tee,omitempty" yaml:"route_tee,omitempty" toml:"route_tee,omitempty"`
 
 	// Enabled controls whether this filter is active.
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
 
 	// ExpiresAt is an optional expiry time for temporary filters.
 	// If nil or zero, the filter never expires.
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty" toml:"expires_at,omitempty"`
+
+	// TimeWindow optionally restricts the filter to records whose time falls
+	// within a daily window, e.g. 02:00 to 03:00, optionally on certain
+	// weekdays and in a given time zone. Records outside it are evaluated as
+	// if the filter didn't exist. A TimeWindow that doesn't parse (see
+	// Validate) never matches. If nil, the filter applies at all times.
+	TimeWindow *TimeWindow `json:"time_window,omitempty" yaml:"time_window,omitempty" toml:"time_window,omitempty"`
+
+	// ThrottlePerValue limits matching records to at most one per matched value
+	// per interval, e.g. one debug line per job_id every 5 seconds. Records for
+	// the same value arriving sooner (by record time) are suppressed. Up to
+	// 10000 distinct values are tracked per filter; beyond that the least
+	// recently emitted value is forgotten. Zero disables throttling.
+	// In JSON the interval is given in nanoseconds; in YAML and TOML (see the
+	// filterfile module) as a duration string such as "5s".
+	ThrottlePerValue time.Duration `json:"throttle_per_value,omitempty" yaml:"throttle_per_value,omitempty" toml:"throttle_per_value,omitempty"`
+
+	// MaxPerSecond limits records emitted through this filter to an average of
+	// MaxPerSecond per second across all values, with bursts of up to
+	// MaxPerSecond after a quiet spell. Records over the limit (by record time)
+	// are suppressed, as if below Level. Zero disables the limit.
+	MaxPerSecond int `json:"max_per_second,omitempty" yaml:"max_per_second,omitempty" toml:"max_per_second,omitempty"`
+
+	// MaxHits makes the filter inactive, as if expired, once it has matched
+	// MaxHits records, e.g. "debug this job for the next 100 lines". Every
+	// record the filter matches counts, whether or not it is emitted. The
+	// count is kept with the filter's runtime state, so passing the filter
+	// back to SetFilters keeps it; a new filter, or a copy modified so that
+	// its FilterID changes, starts from zero. Zero means no limit.
+	MaxHits int `json:"max_hits,omitempty" yaml:"max_hits,omitempty" toml:"max_hits,omitempty"`
+
+	// Confirmed acknowledges that a catch-all pattern (such as "*") is intended.
+	// It is only consulted when the handler is created with WithRejectMatchAll.
+	Confirmed bool `json:"confirmed,omitempty" yaml:"confirmed,omitempty" toml:"confirmed,omitempty"`
 
 	// Cached fields — set by prepare(), not serialized.
-	kind              filterKind `json:"-"` // Pre-classified filter kind
-	parsedLevel       slog.Level `json:"-"` // Cached ParseLevel(Level)
-	parsedOutputLevel slog.Level `json:"-"` // Cached ParseLevel(OutputLevel)
-	contextKey        string     `json:"-"` // Cached context key (trimmed prefix)
-	attributeKey      string     `json:"-"` // Cached attribute key
+	kind              filterKind  `json:"-"` // Pre-classified filter kind
+	parsedLevel       slog.Level  `json:"-"` // Cached ParseLevel(Level)
+	parsedOutputLevel slog.Level  `json:"-"` // Cached ParseLevel(OutputLevel)
+	outputOffset      int         `json:"-"` // Cached relative OutputLevel, see hasOutputOffset
+	hasOutputOffset   bool        `json:"-"` // OutputLevel is a signed offset
+	contextKey        string      `json:"-"` // Cached context key (trimmed prefix)
+	attributeKey      string      `json:"-"` // Cached attribute key
+	keyPath           bool        `json:"-"` // Attribute key is dotted, may navigate into a value
+	matcher           Matcher     `json:"-"` // Cached compiled Pattern
+	conditions        []LogFilter `json:"-"` // Prepared Conditions, see prepareConditions
+	derivedID         string      `json:"-"` // Unique content-derived ID, see FilterID
+
+	sourceLevels []slog.Level `json:"-"` // Parsed SourceLevels
+	window       *timeWindow  `json:"-"` // Parsed TimeWindow
+	ctxLabels    *Matcher     `json:"-"` // Label names a wildcard context key matches
+	keyGlob      *Matcher     `json:"-"` // Attribute keys a glob Type matches
+	state        *filterState `json:"-"` // Runtime state, kept across prepare()
 }
 
 // prepare pre-computes cached fields from the JSON-serializable fields.
@@ -72,43 +235,170 @@ type LogFilter struct {
 // in the hot path. Handler.SetFilters and Handler.AddFilter call this automatically.
 func (f *LogFilter) prepare() {
 	// Classify the filter kind
+	f.ctxLabels = nil
+	f.keyGlob = nil
 	switch {
 	case f.Type == SourceFilePrefix:
 		f.kind = filterKindSourceFile
 	case f.Type == SourceFunctionPrefix:
 		f.kind = filterKindSourceFunction
+	case f.Type == SourcePackagePrefix:
+		f.kind = filterKindSourcePackage
+	case f.Type == ComponentType:
+		f.kind = filterKindComponent
+	case f.Type == MessageType:
+		f.kind = filterKindMessage
+	case f.Type == HasErrorType:
+		f.kind = filterKindHasError
 	case strings.HasPrefix(f.Type, ContextPrefix):
 		f.kind = filterKindContext
 		f.contextKey = strings.TrimPrefix(f.Type, ContextPrefix)
+		if isLabelWildcard(f.contextKey) {
+			_, label, _ := contextLabel(f.contextKey)
+			m := NewMatcher(label)
+			f.ctxLabels = &m
+		}
+	case strings.HasPrefix(f.Type, AnyPrefix):
+		f.kind = filterKindAny
+		f.attributeKey = strings.TrimPrefix(f.Type, AnyPrefix)
+		f.contextKey = f.attributeKey
 	default:
 		f.kind = filterKindAttribute
 		f.attributeKey = f.Type
+		f.keyPath = strings.Contains(f.Type, ".")
+		if isKeyGlob(f.Type) {
+			m := NewMatcher(f.Type)
+			f.keyGlob = &m
+			f.keyPath = false
+		}
 	}
 
+	f.matcher = f.Matcher()
+	f.prepareConditions()
+	f.prepareState()
+
 	// Cache parsed levels
 	f.parsedLevel = ParseLevel(f.Level)
+	f.outputOffset, f.hasOutputOffset = parseLevelOffset(f.OutputLevel)
+	f.sourceLevels = nil
+	for _, l := range f.SourceLevels {
+		f.sourceLevels = append(f.sourceLevels, ParseLevel(l))
+	}
+	f.window = nil
+	if f.TimeWindow != nil {
+		if w, err := parseTimeWindow(f.TimeWindow); err == nil {
+			f.window = w
+		} else {
+			f.window = &timeWindow{invalid: true}
+		}
+	}
 	if f.OutputLevel != "" {
 		f.parsedOutputLevel = ParseLevel(f.OutputLevel)
 	}
 }
 
-// IsExpired returns true if the filter has expired.
+// prepareState gives the filter runtime state if it has none, and the
+// throttle and rate limiter its settings need.
+func (f *LogFilter) prepareState() {
+	if f.state == nil {
+		f.state = &filterState{}
+	}
+	if f.ThrottlePerValue > 0 && f.state.throttle == nil {
+		f.state.throttle = newValueThrottle()
+	}
+	if f.MaxPerSecond > 0 && f.state.rate == nil {
+		f.state.rate = &rateLimiter{}
+	}
+}
+
+// IsExpired returns true if the filter has expired, according to the
+// package-level clock (see SetDefaultClock).
 func (f *LogFilter) IsExpired() bool {
-	if f.ExpiresAt == nil || f.ExpiresAt.IsZero() {
+	return f.IsExpiredAt(now())
+}
+
+// IsExpiredAt returns true if the filter has expired as of t.
+func (f *LogFilter) IsExpiredAt(t time.Time) bool {
+	if !f.expires() {
 		return false
 	}
-	return time.Now().After(*f.ExpiresAt)
+	return t.After(*f.ExpiresAt)
+}
+
+// IsExhausted returns true if the filter has matched MaxHits records.
+func (f *LogFilter) IsExhausted() bool {
+	return f.MaxHits > 0 && f.state != nil && f.state.hits.Load() >= int64(f.MaxHits)
 }
 
-// IsActive returns true if the filter is enabled and not expired.
+// IsActive returns true if the filter is enabled, not expired and not
+// exhausted, according to the package-level clock (see SetDefaultClock).
 func (f *LogFilter) IsActive() bool {
-	return f.Enabled && !f.IsExpired()
+	return f.Enabled && !f.IsExpired() && !f.IsExhausted()
+}
+
+// IsActiveAt returns true if the filter is enabled, not expired and not
+// exhausted as of t.
+func (f *LogFilter) IsActiveAt(t time.Time) bool {
+	return f.Enabled && !f.IsExpiredAt(t) && !f.IsExhausted()
+}
+
+// expires reports whether the filter has an expiry time.
+func (f *LogFilter) expires() bool {
+	return f.ExpiresAt != nil && !f.ExpiresAt.IsZero()
 }
 
-// Matches checks if the given value matches the filter pattern.
-// Returns true if the pattern matches.
+// Matches checks if the given value matches the filter pattern, inverted if
+// Negate is set. It is a convenience wrapper around Matcher; callers matching
+// many values should compile the Matcher once.
 func (f *LogFilter) Matches(value string) bool {
-	return matchPattern(f.Pattern, value)
+	return f.matchValue(f.Matcher(), value)
+}
+
+// matchValue applies m, the filter's compiled pattern, honoring Negate.
+// A Numeric or Regex filter with an invalid pattern never matches, negated
+// or not.
+func (f *LogFilter) matchValue(m Matcher, value string) bool {
+	if f.strictPattern() && m.kind == matchNone {
+		return false
+	}
+	if m.kind == matchNumeric {
+		// A non-numeric value matches neither way, like an absent one
+		v, ok := parseNumber(value)
+		return ok && m.num.contains(v) != f.Negate
+	}
+	return m.Match(value) != f.Negate
+}
+
+// IsMatchAll returns true if the filter matches every value: a pattern such
+// as "*", a regex such as ".*" (see Regex), or with Negate, an empty pattern. A compound filter matches every
+// value if all its conditions do, or with Match "any", if one does.
+func (f *LogFilter) IsMatchAll() bool {
+	if len(f.Conditions) > 0 {
+		return f.conditionsMatchAll()
+	}
+	return f.patternMatchAll()
+}
+
+// patternMatchAll reports whether Pattern, with Negate, matches every value.
+// A MatchMode filter matches on presence or error type rather than value, so
+// never does.
+func (f *LogFilter) patternMatchAll() bool {
+	if f.MatchMode != "" {
+		return false
+	}
+	if f.Negate {
+		return f.Matcher().kind == matchNone && !f.strictPattern()
+	}
+	if f.Regex && !f.Numeric {
+		return regexMatchAll(f.Pattern)
+	}
+	return f.Matcher().kind == matchAll
+}
+
+// strictPattern reports whether the pattern is compiled in a mode where it can
+// be invalid (Numeric or Regex), rather than as an always-valid glob.
+func (f *LogFilter) strictPattern() bool {
+	return f.Numeric || f.Regex
 }
 
 // IsContextFilter returns true if this filter checks context values.
@@ -116,18 +406,28 @@ func (f *LogFilter) IsContextFilter() bool {
 	return strings.HasPrefix(f.Type, ContextPrefix)
 }
 
-// ContextKey returns the context key for context filters.
-// Returns empty string if not a context filter.
+// IsAnyFilter returns true if this filter checks an attribute and a context
+// value under the same key.
+func (f *LogFilter) IsAnyFilter() bool {
+	return strings.HasPrefix(f.Type, AnyPrefix)
+}
+
+// ContextKey returns the context key for context and any: filters.
+// Returns empty string otherwise.
 func (f *LogFilter) ContextKey() string {
+	if f.IsAnyFilter() {
+		return strings.TrimPrefix(f.Type, AnyPrefix)
+	}
 	if !f.IsContextFilter() {
 		return ""
 	}
 	return strings.TrimPrefix(f.Type, ContextPrefix)
 }
 
-// IsSourceFilter returns true if this filter checks source file or function.
+// IsSourceFilter returns true if this filter checks source file, function or
+// package.
 func (f *LogFilter) IsSourceFilter() bool {
-	return f.IsSourceFileFilter() || f.IsSourceFunctionFilter()
+	return f.IsSourceFileFilter() || f.IsSourceFunctionFilter() || f.IsSourcePackageFilter()
 }
 
 // IsSourceFileFilter returns true if this filter checks source file path.
@@ -140,9 +440,22 @@ func (f *LogFilter) IsSourceFunctionFilter() bool {
 	return f.Type == SourceFunctionPrefix
 }
 
-// AttributeKey returns the attribute key for attribute filters.
+// IsSourcePackageFilter returns true if this filter checks the package path.
+func (f *LogFilter) IsSourcePackageFilter() bool {
+	return f.Type == SourcePackagePrefix
+}
+
+// IsMessageFilter returns true if this filter matches the record's message.
+func (f *LogFilter) IsMessageFilter() bool {
+	return f.Type == MessageType
+}
+
+// AttributeKey returns the attribute key for attribute and any: filters.
 // Returns the type as-is for non-context and non-source filters.
 func (f *LogFilter) AttributeKey() string {
+	if f.IsAnyFilter() {
+		return strings.TrimPrefix(f.Type, AnyPrefix)
+	}
 	if f.IsContextFilter() || f.IsSourceFilter() {
 		return ""
 	}
@@ -154,11 +467,15 @@ func (f *LogFilter) HasOutputLevel() bool {
 	return f.OutputLevel != ""
 }
 
-// GetOutputLevel returns the parsed output level, or the original level if not set.
+// GetOutputLevel returns the parsed output level, the original level moved by
+// a relative OutputLevel, or the original level if not set.
 func (f *LogFilter) GetOutputLevel(originalLevel slog.Level) slog.Level {
 	if f.OutputLevel == "" {
 		return originalLevel
 	}
+	if steps, ok := parseLevelOffset(f.OutputLevel); ok {
+		return offsetLevel(originalLevel, steps)
+	}
 	return ParseLevel(f.OutputLevel)
 }
 
@@ -174,23 +491,20 @@ func (f *LogFilter) cachedOutputLevel(originalLevel slog.Level) slog.Level {
 	if f.OutputLevel == "" {
 		return originalLevel
 	}
+	if f.hasOutputOffset {
+		return offsetLevel(originalLevel, f.outputOffset)
+	}
 	return f.parsedOutputLevel
 }
 
-// ParseLevel converts a level string to slog.Level.
+// ParseLevel converts a level string to slog.Level, as ParseLevelStrict does,
+// but returns slog.LevelInfo for unrecognized strings.
 func ParseLevel(level string) slog.Level {
-	switch strings.ToLower(strings.TrimSpace(level)) {
-	case "debug":
-		return slog.LevelDebug
-	case "info":
-		return slog.LevelInfo
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
+	l, err := ParseLevelStrict(level)
+	if err != nil {
 		return slog.LevelInfo
 	}
+	return l
 }
 
 // matchPattern performs fast glob-style pattern matching.
@@ -199,35 +513,8 @@ func ParseLevel(level string) slog.Level {
 //   - "prefix*"    prefix match (HasPrefix)
 //   - "*suffix"    suffix match (HasSuffix)
 //   - "*contains*" contains match (Contains)
+//   - "a*b*c"      segments in order
+//   - "v?", "[0-9]" one character, from a class for "[...]"
 func matchPattern(pattern, value string) bool {
-	if pattern == "" {
-		return false
-	}
-
-	startsWithWildcard := strings.HasPrefix(pattern, "*")
-	endsWithWildcard := strings.HasSuffix(pattern, "*")
-
-	switch {
-	case startsWithWildcard && endsWithWildcard:
-		// *contains* - check if value contains the middle part
-		middle := strings.TrimSuffix(strings.TrimPrefix(pattern, "*"), "*")
-		if middle == "" {
-			return true // Pattern is just "*" or "**", matches everything
-		}
-		return strings.Contains(value, middle)
-
-	case endsWithWildcard:
-		// prefix* - check if value starts with prefix
-		prefix := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(value, prefix)
-
-	case startsWithWildcard:
-		// *suffix - check if value ends with suffix
-		suffix := strings.TrimPrefix(pattern, "*")
-		return strings.HasSuffix(value, suffix)
-
-	default:
-		// Exact match
-		return pattern == value
-	}
+	return NewMatcher(pattern).Match(value)
 }
@@ -136,6 +136,7 @@ func TestLogFilter_ContextKey(t *testing.T) {
 		{"context:job_id", "job_id"},
 		{"context:", ""},
 		{"job_id", ""},
+		{"any:job_id", "job_id"},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +179,7 @@ func TestLogFilter_IsSourceFilter(t *testing.T) {
 	}{
 		{SourceFilePrefix, true},
 		{SourceFunctionPrefix, true},
+		{SourcePackagePrefix, true},
 		{"source:other", false},
 		{"context:user_id", false},
 		{"job_id", false},
@@ -236,6 +238,27 @@ func TestLogFilter_IsSourceFunctionFilter(t *testing.T) {
 	}
 }
 
+func TestLogFilter_IsSourcePackageFilter(t *testing.T) {
+	tests := []struct {
+		filterType string
+		want       bool
+	}{
+		{SourcePackagePrefix, true},
+		{SourceFunctionPrefix, false},
+		{SourceFilePrefix, false},
+		{"job_id", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterType, func(t *testing.T) {
+			f := LogFilter{Type: tt.filterType}
+			if got := f.IsSourcePackageFilter(); got != tt.want {
+				t.Errorf("IsSourcePackageFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLogFilter_AttributeKey_WithSourceFilters(t *testing.T) {
 	tests := []struct {
 		filterType string
@@ -244,8 +267,10 @@ func TestLogFilter_AttributeKey_WithSourceFilters(t *testing.T) {
 		{"job_id", "job_id"},
 		{"user_id", "user_id"},
 		{"context:job_id", ""},
+		{"any:job_id", "job_id"},
 		{SourceFilePrefix, ""},
 		{SourceFunctionPrefix, ""},
+		{SourcePackagePrefix, ""},
 	}
 
 	for _, tt := range tests {
@@ -257,3 +282,65 @@ func TestLogFilter_AttributeKey_WithSourceFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestLogFilter_IsMatchAll(t *testing.T) {
+	tests := []struct {
+		pattern string
+		regex   bool
+		want    bool
+	}{
+		{"*", false, true},
+		{"**", false, true},
+		{"", false, false},
+		{"job_*", false, false},
+		{"*job*", false, false},
+		{".*", true, true},
+		{"(?s).*", true, true},
+		{"^(.*)$", true, true},
+		{"[\\s\\S]*", true, true},
+		{"job_.*|.*", true, true},
+		{".*.*", true, true},
+		{"*", true, false},
+		{".+", true, false},
+		{"job_.*", true, false},
+		{".*_id", true, false},
+		{"(", true, false},
+	}
+
+	for _, tt := range tests {
+		f := LogFilter{Pattern: tt.pattern, Regex: tt.regex}
+		if got := f.IsMatchAll(); got != tt.want {
+			t.Errorf("IsMatchAll(%q, regex=%v) = %v, want %v", tt.pattern, tt.regex, got, tt.want)
+		}
+	}
+}
+
+func TestLogFilter_Negate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter LogFilter
+		value  string
+		want   bool
+	}{
+		{"excluded value", LogFilter{Pattern: "job_critical", Negate: true}, "job_critical", false},
+		{"other value", LogFilter{Pattern: "job_critical", Negate: true}, "job_1", true},
+		{"negated glob", LogFilter{Pattern: "internal/*", Negate: true}, "vendor/x.go", true},
+		{"negated match all", LogFilter{Pattern: "*", Negate: true}, "anything", false},
+		{"negated invalid regex", LogFilter{Pattern: "job_[", Regex: true, Negate: true}, "job_1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.value); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if !(&LogFilter{Pattern: "", Negate: true}).IsMatchAll() {
+		t.Error("Expected negated empty pattern to be match-all")
+	}
+	if (&LogFilter{Pattern: "*", Negate: true}).IsMatchAll() {
+		t.Error("Expected negated \"*\" not to be match-all")
+	}
+}
@@ -0,0 +1,51 @@
+// Package filterfile reads and writes logfilter filters as YAML and TOML, for
+// applications whose configuration isn't JSON:
+//
+//	f, err := os.Open("/etc/myapp/filters.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	filters, err := filterfile.LoadFiltersYAML(f)
+//	if err != nil {
+//	    return err
+//	}
+//	logfilter.SetFilters(filters)
+//
+// Field names are those of the JSON encoding (see logfilter.LogFilter), and
+// levels are kept as strings. As with logfilter.LoadFiltersFromFile, unknown
+// levels are rejected rather than read as info.
+//
+// This package lives in its own module so the core logfilter package stays
+// free of dependencies.
+package filterfile
+
+import (
+	"fmt"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+)
+
+// checkLevels reports the first filter with a Level, OutputLevel or
+// SourceLevels entry that logfilter.ParseLevelStrict doesn't recognize.
+func checkLevels(filters []logfilter.LogFilter) error {
+	for i, f := range filters {
+		levels := append([]string{f.Level, f.OutputLevel}, f.SourceLevels...)
+		for j, level := range levels {
+			if level == "" {
+				continue
+			}
+			if _, err := logfilter.ParseLevelStrict(level); err != nil {
+				field := "source_levels"
+				switch j {
+				case 0:
+					field = "level"
+				case 1:
+					field = "output_level"
+				}
+				return fmt.Errorf("filterfile: filter %d (type %q, pattern %q): unknown %s %q", i, f.Type, f.Pattern, field, level)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package filterfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.yaml.in/yaml/v3"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+)
+
+// sampleFilters exercises every kind of field: strings, bools, ints,
+// durations, the ExpiresAt pointer, slices and conditions.
+func sampleFilters() []logfilter.LogFilter {
+	expiry := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	return []logfilter.LogFilter{
+		{
+			ID: "job-debug", Name: "checkout", Type: "job_id", Pattern: "job_*",
+			Level: "debug", OutputLevel: "+1", Enabled: true, ExpiresAt: &expiry,
+			ThrottlePerValue: 5 * time.Second, MaxHits: 100, Priority: 2,
+		},
+		{Type: "duration_ms", Pattern: ">500", Numeric: true, Negate: true, Level: "WARN", SourceLevels: []string{"info", "warn"}},
+		{
+			Type: "env", Pattern: "prod", Level: "trace", Match: logfilter.ConditionsAny, MatchScope: logfilter.MatchScopePreformatted,
+			Conditions: []logfilter.Condition{
+				{Type: "service", Pattern: "api"},
+				{Type: "trace_id", MatchMode: logfilter.MatchPresent},
+			},
+		},
+	}
+}
+
+func TestYAML_RoundTrip(t *testing.T) {
+	filters := sampleFilters()
+	data, err := MarshalFiltersYAML(filters)
+	if err != nil {
+		t.Fatalf("MarshalFiltersYAML returned error: %v", err)
+	}
+	if !strings.Contains(string(data), "throttle_per_value: 5s") || !strings.Contains(string(data), "level: WARN") {
+		t.Errorf("Expected readable durations and levels kept as strings, got:\n%s", data)
+	}
+
+	loaded, err := LoadFiltersYAML(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadFiltersYAML returned error: %v", err)
+	}
+	if !reflect.DeepEqual(filters, loaded) {
+		t.Errorf("Expected round trip to preserve filters\nwant %+v\ngot  %+v", filters, loaded)
+	}
+}
+
+func TestTOML_RoundTrip(t *testing.T) {
+	filters := sampleFilters()
+	data, err := MarshalFiltersTOML(filters)
+	if err != nil {
+		t.Fatalf("MarshalFiltersTOML returned error: %v", err)
+	}
+	if !strings.Contains(string(data), "[[filters]]") {
+		t.Errorf("Expected an array of tables named filters, got:\n%s", data)
+	}
+
+	loaded, err := LoadFiltersTOML(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadFiltersTOML returned error: %v", err)
+	}
+	if !reflect.DeepEqual(filters, loaded) {
+		t.Errorf("Expected round trip to preserve filters\nwant %+v\ngot  %+v", filters, loaded)
+	}
+}
+
+func TestLoadFiltersYAML_Handwritten(t *testing.T) {
+	src := `
+- type: job_id
+  pattern: job_*
+  level: debug
+  enabled: true
+  expires_at: 2026-03-01T12:00:00Z
+  throttle_per_value: 5s
+`
+	filters, err := LoadFiltersYAML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("LoadFiltersYAML returned error: %v", err)
+	}
+	if len(filters) != 1 || filters[0].ThrottlePerValue != 5*time.Second || filters[0].ExpiresAt == nil || !filters[0].Enabled {
+		t.Errorf("Expected parsed filter, got %+v", filters)
+	}
+
+	if filters, err := LoadFiltersYAML(strings.NewReader("")); err != nil || len(filters) != 0 {
+		t.Errorf("Expected empty input to give no filters, got %v, %v", filters, err)
+	}
+}
+
+func TestLoadFiltersTOML_Handwritten(t *testing.T) {
+	src := `
+[[filters]]
+type = "job_id"
+pattern = "job_*"
+level = "debug"
+enabled = true
+expires_at = 2026-03-01T12:00:00Z
+throttle_per_value = "5s"
+`
+	filters, err := LoadFiltersTOML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("LoadFiltersTOML returned error: %v", err)
+	}
+	if len(filters) != 1 || filters[0].ThrottlePerValue != 5*time.Second || filters[0].ExpiresAt == nil || !filters[0].Enabled {
+		t.Errorf("Expected parsed filter, got %+v", filters)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		load    func(string) error
+		src     string
+		wantErr string
+	}{
+		{"yaml unknown level", loadYAML, "- {type: a, pattern: b, level: verbose}", `unknown level "verbose"`},
+		{"yaml unknown output level", loadYAML, "- {type: a, pattern: b, output_level: loud}", `unknown output_level "loud"`},
+		{"yaml unknown source level", loadYAML, "- {type: a, pattern: b, source_levels: [warn, oops]}", `unknown source_levels "oops"`},
+		{"yaml unknown field", loadYAML, "- {type: a, pattern: b, levle: debug}", "levle"},
+		{"yaml malformed", loadYAML, "- {type: ", "parsing YAML"},
+		{"toml unknown level", loadTOML, "[[filters]]\ntype = \"a\"\nlevel = \"verbose\"", `unknown level "verbose"`},
+		{"toml unknown field", loadTOML, "[[filters]]\ntype = \"a\"\nlevle = \"debug\"", "levle"},
+		{"toml malformed", loadTOML, "[[filters]\n", "parsing TOML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.load(tt.src); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func loadYAML(src string) error {
+	_, err := LoadFiltersYAML(strings.NewReader(src))
+	return err
+}
+
+func loadTOML(src string) error {
+	_, err := LoadFiltersTOML(strings.NewReader(src))
+	return err
+}
+
+func TestSuppression_Tags(t *testing.T) {
+	want := []logfilter.Suppression{
+		{Key: "path", Pattern: "/health*", AllowOverride: true},
+		{Key: "user_agent", Pattern: "kube-probe/*"},
+	}
+
+	var fromYAML []logfilter.Suppression
+	yamlDoc := `
+- {key: path, pattern: "/health*", allow_override: true}
+- {key: user_agent, pattern: "kube-probe/*"}
+`
+	if err := yaml.Unmarshal([]byte(yamlDoc), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	var fromTOML struct {
+		Suppressions []logfilter.Suppression `toml:"suppressions"`
+	}
+	tomlDoc := `
+[[suppressions]]
+key = "path"
+pattern = "/health*"
+allow_override = true
+
+[[suppressions]]
+key = "user_agent"
+pattern = "kube-probe/*"
+`
+	if _, err := toml.Decode(tomlDoc, &fromTOML); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, got := range map[string][]logfilter.Suppression{"yaml": fromYAML, "toml": fromTOML.Suppressions} {
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %d suppressions, got %+v", name, len(want), got)
+		}
+		for i := range want {
+			if got[i].Key != want[i].Key || got[i].Pattern != want[i].Pattern || got[i].AllowOverride != want[i].AllowOverride {
+				t.Errorf("%s: expected %+v, got %+v", name, want[i], got[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/jmylchreest/slog-logfilter/filterfile
+
+go 1.22
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/jmylchreest/slog-logfilter v0.1.0
+	go.yaml.in/yaml/v3 v3.0.5
+)
@@ -0,0 +1,53 @@
+package filterfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	logfilter "github.com/jmylchreest/slog-logfilter"
+)
+
+// tomlDocument is the top level of a TOML filter file. TOML documents are
+// tables, so the filters are an array of tables named "filters".
+type tomlDocument struct {
+	Filters []logfilter.LogFilter `toml:"filters"`
+}
+
+// LoadFiltersTOML reads filters from r, given as an array of tables named
+// "filters":
+//
+//	[[filters]]
+//	type = "job_id"
+//	pattern = "job_*"
+//	level = "debug"
+//	enabled = true
+//	expires_at = 2026-03-01T12:00:00Z
+//	throttle_per_value = "5s"
+//
+// Keys that aren't filter fields are errors, to catch typos.
+func LoadFiltersTOML(r io.Reader) ([]logfilter.LogFilter, error) {
+	var doc tomlDocument
+	md, err := toml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("filterfile: parsing TOML: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("filterfile: parsing TOML: unknown key %q", undecoded[0].String())
+	}
+	if err := checkLevels(doc.Filters); err != nil {
+		return nil, err
+	}
+	return doc.Filters, nil
+}
+
+// MarshalFiltersTOML encodes filters as an array of tables named "filters"
+// that LoadFiltersTOML reads back.
+func MarshalFiltersTOML(filters []logfilter.LogFilter) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tomlDocument{Filters: filters}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package filterfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+	"go.yaml.in/yaml/v3"
+)
+
+// LoadFiltersYAML reads a YAML sequence of filters from r:
+//
+//   - type: job_id
+//     pattern: job_*
+//     level: debug
+//     enabled: true
+//     expires_at: 2026-03-01T12:00:00Z
+//     throttle_per_value: 5s
+func LoadFiltersYAML(r io.Reader) ([]logfilter.LogFilter, error) {
+	var filters []logfilter.LogFilter
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&filters); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("filterfile: parsing YAML: %w", err)
+	}
+	if err := checkLevels(filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// MarshalFiltersYAML encodes filters as a YAML sequence that LoadFiltersYAML
+// reads back.
+func MarshalFiltersYAML(filters []logfilter.LogFilter) ([]byte, error) {
+	if filters == nil {
+		filters = []logfilter.LogFilter{} // Write [] rather than null
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(filters); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
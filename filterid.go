@@ -0,0 +1,140 @@
+package logfilter
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// DeriveFilterID returns the content-derived ID of a filter: "f-" followed by
+// the hex FNV-1a 64-bit hash of its Type, Pattern, Level and OutputLevel
+// (and Regex, Numeric, Negate, Conditions, Match, MatchMode, MatchScope,
+// SourceLevels and TimeWindow, when set).
+// The same content always yields the same ID, across processes and restarts.
+// Other fields (Enabled, ExpiresAt, ...) do not contribute, so toggling a
+// filter keeps its ID, while changing its level gives it a new one.
+func DeriveFilterID(f LogFilter) string {
+	h := fnv.New64a()
+	for _, s := range [...]string{f.Type, f.Pattern, f.Level, f.OutputLevel} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	if f.Regex {
+		h.Write([]byte("regex\x00"))
+	}
+	if f.Numeric {
+		h.Write([]byte("numeric\x00"))
+	}
+	if f.Negate {
+		h.Write([]byte("negate\x00"))
+	}
+	for _, c := range f.Conditions {
+		h.Write([]byte("condition\x00" + c.Type + "\x00" + c.Pattern + "\x00"))
+		if c.MatchMode != "" {
+			h.Write([]byte("match_mode\x00" + c.MatchMode + "\x00"))
+		}
+		if c.MatchScope != "" {
+			h.Write([]byte("match_scope\x00" + c.MatchScope + "\x00"))
+		}
+	}
+	if f.MatchMode != "" {
+		h.Write([]byte("match_mode\x00" + f.MatchMode + "\x00"))
+	}
+	if f.MatchScope != "" {
+		h.Write([]byte("match_scope\x00" + f.MatchScope + "\x00"))
+	}
+	if f.Match != "" {
+		h.Write([]byte("match\x00" + f.Match + "\x00"))
+	}
+	for _, l := range f.SourceLevels {
+		h.Write([]byte("source_level\x00" + l + "\x00"))
+	}
+	if w := f.TimeWindow; w != nil {
+		h.Write([]byte("time_window\x00" + w.Start + "\x00" + w.End + "\x00" + w.Timezone + "\x00"))
+		for _, d := range w.Weekdays {
+			h.Write([]byte("weekday\x00" + d + "\x00"))
+		}
+	}
+	return fmt.Sprintf("f-%016x", h.Sum64())
+}
+
+// FilterID returns the filter's ID if set, otherwise its derived ID.
+//
+// For filters held by a Handler (including copies from GetFilters), derived
+// IDs are unique within the filter list: when two filters derive the same ID,
+// typically because they are duplicates, later ones get a "-2", "-3", ...
+// suffix in list order. For other filters it is DeriveFilterID(f).
+func (f *LogFilter) FilterID() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	if f.derivedID != "" {
+		return f.derivedID
+	}
+	return DeriveFilterID(*f)
+}
+
+// assignDerivedIDs caches a unique derived ID on each filter in the list
+// without an explicit ID, suffixing collisions with a counter.
+func assignDerivedIDs(filters []LogFilter) {
+	taken := make(map[string]bool, len(filters))
+	for i := range filters {
+		if filters[i].ID != "" {
+			taken[filters[i].ID] = true
+		}
+	}
+	for i := range filters {
+		f := &filters[i]
+		if f.ID != "" {
+			f.derivedID = ""
+			continue
+		}
+		base := DeriveFilterID(*f)
+		id := base
+		for n := 2; taken[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[id] = true
+		f.derivedID = id
+	}
+}
+
+// RemoveFilterByID removes the filter whose FilterID is id, reporting whether
+// one was found. This works for derived IDs as well as explicit ones.
+func (h *Handler) RemoveFilterByID(id string) bool {
+	return h.removeFirstFilter(func(f *LogFilter) bool { return f.FilterID() == id })
+}
+
+// removeFirstFilter removes the first filter for which match returns true,
+// reporting whether there was one.
+func (h *Handler) removeFirstFilter(match func(f *LogFilter) bool) bool {
+	h.filtersLock.Lock()
+	defer h.filtersLock.Unlock()
+
+	filtered := make([]LogFilter, 0, len(h.filters))
+	removed := false
+	for i := range h.filters {
+		if !removed && match(&h.filters[i]) {
+			removed = true
+			continue
+		}
+		filtered = append(filtered, h.filters[i])
+	}
+	if removed {
+		h.filters = filtered
+		h.updateLowestLevel()
+	}
+	return removed
+}
+
+// RemoveFilterByID removes the filter with the given ID from the global
+// handler, reporting whether one was found.
+func RemoveFilterByID(id string) bool {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.RemoveFilterByID(id)
+	}
+	return false
+}
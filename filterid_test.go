@@ -0,0 +1,91 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+func TestDeriveFilterID(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true}
+
+	id := DeriveFilterID(f)
+	if !regexp.MustCompile(`^f-[0-9a-f]{16}$`).MatchString(id) {
+		t.Errorf("Expected f-<16 hex digits>, got %q", id)
+	}
+	if id != DeriveFilterID(LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug"}) {
+		t.Error("Expected Enabled not to affect the derived ID")
+	}
+
+	changed := []LogFilter{
+		{Type: "user_id", Pattern: "job_*", Level: "debug"},
+		{Type: "job_id", Pattern: "job_1", Level: "debug"},
+		{Type: "job_id", Pattern: "job_*", Level: "info"},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "info"},
+		// Field boundaries are delimited, so shifting text between fields differs
+		{Type: "job_idjob_*", Pattern: "", Level: "debug"},
+	}
+	for _, c := range changed {
+		if DeriveFilterID(c) == id {
+			t.Errorf("Expected different ID for %+v", c)
+		}
+	}
+}
+
+func TestLogFilter_FilterID(t *testing.T) {
+	explicit := LogFilter{ID: "mine", Type: "job_id", Pattern: "x"}
+	if got := explicit.FilterID(); got != "mine" {
+		t.Errorf("Expected explicit ID, got %q", got)
+	}
+	derived := LogFilter{Type: "job_id", Pattern: "x"}
+	if got := derived.FilterID(); got != DeriveFilterID(derived) {
+		t.Errorf("Expected derived ID, got %q", got)
+	}
+}
+
+func TestHandler_DerivedIDCollisions(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	dup := LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true}
+	base := DeriveFilterID(dup)
+	handler.SetFilters([]LogFilter{dup, dup, {ID: base + "-2", Type: "other", Pattern: "y", Enabled: true}, dup})
+
+	filters := handler.GetFilters()
+	want := []string{base, base + "-3", base + "-2", base + "-4"}
+	for i, f := range filters {
+		if got := f.FilterID(); got != want[i] {
+			t.Errorf("Filter %d: expected ID %q, got %q", i, want[i], got)
+		}
+	}
+
+	// The same list yields the same IDs on a fresh handler (e.g. after restart)
+	other := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	other.SetFilters([]LogFilter{dup, dup})
+	if got := other.GetFilters()[1].FilterID(); got != base+"-2" {
+		t.Errorf("Expected %q, got %q", base+"-2", got)
+	}
+}
+
+func TestHandler_RemoveFilterByID(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	a := LogFilter{Type: "job_id", Pattern: "a", Level: "debug", Enabled: true}
+	b := LogFilter{ID: "b", Type: "job_id", Pattern: "b", Level: "debug", Enabled: true}
+	handler.SetFilters([]LogFilter{a, b})
+
+	if !handler.RemoveFilterByID(DeriveFilterID(a)) {
+		t.Error("Expected removal by derived ID to succeed")
+	}
+	if !handler.RemoveFilterByID("b") {
+		t.Error("Expected removal by explicit ID to succeed")
+	}
+	if handler.RemoveFilterByID("b") {
+		t.Error("Expected removal of missing ID to report false")
+	}
+	if n := len(handler.GetFilters()); n != 0 {
+		t.Errorf("Expected no filters left, got %d", n)
+	}
+}
@@ -0,0 +1,71 @@
+package logfilter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// firehose is a debugging tap that receives every record at or above level,
+// independent of filters and the global level.
+type firehose struct {
+	handler slog.Handler
+	level   slog.Level
+}
+
+// SetFirehose tees every record at or above level to w as JSON, regardless of
+// filters or the global level. The main output is unaffected: records that are
+// suppressed by filters are still suppressed there, and output level
+// transformations are not applied to the firehose copy.
+//
+// This is intended for ad-hoc investigation, e.g. pairing with a RingBufferWriter
+// to keep recent debug records in memory. Passing a nil writer removes the firehose.
+func (h *Handler) SetFirehose(w io.Writer, level slog.Level) {
+	if w == nil {
+		h.firehose.Store(nil)
+		return
+	}
+	h.SetFirehoseHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}), level)
+}
+
+// SetFirehoseHandler is like SetFirehose but sends records to an arbitrary
+// handler, such as a RingBufferHandler. Passing a nil handler removes the firehose.
+func (h *Handler) SetFirehoseHandler(handler slog.Handler, level slog.Level) {
+	if handler == nil {
+		h.firehose.Store(nil)
+		return
+	}
+	h.firehose.Store(&firehose{handler: handler, level: level})
+}
+
+// ClearFirehose removes the firehose, if any.
+func (h *Handler) ClearFirehose() {
+	h.firehose.Store(nil)
+}
+
+// tapFirehose sends the record to the firehose if one is set and the record is
+// at or above its level. Firehose errors are ignored so they cannot affect the
+// main output.
+func (h *Handler) tapFirehose(ctx context.Context, r slog.Record) {
+	fh := h.firehose.Load()
+	if fh == nil || r.Level < fh.level {
+		return
+	}
+	_ = h.scoped(&h.firehoseCache, fh, fh.handler).Handle(ctx, r.Clone())
+}
+
+// scoped returns source with the handler's WithAttrs/WithGroup scope applied,
+// caching the result in cache until key changes. key must be a comparable
+// value identifying source, typically the pointer that owns it.
+func (h *Handler) scoped(cache *atomic.Pointer[scopedHandler], key any, source slog.Handler) slog.Handler {
+	if len(h.scope) == 0 {
+		return source
+	}
+	if c := cache.Load(); c != nil && c.key == key {
+		return c.handler
+	}
+	c := &scopedHandler{key: key, handler: h.applyScope(source)}
+	cache.Store(c)
+	return c.handler
+}
@@ -0,0 +1,124 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_Firehose_ReceivesSuppressedRecords(t *testing.T) {
+	var buf, tap bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+	handler.SetFirehose(&tap, slog.LevelDebug)
+
+	logger := slog.New(handler)
+	logger.Debug("hidden detail", "job_id", "123")
+
+	// Main stream still suppresses the debug record
+	if buf.Len() > 0 {
+		t.Errorf("Expected main output to suppress debug, got: %s", buf.String())
+	}
+	// Firehose receives it
+	if !strings.Contains(tap.String(), "hidden detail") {
+		t.Errorf("Expected firehose to receive debug record, got: %s", tap.String())
+	}
+}
+
+func TestHandler_Firehose_RespectsLevel(t *testing.T) {
+	var tap bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelError)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFirehose(&tap, slog.LevelInfo)
+
+	logger := slog.New(handler)
+	logger.Debug("below firehose")
+	logger.Info("at firehose")
+
+	if strings.Contains(tap.String(), "below firehose") {
+		t.Error("Expected record below firehose level to be skipped")
+	}
+	if !strings.Contains(tap.String(), "at firehose") {
+		t.Error("Expected record at firehose level to be tapped")
+	}
+}
+
+func TestHandler_Firehose_UntransformedAndScoped(t *testing.T) {
+	var buf, tap bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", OutputLevel: "warn", Enabled: true},
+	})
+
+	// Firehose set after deriving the logger still applies to it
+	logger := slog.New(handler).With("job_id", "debug_1").WithGroup("req")
+	handler.SetFirehose(&tap, slog.LevelDebug)
+
+	logger.Debug("traced", "path", "/x")
+
+	if !strings.Contains(buf.String(), "level=WARN") {
+		t.Errorf("Expected main output to be transformed to WARN, got: %s", buf.String())
+	}
+	out := tap.String()
+	if !strings.Contains(out, `"level":"DEBUG"`) {
+		t.Errorf("Expected firehose to keep original level, got: %s", out)
+	}
+	if !strings.Contains(out, `"job_id":"debug_1"`) || !strings.Contains(out, `"req":{"path":"/x"}`) {
+		t.Errorf("Expected firehose to include attrs and groups, got: %s", out)
+	}
+}
+
+func TestHandler_Firehose_Removable(t *testing.T) {
+	var tap bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	logger := slog.New(handler)
+
+	handler.SetFirehose(&tap, slog.LevelDebug)
+	handler.ClearFirehose()
+	logger.Debug("after clear")
+
+	handler.SetFirehose(&tap, slog.LevelDebug)
+	handler.SetFirehose(nil, slog.LevelDebug)
+	logger.Debug("after nil")
+
+	if tap.Len() > 0 {
+		t.Errorf("Expected no firehose output after removal, got: %s", tap.String())
+	}
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug to be disabled once firehose is removed")
+	}
+}
+
+func TestHandler_Firehose_RingBuffer(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	ring := NewRingBufferWriter(2)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFirehose(ring, slog.LevelDebug)
+
+	logger := slog.New(handler)
+	logger.Debug("one")
+	logger.Debug("two")
+	logger.Debug("three")
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 retained entries, got %d", len(entries))
+	}
+	if !strings.Contains(string(entries[0]), "two") || !strings.Contains(string(entries[1]), "three") {
+		t.Errorf("Expected last two records, got: %q", entries)
+	}
+}
@@ -0,0 +1,33 @@
+package logfilter
+
+import "log/slog"
+
+// WithLevelFloor sets a level below which the handler never emits records,
+// whatever filters or the global level say: a filter or global level below
+// the floor is treated as the floor, so a debug filter is neutered when the
+// floor is info. This lets an application guarantee, e.g. in production,
+// that operators can't enable debug logging at runtime.
+//
+// Records below the floor are also excluded from baseline sampling,
+// capture-on-error and the suppressed-record buffer, since those would emit
+// them later. The firehose, which the application configures itself, still
+// sees them.
+func WithLevelFloor(level slog.Level) Option {
+	return func(o *options) {
+		o.levelFloor = level
+		o.hasLevelFloor = true
+	}
+}
+
+// belowFloor reports whether level is below the WithLevelFloor floor.
+func (h *Handler) belowFloor(level slog.Level) bool {
+	return h.hasLevelFloor && level < h.levelFloor
+}
+
+// floored returns level raised to the WithLevelFloor floor, if it is below.
+func (h *Handler) floored(level slog.Level) slog.Level {
+	if h.belowFloor(level) {
+		return h.levelFloor
+	}
+	return level
+}
@@ -0,0 +1,102 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_LevelFloor(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}), level,
+		WithLevelFloor(slog.LevelInfo))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", OutputLevel: "warn", Enabled: true},
+		{Type: "job_id", Pattern: "job_2", Level: "error", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug disabled below the floor despite a debug filter")
+	}
+	if got := slog.Level(handler.lowestLevel.Load()); got != slog.LevelInfo {
+		t.Errorf("Expected lowest level clamped to the floor, got %v", got)
+	}
+
+	logger.Debug("neutered", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected debug filter neutered by the floor, got: %s", buf.String())
+	}
+
+	// Bypass Enabled, as a custom wrapper might
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "bypass", 0)
+	r.AddAttrs(slog.String("job_id", "job_1"))
+	if err := handler.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("Expected Handle to drop records below the floor, got: %s", buf.String())
+	}
+	if d := handler.Explain(context.Background(), r); d.Emit || d.Level != slog.LevelInfo {
+		t.Errorf("Expected Explain to report suppression at the floor, got %v", d)
+	}
+
+	// Filters at or above the floor still work
+	logger.Info("kept", "job_id", "job_1")
+	if !strings.Contains(buf.String(), "level=WARN msg=kept") {
+		t.Errorf("Expected matching info record emitted, got: %s", buf.String())
+	}
+	buf.Reset()
+	logger.Warn("raised", "job_id", "job_2")
+	if buf.Len() > 0 {
+		t.Errorf("Expected filter above the floor to suppress, got: %s", buf.String())
+	}
+
+	// The floor applies to the global level too
+	level.Set(slog.LevelDebug)
+	logger.Debug("global debug")
+	if buf.Len() > 0 {
+		t.Errorf("Expected global debug neutered by the floor, got: %s", buf.String())
+	}
+	handler.SetFilteringEnabled(false)
+	logger.Debug("unfiltered debug")
+	if buf.Len() > 0 {
+		t.Errorf("Expected the floor to hold with filtering disabled, got: %s", buf.String())
+	}
+}
+
+func TestHandler_LevelFloor_Buffers(t *testing.T) {
+	var buf, firehose bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithLevelFloor(slog.LevelInfo),
+		WithBaselineSampling(slog.LevelDebug, 1),
+		WithSuppressedBuffer(10))
+	handler.SetFirehose(&firehose, slog.LevelDebug)
+	logger := slog.New(handler)
+
+	logger.Debug("below floor")
+	logger.Info("sampled")
+	if out := buf.String(); strings.Contains(out, "below floor") || !strings.Contains(out, "sampled") {
+		t.Errorf("Expected sampling only above the floor, got: %s", out)
+	}
+	if !strings.Contains(firehose.String(), "below floor") {
+		t.Errorf("Expected the firehose to still see records below the floor, got: %s", firehose.String())
+	}
+
+	buf.Reset()
+	if err := handler.FlushSuppressed(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "below floor") {
+		t.Errorf("Expected records below the floor not to be buffered, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,82 @@
+package logfilter
+
+import (
+	"sort"
+)
+
+// filterGroup is a named list of filters evaluated independently of the others.
+type filterGroup struct {
+	name    string
+	filters []LogFilter
+	index   *filterIndex // Exact-match index for filters, nil if not worthwhile
+}
+
+// SetFilterGroups replaces the handler's filter groups. Each group is
+// evaluated like its own filter list, yielding at most its first match, and
+// the regular filters (SetFilters) act as one more group. When several groups
+// match a record the lowest level wins, so a platform group suppressing
+// health checks and an app group elevating a job compose without either list
+// having to know about the other. Base filters are still evaluated first and
+// win outright; global suppressions apply before all of them.
+//
+// Groups are evaluated in name order, after the regular filters, which
+// decides ties between equal levels. Unconfirmed catch-all filters are
+// rejected as in SetFilters. Passing nil removes all groups; ClearFilters
+// leaves groups untouched.
+func (h *Handler) SetFilterGroups(groups map[string][]LogFilter) {
+	var prepared []filterGroup
+	var rejected, accepted []LogFilter
+	for name, filters := range groups {
+		a, r := h.screenFilters(filters)
+		rejected = append(rejected, r...)
+		accepted = append(accepted, a...)
+		prepared = append(prepared, filterGroup{name: name, filters: a})
+	}
+	sort.Slice(prepared, func(i, j int) bool { return prepared[i].name < prepared[j].name })
+
+	h.filtersLock.Lock()
+	h.groups = prepared
+	h.updateLowestLevel()
+	h.filtersLock.Unlock()
+
+	warnRejected(rejected)
+	warnInvalid(accepted)
+	warnUnregisteredContextKeys(accepted)
+}
+
+// GetFilterGroups returns a copy of the handler's filter groups.
+func (h *Handler) GetFilterGroups() map[string][]LogFilter {
+	h.filtersLock.RLock()
+	defer h.filtersLock.RUnlock()
+
+	groups := make(map[string][]LogFilter, len(h.groups))
+	for _, g := range h.groups {
+		filters := make([]LogFilter, len(g.filters))
+		copy(filters, g.filters)
+		groups[g.name] = filters
+	}
+	return groups
+}
+
+// SetFilterGroups replaces the global handler's filter groups.
+func SetFilterGroups(groups map[string][]LogFilter) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.SetFilterGroups(groups)
+	}
+}
+
+// GetFilterGroups returns a copy of the global handler's filter groups.
+func GetFilterGroups() map[string][]LogFilter {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.GetFilterGroups()
+	}
+	return nil
+}
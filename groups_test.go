@@ -0,0 +1,110 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_FilterGroups(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilterGroups(map[string][]LogFilter{
+		// Platform policy: health checks only at error
+		"platform": {
+			{Type: "path", Pattern: "/healthz", Level: "error", Enabled: true},
+		},
+		// App: debug for one job
+		"app": {
+			{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+		},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		log   func()
+		emits bool
+	}{
+		{"platform suppresses health check", func() { logger.Info("probe", "path", "/healthz") }, false},
+		{"app elevates job", func() { logger.Debug("step", "job_id", "job_1") }, true},
+		{"lowest level wins across groups", func() { logger.Debug("probe", "path", "/healthz", "job_id", "job_1") }, true},
+		{"no group matches", func() { logger.Debug("step", "job_id", "job_2") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_FilterGroups_FirstMatchWithinGroup(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilterGroups(map[string][]LogFilter{
+		"app": {
+			// First match within the group wins even though a later filter is lower
+			{Type: "job_id", Pattern: "job_*", Level: "warn", Enabled: true},
+			{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+		},
+	})
+	// Regular filters act as another group
+	handler.SetFilters([]LogFilter{
+		{Type: "user", Pattern: "alice", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Info("group first match", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected the group's first match (warn) to suppress info, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Debug("regular filter", "job_id", "job_1", "user", "alice")
+	if !strings.Contains(buf.String(), "regular filter") {
+		t.Errorf("Expected regular filters to combine with groups, got: %s", buf.String())
+	}
+}
+
+func TestHandler_FilterGroups_Lifecycle(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	handler.SetFilterGroups(map[string][]LogFilter{
+		"app": {{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true}},
+	})
+	if slog.Level(handler.lowestLevel.Load()) != slog.LevelDebug {
+		t.Errorf("Expected group filters to lower the lowest level, got %v", slog.Level(handler.lowestLevel.Load()))
+	}
+
+	groups := handler.GetFilterGroups()
+	if len(groups) != 1 || len(groups["app"]) != 1 {
+		t.Errorf("Expected one group with one filter, got %v", groups)
+	}
+
+	handler.ClearFilters()
+	if len(handler.GetFilterGroups()) != 1 {
+		t.Error("Expected ClearFilters to leave groups untouched")
+	}
+
+	handler.SetFilterGroups(nil)
+	if len(handler.GetFilterGroups()) != 0 {
+		t.Error("Expected SetFilterGroups(nil) to remove groups")
+	}
+	if slog.Level(handler.lowestLevel.Load()) <= slog.LevelError {
+		t.Errorf("Expected no active filters, got lowest level %v", slog.Level(handler.lowestLevel.Load()))
+	}
+}
@@ -5,52 +5,242 @@ import (
 	"log/slog"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Handler is an slog.Handler that supports dynamic log levels and filter-based
 // level overrides. It wraps an inner handler and checks filters before delegating.
 type Handler struct {
-	inner             slog.Handler
-	globalLevel       *slog.LevelVar
-	filters           []LogFilter
-	filtersLock       sync.RWMutex
-	lowestLevel       atomic.Int64 // Cached lowest level from active filters (stored as int64)
-	hasSourceFilters  bool         // Cached: true if any filter is source-based
-	preformattedAttrs []slog.Attr  // Attributes added via WithAttrs
-	workDir           string       // Working directory for relative path calculation
+	*handlerState
+
+	preformattedAttrs []slog.Attr // Attributes added via WithAttrs
+	groupPrefix       string      // Dotted path of WithGroup names, e.g. "http.", for qualified keys
+	groupedAttrs      []slog.Attr // WithAttrs attributes added under a group, keyed by qualified key
+	scope             []scopeOp   // WithAttrs/WithGroup calls, in order, for replay onto side handlers
+	component         string      // Cached component attribute value from WithAttrs
+	hasComponent      bool        // True if a component attribute was added via WithAttrs
+
+	innerCache    atomic.Pointer[scopedHandler] // Inner handler with scope applied
+	firehoseCache atomic.Pointer[scopedHandler] // Firehose handler with scope applied
+	routeCache    sync.Map                      // Route name -> *scopedHandler keyed by its *route
+}
+
+// handlerState is the state shared between a Handler and every handler derived
+// from it via WithAttrs or WithGroup, so that runtime changes (filters, firehose)
+// apply to all loggers built from the same root.
+type handlerState struct {
+	innerRef  atomic.Pointer[innerHandler] // Root inner handler, swappable via SetInnerHandler
+	closed    atomic.Bool                  // Set by Close
+	lifecycle sync.RWMutex                 // Held for reading by Handle, for writing by Close
+
+	filteringDisabled atomic.Bool // Set via SetFilteringEnabled(false)
+	noFilters         atomic.Bool // No filters, groups or suppressions, see updateAttrKeys
+
+	globalLevel      slog.Leveler
+	filters          []LogFilter       // Immutable once set: replaced, never modified in place
+	evalFilters      []LogFilter       // filters in evaluation (Priority) order; may be filters itself
+	filtersLock      sync.RWMutex      // Guards filters, groups and the state derived from them
+	lowestLevel      atomic.Int64      // Cached lowest level from active filters (stored as int64)
+	hasSourceFilters bool              // Cached: true if any filter is source-based
+	workDir          string            // Working directory for relative path calculation
+	sourcePathMode   string            // Set via WithSourcePathMode; empty for relative
+	sourceCache      sourceCache       // Memoized extractSource results by PC
+	rejectMatchAll   bool              // Reject unconfirmed catch-all filters
+	componentKey     string            // Attribute key naming the logger's component
+	sampling         *baselineSampling // Set via WithBaselineSampling; nil if disabled
+
+	// OutputLevel for elevated records of filters without one, set via
+	// WithDefaultOutputLevel.
+	defaultOutputLevel    slog.Level
+	hasDefaultOutputLevel bool
+
+	// Level below which nothing is emitted, set via WithLevelFloor.
+	levelFloor    slog.Level
+	hasLevelFloor bool
+
+	annotateKey string // Set via WithAnnotateMatches; empty if disabled
+
+	metrics MetricsCollector // Set via WithMetrics; NopMetricsCollector if unset
+
+	errorHandler func(error) // Set via WithErrorHandler; nil if unset
+
+	sticky *stickyCache // Set via WithStickyMatches; nil if disabled
+
+	maxMatchBytes int // Set via WithMaxMatchBytes; 0 for no limit
+
+	allowList bool // Set via WithAllowListMode
+
+	retryAttempts int           // Set via WithRetry; <= 1 disables retries
+	retryBackoff  time.Duration // Set via WithRetry
+
+	// Cached lowest level among active filters that need the record, rather
+	// than just its context, to match; see Enabled.
+	lowestRecordLevel atomic.Int64
+
+	firehose  atomic.Pointer[firehose]  // Optional tap receiving every record at or above its level
+	matchHook atomic.Pointer[MatchHook] // Set via SetMatchHook; nil if unset
+	clock     atomic.Pointer[clockRef]  // Set via WithClock or SetClock; nil uses the package-level clock
+	capture   *captureBuffer            // Optional capture-on-error buffering (nil if disabled)
+
+	suppressedBuf *suppressedBuffer // Optional recently-suppressed buffer (nil if disabled)
+
+	baseFilters  []LogFilter   // Filters set via WithBaseFilters; immutable after construction
+	baseIndex    *filterIndex  // Exact-match index for baseFilters, nil if not worthwhile
+	filtersIndex *filterIndex  // Exact-match index for filters; guarded by filtersLock
+	attrKeys     attrKeys      // Attribute keys read by all filters and suppressions; guarded by filtersLock
+	suppressions []Suppression // Set via SetGlobalSuppressions; guarded by filtersLock
+	groups       []filterGroup // Set via SetFilterGroups, sorted by name; guarded by filtersLock
+
+	routes   map[string]*route // Set via WithRouteOnMatch/WithRouteHandler; immutable
+	redactor *redactor         // Set via WithRedactKeys; nil if disabled
+
+	levelCounters levelCounters // Emitted/suppressed counts per level
+}
+
+// scopeOp records a single WithAttrs or WithGroup call.
+type scopeOp struct {
+	group string      // Set for WithGroup
+	attrs []slog.Attr // Set for WithAttrs
+}
+
+// scopedHandler caches a side handler with a Handler's scope applied.
+type scopedHandler struct {
+	key     any // Identifies the unscoped handler this was derived from
+	handler slog.Handler
 }
 
 // NewHandler creates a new filter-aware handler wrapping the given inner handler.
 // The globalLevel is used as the default log level when no filters match.
-func NewHandler(inner slog.Handler, globalLevel *slog.LevelVar) *Handler {
+//
+// Options that configure output (WithLevel, WithFormat, WithOutput, WithSource,
+// WithHandler) are ignored here since the inner handler is supplied by the
+// caller; options that configure filtering behavior, such as WithFilters, are
+// applied.
+func NewHandler(inner slog.Handler, globalLevel *slog.LevelVar, opts ...Option) *Handler {
+	return NewHandlerWithLeveler(inner, globalLevel, opts...)
+}
+
+// NewHandlerWithLeveler is NewHandler for a global level supplied by any
+// slog.Leveler, such as a level shared with other handlers or a constant
+// slog.Level. The handler reads it for every record. SetLevel changes it only
+// if it has a Set(slog.Level) method, as *slog.LevelVar does; otherwise the
+// level is managed by its owner and SetLevel does nothing.
+func NewHandlerWithLeveler(inner slog.Handler, globalLevel slog.Leveler, opts ...Option) *Handler {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	wd := ""
 	if cwd, err := filepath.Abs("."); err == nil {
 		wd = cwd
 	}
 	h := &Handler{
-		inner:       inner,
-		globalLevel: globalLevel,
-		workDir:     wd,
+		handlerState: &handlerState{
+			globalLevel: globalLevel,
+			workDir:     wd,
+		},
 	}
+	h.innerRef.Store(&innerHandler{handler: inner})
 	h.lowestLevel.Store(int64(slog.LevelError + 1)) // Higher than any valid level
+	h.lowestRecordLevel.Store(int64(slog.LevelError + 1))
+	h.noFilters.Store(true)
+
+	h.rejectMatchAll = o.rejectMatchAll
+	h.defaultOutputLevel, h.hasDefaultOutputLevel = o.defaultOutputLevel, o.hasDefaultOutputLevel
+	h.levelFloor, h.hasLevelFloor = o.levelFloor, o.hasLevelFloor
+	h.annotateKey = o.annotateKey
+	h.errorHandler = o.errorHandler
+	h.allowList = o.allowList
+	h.sourcePathMode = o.sourcePathMode
+	h.retryAttempts, h.retryBackoff = o.retryAttempts, o.retryBackoff
+	h.maxMatchBytes = defaultMaxMatchBytes
+	if o.hasMaxMatchBytes {
+		h.maxMatchBytes = max(o.maxMatchBytes, 0)
+	}
+	h.metrics = o.metrics
+	if h.metrics == nil {
+		h.metrics = NopMetricsCollector{}
+	}
+	if o.clock != nil {
+		h.clock.Store(&clockRef{clock: o.clock})
+	}
+	if o.samplingRate > 0 {
+		h.sampling = &baselineSampling{level: o.samplingLevel, rate: o.samplingRate}
+	}
+	h.redactor = newRedactor(o.redactKeys)
+	if len(o.routes) > 0 {
+		h.routes = make(map[string]*route, len(o.routes))
+		for name, handler := range o.routes {
+			h.routes[name] = &route{handler: handler}
+		}
+	}
+	h.componentKey = DefaultComponentKey
+	if o.componentKey != "" {
+		h.componentKey = o.componentKey
+	}
+
+	if len(o.baseFilters) > 0 {
+		h.baseFilters = make([]LogFilter, len(o.baseFilters))
+		copy(h.baseFilters, o.baseFilters)
+		h.baseFilters = sortByPriority(h.baseFilters)
+		for i := range h.baseFilters {
+			h.baseFilters[i].state = nil // Don't share state with a filter read back from another handler
+			h.baseFilters[i].prepare()
+		}
+		assignDerivedIDs(h.baseFilters)
+		h.baseIndex = buildFilterIndex(h.baseFilters)
+		warnInvalid(h.baseFilters)
+		warnUnregisteredContextKeys(h.baseFilters)
+		h.updateLowestLevel()
+	}
+
+	if o.captureKey != "" && o.captureWindow > 0 {
+		h.capture = newCaptureBuffer(o.captureKey, o.captureWindow)
+	}
+	if o.suppressedSize > 0 {
+		h.suppressedBuf = newSuppressedBuffer(o.suppressedSize, o.flushSuppressedOnError)
+	}
+	if o.stickyKey != "" && o.stickyTTL > 0 {
+		h.sticky = newStickyCache(o.stickyKey, o.stickyTTL, o.stickyMaxIDs)
+	}
+
+	// Apply initial filters if provided
+	if len(o.filters) > 0 {
+		h.SetFilters(o.filters)
+	}
 	return h
 }
 
 // SetFilters replaces all filters with the given list.
-// Filters are applied in order; first match wins.
+// Filters are applied in order of descending Priority, then list order; first
+// match wins.
+//
+// If the handler was created with WithRejectMatchAll(true), unconfirmed
+// catch-all filters are dropped and a warning is logged for each.
 func (h *Handler) SetFilters(filters []LogFilter) {
-	h.filtersLock.Lock()
-	defer h.filtersLock.Unlock()
+	accepted, rejected := h.screenFilters(filters)
 
-	h.filters = make([]LogFilter, len(filters))
-	copy(h.filters, filters)
+	h.filtersLock.Lock()
+	h.filters = accepted
 	h.updateLowestLevel()
+	h.filtersLock.Unlock()
+
+	warnRejected(rejected)
+	warnInvalid(accepted)
+	warnUnregisteredContextKeys(accepted)
 }
 
-// GetFilters returns a copy of the current filters.
+// GetFilters returns a copy of the current filters in the order they were
+// added: SetFilters' order, with AddFilter appending at the end. Removing a
+// filter keeps the others in order, and UpsertFilter and ReplaceFilter
+// replace a filter in its place. The order is independent of Priority:
+// filters are evaluated by descending Priority, in this order among equal
+// priorities.
 func (h *Handler) GetFilters() []LogFilter {
 	h.filtersLock.RLock()
 	defer h.filtersLock.RUnlock()
@@ -60,13 +250,83 @@ func (h *Handler) GetFilters() []LogFilter {
 	return filters
 }
 
+// GetActiveFilters returns a copy of the filters that are currently active:
+// enabled, not expired by the handler's clock and not exhausted by MaxHits.
+// They are in the same order as GetFilters.
+func (h *Handler) GetActiveFilters() []LogFilter {
+	now := h.now()
+
+	h.filtersLock.RLock()
+	defer h.filtersLock.RUnlock()
+
+	var filters []LogFilter
+	for i := range h.filters {
+		if h.filters[i].IsActiveAt(now) {
+			filters = append(filters, h.filters[i])
+		}
+	}
+	return filters
+}
+
 // AddFilter adds a filter to the end of the filter list.
+// Unconfirmed catch-all filters are rejected as in SetFilters.
 func (h *Handler) AddFilter(filter LogFilter) {
-	h.filtersLock.Lock()
-	defer h.filtersLock.Unlock()
+	accepted, rejected := h.screenFilters([]LogFilter{filter})
+	if len(accepted) == 0 {
+		warnRejected(rejected)
+		return
+	}
 
+	h.filtersLock.Lock()
 	h.filters = append(h.filters, filter)
 	h.updateLowestLevel()
+	h.filtersLock.Unlock()
+
+	warnInvalid(accepted)
+	warnUnregisteredContextKeys(accepted)
+}
+
+// screenFilters copies filters, separating out those rejected by the
+// match-all guard.
+func (h *Handler) screenFilters(filters []LogFilter) (accepted, rejected []LogFilter) {
+	accepted = make([]LogFilter, 0, len(filters))
+	for _, f := range filters {
+		if h.rejectMatchAll && f.IsMatchAll() && !f.Confirmed {
+			rejected = append(rejected, f)
+			continue
+		}
+		accepted = append(accepted, f)
+	}
+	return accepted, rejected
+}
+
+// warnRejected logs a warning for each filter rejected by the match-all guard.
+// It must not be called with filtersLock held, since the default logger may
+// route back through a Handler.
+func warnRejected(rejected []LogFilter) {
+	for _, f := range rejected {
+		slog.Default().Warn("logfilter: rejected unconfirmed match-all filter",
+			"type", f.Type, "pattern", f.Pattern, "level", f.Level)
+	}
+}
+
+// warnInvalid logs a warning for each filter whose pattern doesn't compile.
+// Such filters stay in place but never match. Like warnRejected, it must not
+// be called with filtersLock held.
+func warnInvalid(filters []LogFilter) {
+	for _, f := range filters {
+		if !f.strictPattern() {
+			continue
+		}
+		if _, err := f.compile(); err != nil {
+			mode := "regex"
+			if f.Numeric {
+				mode = "numeric"
+			}
+			slog.Default().Warn("logfilter: invalid "+mode+" pattern, filter will never match",
+				"type", f.Type, "pattern", f.Pattern, "error", err)
+		}
+	}
 }
 
 // RemoveFilter removes filters matching the given type and pattern.
@@ -90,141 +350,650 @@ func (h *Handler) ClearFilters() {
 	defer h.filtersLock.Unlock()
 
 	h.filters = nil
-	h.lowestLevel.Store(int64(slog.LevelError + 1))
-	h.hasSourceFilters = false
+	h.updateLowestLevel()
+}
+
+// GetBaseFilters returns a copy of the base filters set via WithBaseFilters.
+// Base filters are not included in GetFilters.
+func (h *Handler) GetBaseFilters() []LogFilter {
+	filters := make([]LogFilter, len(h.baseFilters))
+	copy(filters, h.baseFilters)
+	return filters
 }
 
-// updateLowestLevel recalculates the lowest level among active filters
+// updateLowestLevel orders the filters for evaluation (h.evalFilters) and
+// the filter groups by priority, recalculates the lowest level among active filters (including base filters
+// and filter groups), the same among filters that need the record to match,
 // and checks if any source filters are present.
 // Must be called with filtersLock held.
+//
+// Published filter slices are immutable: evaluations take h.evalFilters and
+// h.groups under RLock but read them after releasing it, so the filters are
+// prepared in fresh copies (copy-on-write) rather than in place. Runtime
+// state, such as hit counts, lives behind each filter's state pointer, which
+// the copies share. A state pointer is kept only by the filter it was issued
+// to, identified by set and FilterID: a copy passed back with a different ID,
+// or a second copy of the same filter, starts afresh.
 func (h *Handler) updateLowestLevel() {
 	lowest := slog.LevelError + 1
+	lowestRecord := slog.LevelError + 1
 	h.hasSourceFilters = false
+	now := h.now()
 
-	for i := range h.filters {
-		h.filters[i].prepare()
-		f := &h.filters[i]
-		if !f.IsActive() {
-			continue
+	h.filters = slices.Clone(h.filters)
+	if h.groups != nil {
+		groups := make([]filterGroup, len(h.groups))
+		for i, g := range h.groups {
+			g.filters = slices.Clone(sortByPriority(g.filters))
+			groups[i] = g
+		}
+		h.groups = groups
+	}
+
+	lists := make([][]LogFilter, 0, 2+len(h.groups))
+	sets := make([]string, 0, 1+len(h.groups))
+	lists = append(lists, h.baseFilters, h.filters)
+	sets = append(sets, "filters")
+	for _, g := range h.groups {
+		lists = append(lists, g.filters)
+		sets = append(sets, "group:"+g.name)
+	}
+
+	claimed := make(map[*filterState]bool)
+	for n, list := range lists[1:] {
+		for i := range list {
+			list[i].prepare()
 		}
-		if f.parsedLevel < lowest {
-			lowest = f.parsedLevel
+		assignDerivedIDs(list)
+		for i := range list {
+			f := &list[i]
+			owner := stateKey(sets[n], f)
+			if claimed[f.state] || (f.state.owner != "" && f.state.owner != owner) {
+				f.state = nil
+				f.prepareState()
+			}
+			f.state.owner = owner
+			claimed[f.state] = true
 		}
-		if f.kind == filterKindSourceFile || f.kind == filterKindSourceFunction {
-			h.hasSourceFilters = true
+	}
+	h.evalFilters = sortByPriority(h.filters)
+	h.filtersIndex = buildFilterIndex(h.evalFilters)
+	for i := range h.groups {
+		h.groups[i].index = buildFilterIndex(h.groups[i].filters)
+	}
+	h.updateAttrKeys()
+
+	for _, list := range lists {
+		for i := range list {
+			f := &list[i]
+			if !f.IsActiveAt(now) {
+				continue
+			}
+			if f.parsedLevel < lowest {
+				lowest = f.parsedLevel
+			}
+			if f.parsedLevel < lowestRecord && !f.contextOnly() {
+				lowestRecord = f.parsedLevel
+			}
+			if f.needsSource() {
+				h.hasSourceFilters = true
+			}
 		}
 	}
-	h.lowestLevel.Store(int64(lowest))
+	h.lowestLevel.Store(int64(h.floored(lowest)))
+	h.lowestRecordLevel.Store(int64(h.floored(lowestRecord)))
+}
+
+// outputLevel returns the level a record at level decided by f is emitted
+// at: f's OutputLevel if set, else the WithDefaultOutputLevel level if the
+// record is below globalLevel, else level itself.
+func (h *Handler) outputLevel(f *LogFilter, level, globalLevel slog.Level) slog.Level {
+	if f.OutputLevel == "" && h.hasDefaultOutputLevel && level < globalLevel {
+		return h.defaultOutputLevel
+	}
+	return f.cachedOutputLevel(level)
+}
+
+// stateKey identifies the filter owning a filterState: the name of its set,
+// as in Stats, and its FilterID.
+func stateKey(set string, f *LogFilter) string {
+	return set + "\x00" + f.FilterID()
 }
 
 // Enabled reports whether the handler handles records at the given level.
 // It returns true if either:
 // - The level is >= the global level, OR
 // - There are active filters that might match at this level
+//
+// Context filters ("context:key") are evaluated here against ctx, so a level
+// that only they lower is enabled just for contexts they match. Attribute,
+// source and other filters need the record itself, so any of them at or below
+// level enables it regardless; Handle then decides.
 func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
-	// Fast path: level is at or above global level
-	if level >= h.globalLevel.Level() {
+	if h.closed.Load() {
+		return false
+	}
+	if h.filteringDisabled.Load() {
+		return level >= h.floored(h.globalLevel.Level())
+	}
+
+	// Nothing below the floor is emitted or held; only the firehose sees it.
+	if h.belowFloor(level) {
+		fh := h.firehose.Load()
+		return fh != nil && level >= fh.level
+	}
+
+	// Fast path: level is at or above global level or the lowest level of a
+	// filter needing the record
+	if level >= h.globalLevel.Level() || level >= slog.Level(h.lowestRecordLevel.Load()) {
 		return true
 	}
 
-	// Check if any filter could potentially enable this level.
-	// lowestLevel is updated atomically, no lock needed on the hot path.
-	lowestLevel := slog.Level(h.lowestLevel.Load())
+	// A ContextWithLevel override may admit the record whatever the filters.
+	if ctxLevel, ok := LevelFromContext(ctx); ok && level >= ctxLevel {
+		return true
+	}
+
+	// Only context filters reach this low; check them against ctx.
+	if level >= slog.Level(h.lowestLevel.Load()) && h.contextMayEmit(ctx, level) {
+		return true
+	}
 
-	return level >= lowestLevel
+	// The firehose sees records regardless of the main stream's level.
+	if fh := h.firehose.Load(); fh != nil && level >= fh.level {
+		return true
+	}
+
+	// Baseline sampling may emit records no filter would.
+	if h.sampling != nil && level >= h.sampling.level {
+		return true
+	}
+
+	// A filter stuck to the context's ID may admit the record.
+	if h.stickyMayEmit(ctx, level) {
+		return true
+	}
+
+	// Capture-on-error and the suppressed buffer must see every record so
+	// they can buffer suppressed ones.
+	return h.capture != nil || h.suppressedBuf != nil
 }
 
-// Handle processes a log record, applying filters to determine the effective level.
-// If a matching filter has OutputLevel set, the record's level is transformed before emission.
-func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
-	effectiveLevel := h.globalLevel.Level()
-	var matchedFilter *LogFilter
+// contextMayEmit reports whether an active context filter at or below level
+// matches ctx, so that a record at level could be emitted. It ignores filter
+// priority, which only matters once the record is known.
+func (h *Handler) contextMayEmit(ctx context.Context, level slog.Level) bool {
+	if ctx == nil {
+		return true // Can't evaluate; let Handle decide
+	}
 
-	// Check filters (first match wins)
 	h.filtersLock.RLock()
-	filters := h.filters
-	hasSourceFilters := h.hasSourceFilters
+	filters := h.evalFilters
+	groups := h.groups
 	h.filtersLock.RUnlock()
 
-	// Extract source info only if we have source filters (performance optimization)
-	var sourceFile, sourceFunction string
-	if hasSourceFilters && r.PC != 0 {
-		sourceFile, sourceFunction = h.extractSource(r.PC)
+	r := slog.Record{Level: level}
+	v := recordView{h: h, ctx: ctx, r: &r}
+	if v.anyContextMatch(h.baseFilters, level) || v.anyContextMatch(filters, level) {
+		return true
 	}
+	for _, g := range groups {
+		if v.anyContextMatch(g.filters, level) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Attribute map is built lazily — only when an attribute filter is encountered.
-	var attrs map[string]string
-
-	for i := range filters {
-		f := &filters[i]
-		if !f.IsActive() {
+// anyContextMatch reports whether an active context filter in list at or
+// below level matches the view's context.
+func (v *recordView) anyContextMatch(list []LogFilter, level slog.Level) bool {
+	for i := range list {
+		f := &list[i]
+		if f.parsedLevel > level || !f.contextOnly() || !v.active(f) {
 			continue
 		}
-
-		var value string
-		var found bool
-
-		switch f.kind {
-		case filterKindSourceFile:
-			// Match against source file path
-			value = sourceFile
-			found = sourceFile != ""
-		case filterKindSourceFunction:
-			// Match against function name
-			value = sourceFunction
-			found = sourceFunction != ""
-		case filterKindContext:
-			// Extract from context
-			value, found = extractFromContext(ctx, f.contextKey)
-		default:
-			// Build the attribute map on first need
-			if attrs == nil {
-				attrs = make(map[string]string, len(h.preformattedAttrs)+r.NumAttrs())
-				for _, a := range h.preformattedAttrs {
-					attrs[a.Key] = attrValueToString(a.Value)
-				}
-				r.Attrs(func(a slog.Attr) bool {
-					attrs[a.Key] = attrValueToString(a.Value)
-					return true
-				})
-			}
-			// Check record attributes
-			value, found = attrs[f.attributeKey]
+		if _, ok := v.match(f); ok {
+			return true
 		}
+	}
+	return false
+}
 
-		if found && f.Matches(value) {
-			effectiveLevel = f.parsedLevel
-			matchedFilter = f
-			break // First match wins
+// mayEmit reports whether a record at level could be emitted: it must be at
+// or above the global level, the lowest active filter level, or ctx's
+// ContextWithLevel override. Records failing this are suppressed whatever
+// their attributes.
+func (h *Handler) mayEmit(ctx context.Context, level slog.Level) bool {
+	if h.belowFloor(level) {
+		return false
+	}
+	if level >= h.globalLevel.Level() {
+		return true
+	}
+	if ctxLevel, ok := LevelFromContext(ctx); ok && level >= ctxLevel {
+		return true
+	}
+	// lowestLevel is updated atomically, no lock needed on the hot path.
+	return level >= slog.Level(h.lowestLevel.Load()) || h.stickyMayEmit(ctx, level)
+}
+
+// Handle processes a log record, applying filters to determine the effective level.
+// If a matching filter has OutputLevel set, the record's level is transformed before emission.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	h.lifecycle.RLock()
+	defer h.lifecycle.RUnlock()
+	if h.closed.Load() {
+		return ErrHandlerClosed
+	}
+	h.metrics.RecordSeen(r.Level)
+	if h.filteringDisabled.Load() {
+		return h.handleUnfiltered(ctx, r)
+	}
+
+	// Resolve the inner handler once so a concurrent swap can't split this record.
+	_, inner := h.resolveInner()
+
+	// Redact before anything, including the firehose, sees the record.
+	if h.redactor != nil {
+		r = h.redactor.record(r)
+	}
+
+	// Tap the record before any filtering so the firehose sees it unchanged.
+	h.tapFirehose(ctx, r)
+
+	// Records Enabled would only admit for the firehose or buffering (or that
+	// bypassed Enabled altogether) can't be emitted, so skip the filters.
+	var d decision
+	switch {
+	case h.noFilters.Load() && h.sticky == nil:
+		// Nothing to match, so skip attribute and source extraction.
+		d = h.unmatchedDecision(ctx, r.Level)
+	case h.mayEmit(ctx, r.Level):
+		d = h.decide(ctx, r, nil)
+		if h.sticky != nil {
+			d = h.applySticky(ctx, r, d, false)
 		}
+		h.callMatchHook(d.filter, r)
+	}
+	if !d.emit && !h.belowFloor(r.Level) && h.sample(r, d) {
+		d = decision{outputLevel: r.Level, emit: true}
+		r = r.Clone()
+		r.AddAttrs(slog.Bool(SampledKey, true))
 	}
+	h.levelCounters.record(r.Level, d.emit)
+	h.recordMetrics(r.Level, d.filter, d.emit)
 
 	// Check if record should be emitted
-	if r.Level < effectiveLevel {
+	if !d.emit {
+		if h.belowFloor(r.Level) {
+			return nil // Never emitted, so not worth holding
+		}
+		if h.capture != nil {
+			h.capture.hold(ctx, inner, r)
+		}
+		if h.suppressedBuf != nil {
+			h.suppressedBuf.hold(inner, r)
+		}
 		return nil // Suppress
 	}
 
-	// Transform log level if filter specifies an output level
-	if matchedFilter != nil && matchedFilter.HasOutputLevel() {
+	// An error releases any records captured for the same context ID first.
+	if h.capture != nil && r.Level >= slog.LevelError {
+		_ = h.reportError(h.capture.flush(ctx))
+	}
+	if h.suppressedBuf != nil && h.suppressedBuf.flushOnError && r.Level >= slog.LevelError {
+		_ = h.reportError(h.suppressedBuf.replay(ctx))
+	}
+
+	// Transform log level if the filter (or WithDefaultOutputLevel) specifies
+	// an output level
+	rebuilt := false
+	if d.filter != nil && d.outputLevel != r.Level {
 		// Create a new record with the transformed level
-		newRecord := slog.NewRecord(r.Time, matchedFilter.cachedOutputLevel(r.Level), r.Message, r.PC)
+		newRecord := slog.NewRecord(r.Time, d.outputLevel, r.Message, r.PC)
 		r.Attrs(func(a slog.Attr) bool {
 			newRecord.AddAttrs(a)
 			return true
 		})
-		return h.inner.Handle(ctx, newRecord)
+		r = newRecord
+		rebuilt = true
+	}
+
+	// Name the filter that let the record through
+	if d.filter != nil && h.annotateKey != "" {
+		if !rebuilt {
+			r = r.Clone()
+		}
+		h.annotate(&r, d.filter)
 	}
 
-	return h.inner.Handle(ctx, r)
+	return h.reportError(h.emit(ctx, inner, d.filter, r))
+}
+
+// decision is the outcome of evaluating a record against the filters.
+type decision struct {
+	filter      *LogFilter // First matching filter, or nil if none matched
+	level       slog.Level // Effective minimum level for the record
+	outputLevel slog.Level // Level the record is emitted at
+	value       string     // Value the filter matched against
+	emit        bool       // Whether the record passes the effective level
+
+	suppression *Suppression // Global suppression that dropped the record, if any
+}
+
+// recordSource is the source location of a record, used by source filters.
+type recordSource struct {
+	file     string // Formatted path, see formatSourcePath
+	function string
+	path     string // Full path as reported by the runtime, if known
+	pkg      string // Import path of the function's package
+}
+
+// decide evaluates a record and then applies the stateful per-filter limits
+// (ThrottlePerValue, then MaxPerSecond), which may turn an emit into a suppression.
+// Unlike evaluate, it counts the match and records the emit against those limits.
+func (h *Handler) decide(ctx context.Context, r slog.Record, src *recordSource) decision {
+	d := h.evaluate(ctx, r, src)
+	f := d.filter
+	if f != nil && f.MaxHits > 0 && f.state != nil && f.state.hits.Add(1) > int64(f.MaxHits) {
+		// Concurrent records used up the filter's last hits since evaluate
+		// saw it active; it is now inactive, so evaluate again without it.
+		return h.decide(ctx, r, src)
+	}
+	if f != nil && f.state != nil {
+		f.state.matches.Add(1)
+	}
+	if !d.emit || f == nil || f.state == nil {
+		return d
+	}
+
+	now := r.Time
+	if now.IsZero() {
+		now = h.now()
+	}
+	if f.ThrottlePerValue > 0 && f.state.throttle != nil {
+		if !f.state.throttle.allow(d.value, now, f.ThrottlePerValue) {
+			d.emit = false
+			return d
+		}
+	}
+	if f.MaxPerSecond > 0 && f.state.rate != nil {
+		if !f.state.rate.allow(now, f.MaxPerSecond) {
+			d.emit = false
+		}
+	}
+	return d
+}
+
+// evaluate runs the filters against a record and decides whether it is emitted
+// and at what level, without emitting it. If src is nil, the source location is
+// resolved from r.PC when source filters are present.
+func (h *Handler) evaluate(ctx context.Context, r slog.Record, src *recordSource) decision {
+	globalLevel := h.globalLevel.Level()
+	d := decision{level: h.floored(globalLevel), outputLevel: r.Level}
+
+	h.filtersLock.RLock()
+	filters := h.evalFilters
+	filtersIndex := h.filtersIndex
+	groups := h.groups
+	suppressions := h.suppressions
+	keys := h.attrKeys
+	hasSourceFilters := h.hasSourceFilters
+	h.filtersLock.RUnlock()
+
+	v := recordView{h: h, ctx: ctx, r: &r, keys: keys}
+
+	// Global suppressions apply before any filter.
+	suppression := matchSuppression(suppressions, r.Level, &v)
+	if suppression != nil && !suppression.AllowOverride {
+		d.suppression = suppression
+		return d
+	}
+
+	// Extract source info only if we have source filters (performance optimization).
+	// Records without a PC (e.g. built manually with slog.NewRecord(..., 0)) have
+	// no source, so source filters are skipped for them rather than suppressing.
+	if src == nil && hasSourceFilters && r.PC != 0 {
+		s := h.source(r.PC)
+		src = &s
+	}
+	if src != nil {
+		v.sourceFile, v.sourceFunction, v.sourcePath = src.file, src.function, src.path
+		v.sourcePackage = src.pkg
+	}
+
+	// Base filters are evaluated before everything else and win outright.
+	f, value := v.firstMatch(h.baseFilters, h.baseIndex)
+	if f == nil {
+		// The regular filters and each filter group yield their own first
+		// match; the lowest level among those wins, earlier lists on ties.
+		f, value = v.firstMatch(filters, filtersIndex)
+		for _, g := range groups {
+			if gf, gv := v.firstMatch(g.filters, g.index); gf != nil && (f == nil || gf.parsedLevel < f.parsedLevel) {
+				f, value = gf, gv
+			}
+		}
+	}
+	if f != nil {
+		d.level = h.floored(f.parsedLevel)
+		d.filter = f
+		d.outputLevel = h.outputLevel(f, r.Level, globalLevel)
+		d.value = value
+	}
+
+	// A ContextWithLevel override replaces the global or filter level.
+	if ctxLevel, ok := LevelFromContext(ctx); ok {
+		d.level = h.floored(ctxLevel)
+	}
+
+	// An overridable suppression still drops the record if no filter matched.
+	if suppression != nil && d.filter == nil {
+		d.suppression = suppression
+	}
+	// In allow-list mode, only a matching filter lets a record through.
+	d.emit = r.Level >= d.level && d.suppression == nil && (d.filter != nil || !h.allowList)
+	return d
+}
+
+// recordView exposes the parts of a record that filters match against,
+// computing the expensive ones (attribute values, clock) on first use.
+type recordView struct {
+	h              *Handler
+	ctx            context.Context
+	r              *slog.Record
+	sourceFile     string
+	sourceFunction string
+	sourcePackage  string
+	sourcePath     string    // Full path of sourceFile, if known
+	now            time.Time // Read by active, only for filters with an expiry
+
+	keys      attrKeys   // Attribute keys the filters read
+	collected bool       // Whether the values of keys have been resolved, see attr
+	slots     []attrSlot // Values of keys, if more than fit in slotBuf
+	slotBuf   [maxStackAttrSlots]attrSlot
+}
+
+// active reports whether f is enabled, unexpired, not exhausted and applies
+// to the record's level and time (see LogFilter.SourceLevels and TimeWindow).
+func (v *recordView) active(f *LogFilter) bool {
+	if !f.Enabled || f.IsExhausted() {
+		return false
+	}
+	if f.sourceLevels != nil && !slices.Contains(f.sourceLevels, v.r.Level) {
+		return false
+	}
+	if f.window != nil && !f.window.contains(v.recordTime()) {
+		return false
+	}
+	if f.expires() {
+		if v.now.IsZero() {
+			v.now = v.h.now()
+		}
+		return !f.IsExpiredAt(v.now)
+	}
+	return true
+}
+
+// recordTime returns the record's time, or the handler's current time for a
+// record without one (or in Enabled, before the record exists).
+func (v *recordView) recordTime() time.Time {
+	if !v.r.Time.IsZero() {
+		return v.r.Time
+	}
+	if v.now.IsZero() {
+		v.now = v.h.now()
+	}
+	return v.now
+}
+
+// firstMatch returns the first active filter in list matching the record,
+// and the value it matched, or nil. idx, if not nil, must index list.
+func (v *recordView) firstMatch(list []LogFilter, idx *filterIndex) (*LogFilter, string) {
+	if idx == nil {
+		for i := range list {
+			f := &list[i]
+			if !v.active(f) {
+				continue
+			}
+			if value, ok := v.match(f); ok {
+				return f, value
+			}
+		}
+		return nil, ""
+	}
+
+	// Scan the unindexed filters up to the first indexed match.
+	best, bestValue := v.firstIndexed(list, idx)
+	for _, p := range idx.rest {
+		if best >= 0 && p >= best {
+			break
+		}
+		f := &list[p]
+		if !v.active(f) {
+			continue
+		}
+		if value, ok := v.match(f); ok {
+			return f, value
+		}
+	}
+	if best >= 0 {
+		return &list[best], bestValue
+	}
+	return nil, ""
+}
+
+// match reports whether f matches the record, returning the matched value.
+func (v *recordView) match(f *LogFilter) (string, bool) {
+	if len(f.conditions) > 0 {
+		return v.matchConditions(f)
+	}
+	return v.matchOne(f)
+}
+
+// matchOne reports whether f's Type and Pattern match the record, returning
+// the matched value. It ignores f's conditions.
+func (v *recordView) matchOne(f *LogFilter) (string, bool) {
+	switch {
+	case f.MatchMode == MatchErrorType:
+		return v.matchErrorType(f)
+	case f.MatchMode != "":
+		return v.matchPresence(f)
+	}
+
+	var value string
+	var found bool
+
+	switch f.kind {
+	case filterKindSourceFile:
+		// Match against the formatted and the full source file path
+		return v.matchSourceFile(f)
+	case filterKindSourceFunction:
+		// Match against function name
+		value = v.sourceFunction
+		found = v.sourceFunction != ""
+	case filterKindSourcePackage:
+		// Match against the function's package path
+		value = v.sourcePackage
+		found = v.sourcePackage != ""
+	case filterKindContext:
+		// Extract from context
+		if f.ctxLabels != nil {
+			return v.matchContextLabels(f)
+		}
+		value, found = extractFromContext(v.ctx, f.contextKey)
+	case filterKindComponent:
+		// Match against the logger's component
+		value, found = v.h.componentValue(*v.r)
+	case filterKindAny:
+		// Attribute first; fall back to context if absent or not matching
+		if value, ok := v.matchAttr(f); ok {
+			return value, true
+		}
+		value, found = extractFromContext(v.ctx, f.contextKey)
+	case filterKindMessage:
+		// Match against the message text
+		value, found = v.r.Message, true
+	case filterKindHasError:
+		// Match error-level records and records with an error attribute
+		value, found = v.h.recordError(*v.r)
+	default:
+		return v.matchAttr(f)
+	}
+
+	return value, found && f.matchValue(f.matcher, value)
+}
+
+// matchAttr reports whether f matches the attribute named by its Type,
+// returning the matched value. Record attributes are checked first, then a
+// dotted path into a map or group value. Slice values match element-wise
+// (see matchElems). A glob Type, such as "*_id", matches any attribute whose
+// key it matches (see matchGlobAttr).
+func (v *recordView) matchAttr(f *LogFilter) (string, bool) {
+	if f.keyGlob != nil {
+		return v.matchGlobAttr(f)
+	}
+	if f.scoped() {
+		return v.matchScopedAttr(f)
+	}
+	if elems, ok := v.attrElems(f.attributeKey); ok {
+		whole, _ := v.attr(f.attributeKey)
+		return f.matchElems(f.matcher, elems, whole)
+	}
+	value, found := v.lookupAttr(f)
+	return value, found && f.matchValue(f.matcher, value)
+}
+
+// matchSourceFile reports whether a source:file filter matches the record's
+// source file, in either its formatted form (see formatSourcePath) or its
+// full path, so that a pattern can target a directory segment anywhere in the
+// path, e.g. "*/internal/*", as well as a relative path or bare filename.
+// With Negate, it matches if neither form does. It returns the form matched.
+func (v *recordView) matchSourceFile(f *LogFilter) (string, bool) {
+	if v.sourceFile == "" {
+		return "", false
+	}
+	if v.sourcePath == "" || v.sourcePath == v.sourceFile {
+		return v.sourceFile, f.matchValue(f.matcher, v.sourceFile)
+	}
+	return f.matchElems(f.matcher, []string{v.sourceFile, v.sourcePath}, v.sourceFile)
 }
 
 // extractSource extracts the source file and function name from a program counter.
 // For local files (within working directory), returns relative paths.
 // For external packages, returns the module path (e.g., "@github.com/pkg/module/file.go").
 func (h *Handler) extractSource(pc uintptr) (file, function string) {
+	src := h.resolveSource(pc)
+	return src.file, src.function
+}
+
+// resolveSource resolves pc's source location, as extractSource, along with
+// the full path of its file.
+func (h *Handler) resolveSource(pc uintptr) recordSource {
 	frames := runtime.CallersFrames([]uintptr{pc})
 	frame, _ := frames.Next()
 
+	var file, function string
 	if frame.File != "" {
 		file = h.formatSourcePath(frame.File, frame.Function)
 	}
@@ -244,13 +1013,39 @@ func (h *Handler) extractSource(pc uintptr) (file, function string) {
 		}
 	}
 
-	return file, function
+	return recordSource{file: file, function: function, path: frame.File, pkg: functionPackage(frame.Function)}
+}
+
+// functionPackage returns the import path of the package of a fully qualified
+// function name, e.g. "github.com/me/app/internal/auth" for
+// "github.com/me/app/internal/auth.(*Service).Login". The runtime escapes dots
+// in the last path element (as in "gopkg.in/yaml%2ev3"); they are unescaped.
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[lastSlash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return strings.ReplaceAll(function[:lastSlash+1+dot], "%2e", ".")
 }
 
-// formatSourcePath formats the source file path for display.
-// Local files (within working directory) get relative paths.
-// External packages get module paths prefixed with "@".
+// formatSourcePath formats the source file path for display, as the
+// handler's WithSourcePathMode requires. By default, local files (within
+// working directory) get relative paths and external packages get module
+// paths prefixed with "@".
 func (h *Handler) formatSourcePath(filePath, functionName string) string {
+	switch h.sourcePathMode {
+	case SourcePathAbsolute:
+		return filePath
+	case SourcePathBase:
+		return filepath.Base(filePath)
+	case SourcePathModule:
+		if p, ok := moduleSourcePath(filePath, functionName); ok {
+			return p
+		}
+		return filepath.Base(filePath)
+	}
+
 	// Try to make the path relative to working directory
 	if h.workDir != "" {
 		if rel, err := filepath.Rel(h.workDir, filePath); err == nil {
@@ -262,20 +1057,8 @@ func (h *Handler) formatSourcePath(filePath, functionName string) string {
 	}
 
 	// External package - extract module path from function name
-	// Function name looks like: "github.com/user/repo/pkg.(*Type).Method"
-	if functionName != "" {
-		// Find the last slash to get the package path
-		if lastSlash := strings.LastIndex(functionName, "/"); lastSlash >= 0 {
-			// Get everything up to and including the package name
-			afterSlash := functionName[lastSlash+1:]
-			if dotIdx := strings.Index(afterSlash, "."); dotIdx >= 0 {
-				// Module path is everything before the type/function
-				modulePath := functionName[:lastSlash+1+dotIdx]
-				// Add the filename
-				fileName := filepath.Base(filePath)
-				return "@" + modulePath + "/" + fileName
-			}
-		}
+	if p, ok := moduleSourcePath(filePath, functionName); ok {
+		return p
 	}
 
 	// Fallback to just the filename
@@ -284,39 +1067,90 @@ func (h *Handler) formatSourcePath(filePath, functionName string) string {
 
 // WithAttrs returns a new Handler with the given attributes added.
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.redactor != nil {
+		attrs, _ = h.redactor.attrs(attrs)
+	}
+
 	// Copy preformattedAttrs to avoid aliasing the parent's backing array.
 	merged := make([]slog.Attr, len(h.preformattedAttrs), len(h.preformattedAttrs)+len(attrs))
 	copy(merged, h.preformattedAttrs)
 	merged = append(merged, attrs...)
 
-	newHandler := &Handler{
-		inner:             h.inner.WithAttrs(attrs),
-		globalLevel:       h.globalLevel,
-		filters:           h.filters,
-		hasSourceFilters:  h.hasSourceFilters,
+	nh := &Handler{
+		handlerState:      h.handlerState,
 		preformattedAttrs: merged,
-		workDir:           h.workDir,
+		groupPrefix:       h.groupPrefix,
+		groupedAttrs:      h.groupedAttrs,
+		scope:             h.withScope(scopeOp{attrs: attrs}),
+		component:         h.component,
+		hasComponent:      h.hasComponent,
 	}
-	newHandler.lowestLevel.Store(h.lowestLevel.Load())
-	return newHandler
+	if h.groupPrefix != "" {
+		grouped := make([]slog.Attr, len(h.groupedAttrs), len(h.groupedAttrs)+len(attrs))
+		copy(grouped, h.groupedAttrs)
+		for _, a := range attrs {
+			grouped = append(grouped, slog.Attr{Key: h.groupPrefix + a.Key, Value: a.Value})
+		}
+		nh.groupedAttrs = grouped
+	}
+	for _, a := range attrs {
+		if a.Key == h.componentKey {
+			nh.component, nh.hasComponent = attrValueToString(a.Value), true
+		}
+	}
+
+	// Derive the inner handler eagerly from the parent's; it is only rebuilt
+	// from scratch if the root inner handler is swapped.
+	ref, inner := h.resolveInner()
+	nh.innerCache.Store(&scopedHandler{key: ref, handler: inner.WithAttrs(attrs)})
+	return nh
 }
 
 // WithGroup returns a new Handler with the given group name.
 func (h *Handler) WithGroup(name string) slog.Handler {
-	newHandler := &Handler{
-		inner:             h.inner.WithGroup(name),
-		globalLevel:       h.globalLevel,
-		filters:           h.filters,
-		hasSourceFilters:  h.hasSourceFilters,
+	nh := &Handler{
+		handlerState:      h.handlerState,
 		preformattedAttrs: h.preformattedAttrs,
-		workDir:           h.workDir,
+		groupPrefix:       h.groupPrefix,
+		groupedAttrs:      h.groupedAttrs,
+		scope:             h.withScope(scopeOp{group: name}),
+		component:         h.component,
+		hasComponent:      h.hasComponent,
+	}
+	if name != "" {
+		nh.groupPrefix += name + "."
+	}
+
+	ref, inner := h.resolveInner()
+	nh.innerCache.Store(&scopedHandler{key: ref, handler: inner.WithGroup(name)})
+	return nh
+}
+
+// withScope returns a copy of the handler's scope with op appended.
+func (h *Handler) withScope(op scopeOp) []scopeOp {
+	scope := make([]scopeOp, len(h.scope), len(h.scope)+1)
+	copy(scope, h.scope)
+	return append(scope, op)
+}
+
+// applyScope replays the handler's WithAttrs/WithGroup calls onto target.
+func (h *Handler) applyScope(target slog.Handler) slog.Handler {
+	for _, op := range h.scope {
+		if op.group != "" {
+			target = target.WithGroup(op.group)
+		} else {
+			target = target.WithAttrs(op.attrs)
+		}
 	}
-	newHandler.lowestLevel.Store(h.lowestLevel.Load())
-	return newHandler
+	return target
 }
 
 // attrValueToString converts an slog.Value to a string for pattern matching.
+// A slog.LogValuer is resolved first, so filters match the value it logs as;
+// Resolve bounds chains of LogValuers, and recovers from a panicking one, as
+// slog's own handlers do.
 func attrValueToString(v slog.Value) string {
+	v = v.Resolve()
 	switch v.Kind() {
 	case slog.KindTime:
 		return v.Time().String()
@@ -3,9 +3,13 @@ package logfilter
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestHandler_BasicFiltering(t *testing.T) {
@@ -127,6 +131,100 @@ func TestHandler_ContextFilter(t *testing.T) {
 	}
 }
 
+func TestHandler_AnyFilter(t *testing.T) {
+	type ctxKey string
+	const jobIDKey ctxKey = "job_id"
+
+	RegisterContextExtractor("job_id", func(ctx context.Context) (string, bool) {
+		if v, ok := ctx.Value(jobIDKey).(string); ok {
+			return v, true
+		}
+		return "", false
+	})
+	defer ClearContextExtractors()
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: AnyPrefix + "job_id", Pattern: "job_abc*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		ctx   string
+		attr  string
+		emits bool
+	}{
+		{"attribute only", "", "job_abc1", true},
+		{"context only", "job_abc2", "", true},
+		{"attribute mismatch, context match", "job_abc3", "job_xyz", true},
+		{"neither matches", "job_xyz", "job_xyz", false},
+		{"neither present", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			ctx := context.Background()
+			if tt.ctx != "" {
+				ctx = context.WithValue(ctx, jobIDKey, tt.ctx)
+			}
+			var args []any
+			if tt.attr != "" {
+				args = append(args, "job_id", tt.attr)
+			}
+			logger.DebugContext(ctx, "step", args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_MessageFilter(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_quiet", Level: "error", Enabled: true},
+		{Type: MessageType, Pattern: "cache *", Level: "debug", Enabled: true},
+		{Type: MessageType, Pattern: "*refused*", Level: "debug", Enabled: true},
+		{Type: MessageType, Pattern: "tick", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		msg   string
+		args  []any
+		emits bool
+	}{
+		{"prefix", "cache miss", nil, true},
+		{"prefix mismatch", "no cache", nil, false},
+		{"contains", "dial: connection refused", nil, true},
+		{"exact", "tick", nil, true},
+		{"exact mismatch", "ticks", nil, false},
+		{"attribute filter matches first", "cache miss", []any{"job_id", "job_quiet"}, false},
+		{"attribute filter mismatch falls through", "cache miss", []any{"job_id", "job_loud"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.Debug(tt.msg, tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
 func TestHandler_FirstMatchWins(t *testing.T) {
 	var buf bytes.Buffer
 	level := new(slog.LevelVar)
@@ -201,6 +299,124 @@ func TestHandler_WithAttrs(t *testing.T) {
 	}
 }
 
+// TestHandler_ConcurrentFilterUpdates hammers every way of changing filters
+// while records are evaluated; run with -race, it checks that updates never
+// write to filters an evaluation may be reading.
+func TestHandler_ConcurrentFilterUpdates(t *testing.T) {
+	var buf syncBuffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaseFilters([]LogFilter{{Type: "tenant", Pattern: "base", Level: "debug", Enabled: true}}))
+	handler.SetFilterGroups(map[string][]LogFilter{
+		"oncall": {{Type: "service", Pattern: "billing", Level: "debug", Enabled: true}},
+	})
+	logger := slog.New(handler).With("service", "billing")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 500 {
+				logger.Debug("detail", "job_id", fmt.Sprintf("job_%d", j%10), "worker", i)
+				logger.Info("step", "tenant", "base")
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for i := 0; ; i++ {
+		select {
+		case <-done:
+		default:
+			pattern := fmt.Sprintf("job_%d", i%10)
+			switch i % 6 {
+			case 0:
+				handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: pattern, Level: "debug", Enabled: true}})
+			case 1:
+				handler.AddFilter(LogFilter{Type: "job_id", Pattern: pattern, Level: "debug", Priority: i % 3, Enabled: true})
+			case 2:
+				handler.UpsertFilter(LogFilter{Name: "named", Type: "job_id", Pattern: pattern, Level: "debug", Enabled: true})
+			case 3:
+				handler.RemoveFilter("job_id", pattern)
+			case 4:
+				handler.RemoveFilterByName("named")
+			case 5:
+				handler.SetFilterGroups(map[string][]LogFilter{
+					"oncall": {{Type: "service", Pattern: "billing", Level: "debug", Enabled: true}},
+				})
+			}
+			continue
+		}
+		break
+	}
+
+	if !strings.Contains(buf.String(), `"msg":"detail"`) {
+		t.Error("Expected group-elevated debug records during updates")
+	}
+}
+
+func TestHandler_WithAttrs_Concurrent(t *testing.T) {
+	var buf syncBuffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "worker", Pattern: "w3", Level: "debug", Enabled: true},
+	})
+
+	base := slog.New(handler).With("service", "api", "region", "eu")
+
+	const workers = 16
+	const perWorker = 50
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := fmt.Sprintf("w%d", i)
+			for j := range perWorker {
+				logger := base.With("worker", worker).With("seq", j)
+				logger.Info("step")
+				logger.Debug("detail")
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if want := workers*perWorker + perWorker; len(lines) != want {
+		t.Fatalf("Expected %d lines, got %d", want, len(lines))
+	}
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("Expected JSON line, got %q: %v", line, err)
+		}
+		if rec["service"] != "api" || rec["region"] != "eu" {
+			t.Errorf("Expected base attributes, got %s", line)
+		}
+		worker, _ := rec["worker"].(string)
+		if !strings.HasPrefix(worker, "w") {
+			t.Errorf("Expected a worker attribute, got %s", line)
+		}
+		if rec["msg"] == "detail" && worker != "w3" {
+			t.Errorf("Expected only w3 debug records, got %s", line)
+		}
+		if len(rec) != 7 { // time, level, msg, service, region, worker, seq
+			t.Errorf("Expected 7 keys, got %d: %s", len(rec), line)
+		}
+	}
+}
+
 func TestHandler_SetFilters(t *testing.T) {
 	level := new(slog.LevelVar)
 	level.Set(slog.LevelInfo)
@@ -635,3 +851,424 @@ func TestHandler_OutputLevel_PreservesAttributes(t *testing.T) {
 		t.Errorf("Expected count attribute to be preserved, got: %s", output)
 	}
 }
+
+func TestHandler_DerivedLoggerSeesLaterFilters(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+
+	// Derive before any filters exist
+	logger := slog.New(handler).With("job_id", "debug_1").WithGroup("g")
+
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+	})
+
+	buf.Reset()
+	logger.Debug("test message")
+	if buf.Len() == 0 {
+		t.Error("Expected derived logger to pick up filters set after derivation")
+	}
+}
+
+func TestHandler_RegexFilter(t *testing.T) {
+	var warnings bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&warnings, nil)))
+	defer slog.SetDefault(prev)
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "user_id", Pattern: "user_[", Regex: true, Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_[0-9a-f]{8}", Regex: true, Level: "debug", Enabled: true},
+	})
+
+	if !strings.Contains(warnings.String(), "invalid regex pattern") || !strings.Contains(warnings.String(), "type=user_id") {
+		t.Errorf("Expected a warning for the invalid regex, got: %s", warnings.String())
+	}
+
+	logger := slog.New(handler)
+	logger.Debug("matching", "job_id", "job_deadbeef")
+	if !strings.Contains(buf.String(), "matching") {
+		t.Error("Expected regex filter to match")
+	}
+
+	buf.Reset()
+	logger.Debug("not matching", "job_id", "job_xyz")
+	logger.Debug("invalid", "user_id", "user_[")
+	if buf.Len() > 0 {
+		t.Errorf("Expected non-matching and invalid regex filters to suppress, got: %s", buf.String())
+	}
+
+	// Same pattern as a glob is a different filter
+	glob := LogFilter{Type: "job_id", Pattern: "job_[0-9a-f]{8}", Level: "debug"}
+	regex := glob
+	regex.Regex = true
+	if DeriveFilterID(glob) == DeriveFilterID(regex) {
+		t.Error("Expected Regex to contribute to the derived ID")
+	}
+}
+
+func TestHandler_NegateFilter(t *testing.T) {
+	type ctxKey string
+	const tenantKey ctxKey = "tenant"
+
+	RegisterContextExtractor("tenant", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(tenantKey).(string)
+		return v, ok
+	})
+	defer ClearContextExtractors()
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		// Suppress debug for every job except job_critical
+		{Type: "job_id", Pattern: "job_critical", Negate: true, Level: "info", Enabled: true},
+		// Suppress debug for every tenant except acme
+		{Type: "context:tenant", Pattern: "acme", Negate: true, Level: "info", Enabled: true},
+	})
+	logger := slog.New(handler)
+	acme := context.WithValue(context.Background(), tenantKey, "acme")
+	other := context.WithValue(context.Background(), tenantKey, "globex")
+
+	tests := []struct {
+		name  string
+		log   func()
+		emits bool
+	}{
+		{"excluded job emits", func() { logger.Debug("step", "job_id", "job_critical") }, true},
+		{"other job suppressed", func() { logger.Debug("step", "job_id", "job_1") }, false},
+		{"no job attribute is unaffected", func() { logger.Debug("step") }, true},
+		{"excluded tenant emits", func() { logger.DebugContext(acme, "step") }, true},
+		{"other tenant suppressed", func() { logger.DebugContext(other, "step") }, false},
+		{"first match wins", func() { logger.DebugContext(acme, "step", "job_id", "job_1") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_NegateSourceFilter(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: SourceFilePrefix, Pattern: "*vendor*", Negate: true, Level: "debug", Enabled: true},
+	})
+
+	slog.New(handler).Debug("from this file")
+	if buf.Len() == 0 {
+		t.Error("Expected negated source filter to match files outside the pattern")
+	}
+}
+
+func TestHandler_RejectMatchAll(t *testing.T) {
+	var warnings bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&warnings, nil)))
+	defer slog.SetDefault(prev)
+
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithRejectMatchAll(true))
+
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "user_id", Pattern: "**", Level: "debug", Enabled: true, Confirmed: true},
+	})
+
+	filters := handler.GetFilters()
+	if len(filters) != 2 {
+		t.Fatalf("Expected unconfirmed match-all filter to be rejected, got %d filters", len(filters))
+	}
+	if filters[0].Pattern != "job_*" || filters[1].Type != "user_id" {
+		t.Errorf("Unexpected filters kept: %+v", filters)
+	}
+	if !strings.Contains(warnings.String(), "rejected unconfirmed match-all filter") ||
+		!strings.Contains(warnings.String(), "type=job_id") {
+		t.Errorf("Expected a warning for the rejected filter, got: %s", warnings.String())
+	}
+
+	warnings.Reset()
+	handler.AddFilter(LogFilter{Type: "endpoint", Pattern: "*", Level: "debug", Enabled: true})
+	if len(handler.GetFilters()) != 2 {
+		t.Error("Expected AddFilter to reject unconfirmed match-all filter")
+	}
+	if !strings.Contains(warnings.String(), "type=endpoint") {
+		t.Errorf("Expected a warning for the rejected filter, got: %s", warnings.String())
+	}
+}
+
+func TestHandler_RejectMatchAll_Regex(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithRejectMatchAll(true))
+
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: ".*", Regex: true, Level: "debug", Enabled: true},
+		{Type: "user_id", Pattern: "^(?s:.*)$", Regex: true, Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_.*", Regex: true, Level: "debug", Enabled: true},
+	})
+
+	filters := handler.GetFilters()
+	if len(filters) != 1 || filters[0].Pattern != "job_.*" {
+		t.Errorf("Expected catch-all regex filters to be rejected, got %+v", filters)
+	}
+}
+
+func TestHandler_MatchAllAllowedByDefault(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	handler.AddFilter(LogFilter{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true})
+	if len(handler.GetFilters()) != 1 {
+		t.Error("Expected match-all filter to be accepted without the guard")
+	}
+}
+
+func TestHandler_BaseFilters(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaseFilters([]LogFilter{
+			{Type: "path", Pattern: "/healthz", Level: "error", Enabled: true},
+		}))
+	logger := slog.New(handler)
+
+	// Base filter suppresses health checks even with an app filter that would elevate them
+	handler.SetFilters([]LogFilter{
+		{Type: "path", Pattern: "/*", Level: "debug", Enabled: true},
+	})
+
+	buf.Reset()
+	logger.Info("probe", "path", "/healthz")
+	if buf.Len() > 0 {
+		t.Errorf("Expected base filter to take precedence, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Debug("request", "path", "/api")
+	if buf.Len() == 0 {
+		t.Error("Expected app filter to apply when no base filter matches")
+	}
+
+	// GetFilters excludes base filters
+	if filters := handler.GetFilters(); len(filters) != 1 || filters[0].Pattern != "/*" {
+		t.Errorf("Expected GetFilters to return only app filters, got %+v", filters)
+	}
+	if base := handler.GetBaseFilters(); len(base) != 1 || base[0].Pattern != "/healthz" {
+		t.Errorf("Expected GetBaseFilters to return base filters, got %+v", base)
+	}
+
+	// Clearing app filters leaves base filters in place
+	handler.ClearFilters()
+	handler.RemoveFilter("path", "/healthz")
+	handler.SetFilters(nil)
+
+	buf.Reset()
+	logger.Warn("probe", "path", "/healthz")
+	if buf.Len() > 0 {
+		t.Errorf("Expected base filter to survive ClearFilters/SetFilters, got: %s", buf.String())
+	}
+	if len(handler.GetBaseFilters()) != 1 {
+		t.Error("Expected base filters to be immune to RemoveFilter")
+	}
+}
+
+func TestHandler_BaseFilters_LowestLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaseFilters([]LogFilter{
+			{Type: "tenant", Pattern: "acme", Level: "debug", Enabled: true},
+		}))
+
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected base filter to lower the enabled threshold")
+	}
+
+	handler.ClearFilters()
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected base filter threshold to survive ClearFilters")
+	}
+
+	slog.New(handler).Debug("traced", "tenant", "acme")
+	if buf.Len() == 0 {
+		t.Error("Expected base elevation filter to emit debug record")
+	}
+}
+
+func TestHandler_ZeroPC_SourceFiltersSkipped(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+	handler.SetFilters([]LogFilter{
+		// Would suppress everything below ERROR if it matched
+		{Type: SourceFilePrefix, Pattern: "*", Level: "error", Enabled: true},
+		{Type: SourceFunctionPrefix, Pattern: "*", Level: "error", Enabled: true},
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+	})
+
+	ctx := context.Background()
+
+	// Info record without PC: source filters can't match, global level applies
+	buf.Reset()
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "manual info", 0)
+	if err := handler.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected source filters not to suppress a record without PC")
+	}
+
+	// Debug record without PC: attribute filter after the source filters still applies
+	buf.Reset()
+	r = slog.NewRecord(time.Now(), slog.LevelDebug, "manual debug", 0)
+	r.AddAttrs(slog.String("job_id", "debug_1"))
+	if err := handler.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected attribute filter to apply to a record without PC")
+	}
+
+	// Debug record without PC and no matching attribute is suppressed by the global level
+	buf.Reset()
+	r = slog.NewRecord(time.Now(), slog.LevelDebug, "manual debug", 0)
+	r.AddAttrs(slog.String("job_id", "other"))
+	if err := handler.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("Expected unmatched debug record to be suppressed, got: %s", buf.String())
+	}
+
+	// The same source filters do suppress a record with a PC
+	buf.Reset()
+	slog.New(handler).Info("logged info", "job_id", "debug_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected source filter to match a record with PC, got: %s", buf.String())
+	}
+}
+
+func TestHandler_HandleBelowLowestLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "*", Level: "warn", Enabled: true, Confirmed: true},
+	})
+
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("Expected Enabled to reject debug below all filter levels")
+	}
+
+	// Bypass Enabled, as a custom wrapper might
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "too low", 0)
+	r.AddAttrs(slog.String("job_id", "job_1"))
+	if err := handler.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("Expected record below lowest level to be suppressed, got: %s", buf.String())
+	}
+	if got := handler.LevelStats()[slog.LevelDebug].Suppressed; got != 1 {
+		t.Errorf("Expected 1 suppressed debug record, got %d", got)
+	}
+	// The filter would match, but is never consulted
+	if got := handler.GetFilters()[0].state.matches.Load(); got != 0 {
+		t.Errorf("Expected filters to be skipped, got %d matches", got)
+	}
+}
+
+// tenantID logs as its name, as an ID type hiding its fields might.
+type tenantID struct{ name string }
+
+func (t tenantID) LogValue() slog.Value { return slog.StringValue("tenant-" + t.name) }
+
+// loopValuer resolves to itself forever.
+type loopValuer struct{}
+
+func (l loopValuer) LogValue() slog.Value { return slog.AnyValue(l) }
+
+func TestAttrValueToString_LogValuer(t *testing.T) {
+	if got := attrValueToString(slog.AnyValue(tenantID{"acme"})); got != "tenant-acme" {
+		t.Errorf("Expected LogValuer resolved to \"tenant-acme\", got %q", got)
+	}
+	elems, ok := attrValueElems(slog.AnyValue(tagsValuer{"a", "b"}))
+	if !ok || len(elems) != 2 || elems[1] != "b" {
+		t.Errorf("Expected LogValuer resolved to a slice, got %v, %v", elems, ok)
+	}
+
+	// A LogValuer that never resolves must not hang.
+	done := make(chan string)
+	go func() { done <- attrValueToString(slog.AnyValue(loopValuer{})) }()
+	select {
+	case got := <-done:
+		if got == "" {
+			t.Error("Expected a non-empty placeholder for an unresolvable LogValuer")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected resolution of a self-referencing LogValuer to stop")
+	}
+}
+
+// tagsValuer logs as a slice of tags.
+type tagsValuer []string
+
+func (t tagsValuer) LogValue() slog.Value { return slog.AnyValue([]string(t)) }
+
+func TestHandler_LogValuerAttribute(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "tenant", Pattern: "tenant-acme", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("record attribute", "tenant", tenantID{"acme"})
+	if !strings.Contains(buf.String(), "tenant=tenant-acme") {
+		t.Errorf("Expected record LogValuer to match its resolved form, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.With("tenant", tenantID{"acme"}).Debug("logger attribute")
+	if buf.Len() == 0 {
+		t.Error("Expected logger LogValuer to match its resolved form")
+	}
+
+	buf.Reset()
+	logger.Debug("other tenant", "tenant", tenantID{"globex"})
+	if buf.Len() > 0 {
+		t.Errorf("Expected other tenant suppressed, got: %s", buf.String())
+	}
+}
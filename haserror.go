@@ -0,0 +1,80 @@
+package logfilter
+
+import (
+	"log/slog"
+)
+
+// HasErrorType is the filter type that matches records carrying an error.
+// A record carries an error when its level is at least slog.LevelError, or
+// when it (or the logger, via With) has an attribute whose value is an error
+// or whose key is "error".
+//
+// The pattern is matched against the error's text, taken from the first such
+// attribute; it is empty for error-level records without one. Use "*" to
+// match every error-carrying record.
+const HasErrorType = "has-error"
+
+// errorAttrKey is the attribute key treated as an error regardless of value type.
+const errorAttrKey = "error"
+
+// IsHasErrorFilter returns true if this filter matches error-carrying records.
+func (f *LogFilter) IsHasErrorFilter() bool {
+	return f.Type == HasErrorType
+}
+
+// recordError returns the error text carried by the record and whether it
+// carries an error at all. Record attributes are checked before the logger's.
+func (h *Handler) recordError(r slog.Record) (string, bool) {
+	var text string
+	var found bool
+	r.Attrs(func(a slog.Attr) bool {
+		text, found = attrError(a)
+		return !found
+	})
+	if !found {
+		for _, a := range h.preformattedAttrs {
+			if text, found = attrError(a); found {
+				break
+			}
+		}
+	}
+	if found || r.Level >= slog.LevelError {
+		return text, true
+	}
+	return "", false
+}
+
+// recordErrorValue returns the value of the record's first error attribute,
+// checking record attributes before the logger's, as recordError.
+func (h *Handler) recordErrorValue(r slog.Record) (slog.Value, bool) {
+	var val slog.Value
+	var found bool
+	r.Attrs(func(a slog.Attr) bool {
+		if _, found = attrError(a); found {
+			val = a.Value
+		}
+		return !found
+	})
+	if found {
+		return val, true
+	}
+	for _, a := range h.preformattedAttrs {
+		if _, ok := attrError(a); ok {
+			return a.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// attrError reports whether a is an error attribute and returns its text.
+func attrError(a slog.Attr) (string, bool) {
+	if a.Value.Kind() == slog.KindAny {
+		if err, ok := a.Value.Any().(error); ok {
+			return err.Error(), true
+		}
+	}
+	if a.Key == errorAttrKey {
+		return attrValueToString(a.Value), true
+	}
+	return "", false
+}
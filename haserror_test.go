@@ -0,0 +1,68 @@
+package logfilter
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_HasErrorFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		log     func(*slog.Logger)
+		match   bool
+	}{
+		{"error-typed attr", "*", func(l *slog.Logger) { l.Warn("retry", "err", errors.New("timeout")) }, true},
+		{"error key", "*", func(l *slog.Logger) { l.Warn("retry", "error", "timeout") }, true},
+		{"error level", "*", func(l *slog.Logger) { l.Error("failed") }, true},
+		{"logger attr", "*", func(l *slog.Logger) { l.With("cause", errors.New("eof")).Warn("retry") }, true},
+		{"no error", "*", func(l *slog.Logger) { l.Warn("retry", "attempt", 2) }, false},
+		{"pattern on text", "*timeout*", func(l *slog.Logger) { l.Warn("retry", "err", errors.New("read timeout")) }, true},
+		{"pattern mismatch", "*timeout*", func(l *slog.Logger) { l.Warn("retry", "err", errors.New("eof")) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+			handler.SetFilters([]LogFilter{
+				{Type: HasErrorType, Pattern: tt.pattern, Level: "debug", OutputLevel: "error", Enabled: true},
+			})
+
+			tt.log(slog.New(handler))
+			matched := strings.Contains(buf.String(), "level=ERROR")
+			if matched != tt.match {
+				t.Errorf("Expected match=%v, got output: %s", tt.match, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_HasErrorFilter_ElevatesDebug(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: HasErrorType, Pattern: "*", Level: "debug", Enabled: true},
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("cache miss", "err", errors.New("not found"))
+	logger.Debug("cache hit")
+
+	out := buf.String()
+	if !strings.Contains(out, "cache miss") {
+		t.Errorf("Expected error-carrying debug to be emitted, got: %s", out)
+	}
+	if strings.Contains(out, "cache hit") {
+		t.Errorf("Expected plain debug to be suppressed, got: %s", out)
+	}
+}
@@ -0,0 +1,43 @@
+package logfilter
+
+import "log/slog"
+
+// MatchHook is called with the filter that matched a record and the record.
+type MatchHook func(f LogFilter, r slog.Record)
+
+// SetMatchHook sets a function called from Handle whenever a filter matches a
+// record, before the record is emitted, e.g. for auditing or alerting on a
+// specific filter. It is called for every match, including matches by
+// filters that suppress the record, with the record as logged (before any
+// output level transformation). Passing nil removes the hook.
+//
+// The hook runs synchronously on the logging goroutine, so it must be fast;
+// dispatch slow work, such as network calls, asynchronously. It must not log
+// through this handler.
+func (h *Handler) SetMatchHook(hook MatchHook) {
+	if hook == nil {
+		h.matchHook.Store(nil)
+		return
+	}
+	h.matchHook.Store(&hook)
+}
+
+// callMatchHook calls the match hook, if any, for a record matched by f.
+func (h *Handler) callMatchHook(f *LogFilter, r slog.Record) {
+	hook := h.matchHook.Load()
+	if hook == nil || f == nil {
+		return
+	}
+	(*hook)(*f, r.Clone())
+}
+
+// SetMatchHook sets the match hook on the global handler.
+func SetMatchHook(hook MatchHook) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.SetMatchHook(hook)
+	}
+}
@@ -0,0 +1,68 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_SetMatchHook(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Name: "jobs", Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "info", Enabled: true},
+		{Name: "quiet", Type: "service", Pattern: "noisy", Level: "error", Enabled: true},
+	})
+
+	type call struct {
+		filter LogFilter
+		record slog.Record
+	}
+	var calls []call
+	handler.SetMatchHook(func(f LogFilter, r slog.Record) {
+		calls = append(calls, call{f, r})
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("processing", "job_id", "job_42")
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 hook call, got %d", len(calls))
+	}
+	if calls[0].filter.Name != "jobs" || calls[0].filter.Pattern != "job_*" {
+		t.Errorf("Expected hook to receive the jobs filter, got %+v", calls[0].filter)
+	}
+	if calls[0].record.Message != "processing" {
+		t.Errorf("Expected message %q, got %q", "processing", calls[0].record.Message)
+	}
+	if calls[0].record.Level != slog.LevelDebug {
+		t.Errorf("Expected the record as logged (DEBUG), got %v", calls[0].record.Level)
+	}
+	if buf.Len() == 0 {
+		t.Errorf("Expected record to be emitted")
+	}
+
+	// Suppressing filters fire too
+	buf.Reset()
+	logger.Info("tick", "service", "noisy")
+	if len(calls) != 2 || calls[1].filter.Name != "quiet" {
+		t.Errorf("Expected hook call for the suppressing filter, got %d calls", len(calls))
+	}
+	if buf.Len() > 0 {
+		t.Errorf("Expected record to be suppressed, got: %s", buf.String())
+	}
+
+	// No match, no call
+	logger.Info("other", "job_id", "task_1")
+	if len(calls) != 2 {
+		t.Errorf("Expected no hook call without a match, got %d calls", len(calls))
+	}
+
+	handler.SetMatchHook(nil)
+	logger.Debug("processing", "job_id", "job_43")
+	if len(calls) != 2 {
+		t.Errorf("Expected no hook call after removing the hook, got %d calls", len(calls))
+	}
+}
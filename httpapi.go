@@ -0,0 +1,100 @@
+package logfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// maxFilterBodyBytes caps the size of a POSTed filter.
+const maxFilterBodyBytes = 1 << 20
+
+// NewFilterAPI returns an http.Handler for inspecting and changing h's filters
+// at runtime, serving JSON:
+//
+//	GET    /filters        the current filters, as GetFilters
+//	POST   /filters        add the LogFilter in the body, as AddFilter
+//	DELETE /filters/{name} remove the filter with that Name, as RemoveFilterByName
+//
+// A POSTed filter must pass LogFilter.Validate and have a name no other filter
+// has; otherwise, or if the body is malformed, the response is 400 (409 for a
+// taken name). When h rejects unconfirmed match-all filters
+// (WithRejectMatchAll), so does the API.
+// Successful POSTs respond 201 with the filter, DELETEs 204, or 404 if no
+// filter has the name. Errors are JSON objects with an "error" field.
+//
+// The API has no authentication; mount it only where access is controlled,
+// e.g. behind an admin listener, or with http.StripPrefix under a subpath.
+func NewFilterAPI(h *Handler) http.Handler {
+	api := &filterAPI{h: h}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /filters", api.list)
+	mux.HandleFunc("POST /filters", api.add)
+	mux.HandleFunc("DELETE /filters/{name}", api.remove)
+	return mux
+}
+
+// filterAPI serves NewFilterAPI.
+type filterAPI struct {
+	h *Handler
+}
+
+func (api *filterAPI) list(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.h.GetFilters())
+}
+
+func (api *filterAPI) add(w http.ResponseWriter, r *http.Request) {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFilterBodyBytes))
+	dec.DisallowUnknownFields()
+
+	var f LogFilter
+	if err := dec.Decode(&f); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("malformed filter: %v", err))
+		return
+	}
+	if dec.More() {
+		writeError(w, http.StatusBadRequest, "malformed filter: trailing data after JSON object")
+		return
+	}
+	if err := api.validate(f); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !api.h.addFilterIfNameFree(f) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("a filter named %q already exists", f.Name))
+		return
+	}
+	writeJSON(w, http.StatusCreated, f)
+}
+
+// validate reports why f can't be added, if it can't.
+func (api *filterAPI) validate(f LogFilter) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if _, rejected := api.h.screenFilters([]LogFilter{f}); len(rejected) > 0 {
+		return fmt.Errorf("pattern %q matches every value; set confirmed to add it", f.Pattern)
+	}
+	return nil
+}
+
+func (api *filterAPI) remove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !api.h.RemoveFilterByName(name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no filter named %q", name))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJSON writes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,187 @@
+package logfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestAPI(t *testing.T, opts ...Option) (*Handler, *httptest.Server) {
+	t.Helper()
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, opts...)
+	srv := httptest.NewServer(NewFilterAPI(handler))
+	t.Cleanup(srv.Close)
+	return handler, srv
+}
+
+func doRequest(t *testing.T, method, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(resp.Body)
+	return resp, buf.Bytes()
+}
+
+func TestFilterAPI_GetFilters(t *testing.T) {
+	handler, srv := newTestAPI(t)
+
+	resp, body := doRequest(t, http.MethodGet, srv.URL+"/filters", "")
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+		t.Errorf("Expected 200 with [], got %d %s", resp.StatusCode, body)
+	}
+
+	handler.SetFilters([]LogFilter{{Name: "jobs", Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true}})
+	resp, body = doRequest(t, http.MethodGet, srv.URL+"/filters", "")
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+	var filters []LogFilter
+	if err := json.Unmarshal(body, &filters); err != nil {
+		t.Fatalf("Expected JSON array, got %s: %v", body, err)
+	}
+	if len(filters) != 1 || filters[0].Name != "jobs" || filters[0].Pattern != "job_*" {
+		t.Errorf("Expected the handler's filters, got %+v", filters)
+	}
+}
+
+func TestFilterAPI_PostFilter(t *testing.T) {
+	handler, srv := newTestAPI(t)
+
+	resp, body := doRequest(t, http.MethodPost, srv.URL+"/filters",
+		`{"name": "jobs", "type": "job_id", "pattern": "job_*", "level": "debug", "enabled": true}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d %s", resp.StatusCode, body)
+	}
+	if f, ok := handler.GetFilterByName("jobs"); !ok || f.Level != "debug" || !f.Enabled {
+		t.Errorf("Expected filter added to handler, got %+v (found=%v)", f, ok)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, srv.URL+"/filters",
+		`{"name": "jobs", "type": "user_id", "pattern": "u1", "level": "debug"}`)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for a taken name, got %d %s", resp.StatusCode, body)
+	}
+	if n := len(handler.GetFilters()); n != 1 {
+		t.Errorf("Expected 1 filter, got %d", n)
+	}
+}
+
+func TestFilterAPI_PostFilter_ConcurrentSameName(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	api := NewFilterAPI(handler)
+
+	const posts = 20
+	codes := make(chan int, posts)
+	var wg sync.WaitGroup
+	for i := 0; i < posts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/filters",
+				strings.NewReader(`{"name": "jobs", "type": "job_id", "pattern": "job_*", "level": "debug"}`))
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, req)
+			codes <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	created := 0
+	for code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+		default:
+			t.Errorf("Expected 201 or 409, got %d", code)
+		}
+	}
+	if created != 1 || len(handler.GetFilters()) != 1 {
+		t.Errorf("Expected exactly one filter added, got %d created and %d filters", created, len(handler.GetFilters()))
+	}
+}
+
+func TestFilterAPI_PostFilter_BadRequest(t *testing.T) {
+	handler, srv := newTestAPI(t, WithRejectMatchAll(true))
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"malformed JSON", `{"type": `, "malformed filter"},
+		{"not an object", `["job_id"]`, "malformed filter"},
+		{"unknown field", `{"type": "job_id", "patern": "x"}`, "malformed filter"},
+		{"trailing data", `{"type": "job_id", "pattern": "x"} {}`, "trailing data"},
+		{"missing type", `{"pattern": "x", "level": "debug"}`, "empty filter type"},
+		{"unknown level", `{"type": "job_id", "pattern": "x", "level": "verbose"}`, `unknown level "verbose"`},
+		{"unknown output level", `{"type": "job_id", "pattern": "x", "output_level": "loud"}`, `unknown level "loud" in output_level`},
+		{"invalid regex", `{"type": "job_id", "pattern": "job_(", "regex": true}`, "regex"},
+		{"invalid numeric", `{"type": "ms", "pattern": ">fast", "numeric": true}`, "numeric"},
+		{"empty context key", `{"type": "context:", "pattern": "x"}`, "empty context key"},
+		{"unconfirmed match-all", `{"type": "job_id", "pattern": "*", "level": "debug"}`, "matches every value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doRequest(t, http.MethodPost, srv.URL+"/filters", tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d %s", resp.StatusCode, body)
+			}
+			var e struct{ Error string }
+			if err := json.Unmarshal(body, &e); err != nil || !strings.Contains(e.Error, tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %s", tt.wantErr, body)
+			}
+		})
+	}
+
+	if n := len(handler.GetFilters()); n != 0 {
+		t.Errorf("Expected no filters added, got %d", n)
+	}
+}
+
+func TestFilterAPI_DeleteFilter(t *testing.T) {
+	handler, srv := newTestAPI(t)
+	handler.SetFilters([]LogFilter{
+		{Name: "a", Type: "job_id", Pattern: "x", Level: "debug", Enabled: true},
+		{Name: "b", Type: "job_id", Pattern: "x", Level: "error", Enabled: true},
+	})
+
+	resp, body := doRequest(t, http.MethodDelete, srv.URL+"/filters/a", "")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d %s", resp.StatusCode, body)
+	}
+	if filters := handler.GetFilters(); len(filters) != 1 || filters[0].Name != "b" {
+		t.Errorf("Expected only filter b left, got %+v", filters)
+	}
+
+	resp, body = doRequest(t, http.MethodDelete, srv.URL+"/filters/a", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing name, got %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestFilterAPI_MethodNotAllowed(t *testing.T) {
+	_, srv := newTestAPI(t)
+
+	resp, _ := doRequest(t, http.MethodPut, srv.URL+"/filters", "{}")
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,82 @@
+package logfilter
+
+// minIndexedFilters is the number of exact-match filters on the same
+// attribute key from which a filter list gets an index for that key.
+// Below it a linear scan is as fast.
+const minIndexedFilters = 8
+
+// filterIndex speeds up first-match evaluation of lists dominated by
+// exact-match attribute filters, such as many specific job_ids. Indexed
+// filters are found by a map lookup of the record's value instead of a scan;
+// the remaining filters are scanned as usual. Positions are kept so that
+// first match wins exactly as in a linear scan.
+type filterIndex struct {
+	keys  []string                    // Indexed attribute keys
+	exact map[string]map[string][]int // Key -> value -> positions in the list, ascending
+	rest  []int                       // Positions of filters not in the index, ascending
+}
+
+// indexable reports whether f can be found through an exact-match index.
+func (f *LogFilter) indexable() bool {
+	return f.kind == filterKindAttribute && f.keyGlob == nil && !f.keyPath && !f.Negate && f.MatchMode == "" && !f.scoped() && f.matcher.kind == matchExact && len(f.conditions) == 0
+}
+
+// buildFilterIndex returns an index for the prepared list, or nil if no
+// attribute key has enough exact-match filters to be worth indexing.
+func buildFilterIndex(list []LogFilter) *filterIndex {
+	counts := make(map[string]int)
+	for i := range list {
+		if list[i].indexable() {
+			counts[list[i].attributeKey]++
+		}
+	}
+
+	idx := &filterIndex{exact: make(map[string]map[string][]int)}
+	for i := range list {
+		f := &list[i]
+		if !f.indexable() || counts[f.attributeKey] < minIndexedFilters {
+			idx.rest = append(idx.rest, i)
+			continue
+		}
+		values, ok := idx.exact[f.attributeKey]
+		if !ok {
+			values = make(map[string][]int)
+			idx.exact[f.attributeKey] = values
+			idx.keys = append(idx.keys, f.attributeKey)
+		}
+		values[f.matcher.literal] = append(values[f.matcher.literal], i)
+	}
+	if len(idx.keys) == 0 {
+		return nil
+	}
+	return idx
+}
+
+// firstIndexed returns the position of the earliest active indexed filter
+// matching the record, or -1. Slice attributes are looked up by element.
+func (v *recordView) firstIndexed(list []LogFilter, idx *filterIndex) (int, string) {
+	best, bestValue := -1, ""
+	lookup := func(values map[string][]int, value string) {
+		for _, p := range values[value] {
+			if best >= 0 && p >= best {
+				break
+			}
+			if v.active(&list[p]) {
+				best, bestValue = p, value
+				break
+			}
+		}
+	}
+	for _, key := range idx.keys {
+		if elems, ok := v.attrElems(key); ok {
+			for _, e := range elems {
+				lookup(idx.exact[key], e)
+			}
+			continue
+		}
+		if value, ok := v.attr(key); ok {
+			lookup(idx.exact[key], value)
+		}
+	}
+	return best, bestValue
+}
@@ -0,0 +1,134 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// exactFilters returns n exact-match debug filters on job_id.
+func exactFilters(n int) []LogFilter {
+	filters := make([]LogFilter, n)
+	for i := range filters {
+		filters[i] = LogFilter{Type: "job_id", Pattern: fmt.Sprintf("job_%d", i), Level: "debug", Enabled: true}
+	}
+	return filters
+}
+
+func TestBuildFilterIndex(t *testing.T) {
+	small := exactFilters(minIndexedFilters - 1)
+	for i := range small {
+		small[i].prepare()
+	}
+	if buildFilterIndex(small) != nil {
+		t.Error("Expected no index below the threshold")
+	}
+
+	filters := append(exactFilters(minIndexedFilters),
+		LogFilter{Type: "job_id", Pattern: "job_*", Level: "warn", Enabled: true},
+		LogFilter{Type: "labels.env", Pattern: "prod", Level: "debug", Enabled: true},
+	)
+	for i := range filters {
+		filters[i].prepare()
+	}
+	idx := buildFilterIndex(filters)
+	if idx == nil {
+		t.Fatal("Expected an index at the threshold")
+	}
+	if len(idx.keys) != 1 || idx.keys[0] != "job_id" {
+		t.Errorf("Expected job_id to be indexed, got %v", idx.keys)
+	}
+	if len(idx.rest) != 2 || idx.rest[0] != minIndexedFilters || idx.rest[1] != minIndexedFilters+1 {
+		t.Errorf("Expected glob and key path filters to stay unindexed, got %v", idx.rest)
+	}
+}
+
+func TestHandler_ExactIndex_FirstMatchWins(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		filters []LogFilter
+		job     string
+		emits   bool
+	}{
+		{
+			"indexed match",
+			exactFilters(20),
+			"job_7", true,
+		},
+		{
+			"earlier glob wins over indexed match",
+			append([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "error", Enabled: true}}, exactFilters(20)...),
+			"job_7", false,
+		},
+		{
+			"indexed match wins over later glob",
+			append(exactFilters(20), LogFilter{Type: "job_id", Pattern: "job_*", Level: "error", Enabled: true}),
+			"job_7", true,
+		},
+		{
+			"disabled duplicate falls through to later one",
+			append([]LogFilter{{Type: "job_id", Pattern: "job_7", Level: "debug", Enabled: false}}, exactFilters(20)...),
+			"job_7", true,
+		},
+		{
+			"expired indexed filter is skipped",
+			append([]LogFilter{{Type: "job_id", Pattern: "job_x", Level: "debug", Enabled: true, ExpiresAt: &past}}, exactFilters(20)...),
+			"job_x", false,
+		},
+		{
+			"first of duplicate exact filters wins",
+			append([]LogFilter{{Type: "job_id", Pattern: "job_7", Level: "error", Enabled: true}}, exactFilters(20)...),
+			"job_7", false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+			handler.SetFilters(tt.filters)
+			if handler.filtersIndex == nil {
+				t.Fatal("Expected the filter list to be indexed")
+			}
+
+			slog.New(handler).Debug("step", "job_id", tt.job)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func BenchmarkHandle_ExactFilters(b *testing.B) {
+	for _, mode := range []string{"indexed", "linear"} {
+		b.Run(mode, func(b *testing.B) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+			handler.SetFilters(exactFilters(1000))
+			if mode == "linear" {
+				handler.filtersIndex = nil
+			}
+
+			r := slog.NewRecord(time.Now(), slog.LevelDebug, "step", 0)
+			r.AddAttrs(slog.String("job_id", "job_999"))
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = handler.Handle(ctx, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package logfilter
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// lookupPath resolves a dotted attribute key such as "labels.env" that does
+// not name an attribute directly. The longest prefix naming an attribute is
+// looked up, and the rest of the path navigates into its value: group
+// members, or keys of a map with string keys (e.g. map[string]string held by
+// slog.Any). It fails closed, returning false, if any step doesn't resolve.
+// scope restricts the attributes consulted, as LogFilter.MatchScope.
+func (v *recordView) lookupPath(key, scope string) (string, bool) {
+	val, ok := v.rawPath(key, scope)
+	if !ok {
+		return "", false
+	}
+	return v.h.matchString(val), true
+}
+
+// rawPath is lookupPath returning the unconverted value.
+func (v *recordView) rawPath(key, scope string) (slog.Value, bool) {
+	for i := strings.LastIndexByte(key, '.'); i > 0; i = strings.LastIndexByte(key[:i], '.') {
+		val, ok := v.rawAttr(key[:i], scope)
+		if !ok {
+			continue
+		}
+		return navigatePath(val, strings.Split(key[i+1:], "."))
+	}
+	return slog.Value{}, false
+}
+
+// rawAttr returns the unconverted value of the attribute with the given key,
+// with the precedence of attr, including group-qualified keys under
+// WithGroup, or from only the record's or the logger's attributes as scope
+// requires.
+func (v *recordView) rawAttr(key, scope string) (slog.Value, bool) {
+	var s attrSlot
+	if scope != MatchScopePreformatted {
+		s = v.scanRecordAttr(key)
+	}
+	if s.rank == attrUnset && scope != MatchScopeRecord {
+		s = v.scanLoggerAttr(key)
+	}
+	return s.val, s.rank != attrUnset
+}
+
+// navigatePath follows path through group and map values.
+func navigatePath(val slog.Value, path []string) (slog.Value, bool) {
+	for _, seg := range path {
+		val = val.Resolve()
+		switch val.Kind() {
+		case slog.KindGroup:
+			next, ok := groupMember(val.Group(), seg)
+			if !ok {
+				return slog.Value{}, false
+			}
+			val = next
+		case slog.KindAny:
+			next, ok := mapEntry(val.Any(), seg)
+			if !ok {
+				return slog.Value{}, false
+			}
+			val = next
+		default:
+			return slog.Value{}, false
+		}
+	}
+	return val, true
+}
+
+// groupMember returns the value of the last group member named key.
+func groupMember(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// mapEntry returns m[key] if m is a map with string keys.
+func mapEntry(m any, key string) (slog.Value, bool) {
+	switch m := m.(type) {
+	case map[string]string:
+		s, ok := m[key]
+		return slog.StringValue(s), ok
+	case map[string]any:
+		x, ok := m[key]
+		return slog.AnyValue(x), ok
+	}
+
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return slog.Value{}, false
+	}
+	x := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+	if !x.IsValid() {
+		return slog.Value{}, false
+	}
+	return slog.AnyValue(x.Interface()), true
+}
@@ -0,0 +1,147 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+type labelMap map[string]string
+
+func TestHandler_KeyPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		pattern string
+		args    []any
+		emits   bool
+	}{
+		{"map[string]string", "labels.env", "prod", []any{"labels", map[string]string{"env": "prod"}}, true},
+		{"map value mismatch", "labels.env", "prod", []any{"labels", map[string]string{"env": "dev"}}, false},
+		{"missing map key", "labels.env", "*", []any{"labels", map[string]string{"team": "core"}}, false},
+		{"missing attribute", "labels.env", "*", []any{"other", "x"}, false},
+		{"not a map", "labels.env", "*", []any{"labels", "env=prod"}, false},
+		{"map[string]any nested", "meta.k8s.ns", "kube-*", []any{"meta", map[string]any{"k8s": map[string]any{"ns": "kube-system"}}}, true},
+		{"named map type", "labels.env", "prod", []any{"labels", labelMap{"env": "prod"}}, true},
+		{"group attribute", "req.method", "POST", []any{slog.Group("req", "method", "POST")}, true},
+		{"dotted attribute key wins", "labels.env", "direct", []any{"labels.env", "direct", "labels", map[string]string{"env": "prod"}}, true},
+		{"non-string map keys", "codes.1", "*", []any{"codes", map[int]string{1: "x"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+			handler.SetFilters([]LogFilter{
+				{Type: tt.typ, Pattern: tt.pattern, Level: "debug", Enabled: true},
+			})
+
+			slog.New(handler).Debug("msg", tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_KeyPath_LoggerAttr(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "labels.env", Pattern: "prod", Level: "debug", Enabled: true},
+	})
+
+	slog.New(handler).With("labels", map[string]string{"env": "prod"}).Debug("from With")
+	if buf.Len() == 0 {
+		t.Error("Expected key path to resolve attributes added with With")
+	}
+}
+
+func TestHandler_GroupQualifiedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "http.status", Pattern: "500", Level: "debug", Enabled: true},
+		{Type: "http.db.table", Pattern: "orders", Level: "debug", Enabled: true},
+		{Type: "http.route", Pattern: "/checkout", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+	})
+	root := slog.New(handler)
+	httpLog := root.WithGroup("http")
+
+	tests := []struct {
+		name   string
+		logger *slog.Logger
+		args   []any
+		emits  bool
+	}{
+		{"qualified key", httpLog, []any{"status", 500}, true},
+		{"qualified key mismatch", httpLog, []any{"status", 200}, false},
+		{"ungrouped key is not qualified", root, []any{"status", 500}, false},
+		{"nested groups", httpLog.WithGroup("db"), []any{"table", "orders"}, true},
+		{"nested group is not the outer group", httpLog.WithGroup("db"), []any{"status", 500}, false},
+		{"With under a group", httpLog.With("route", "/checkout"), nil, true},
+		{"With before the group", root.With("route", "/checkout").WithGroup("http"), nil, false},
+		{"bare key still matches under a group", httpLog, []any{"job_id", "job_1"}, true},
+		{"ungrouped attribute unchanged", root, []any{"job_id", "job_1"}, true},
+		{"empty group name adds no prefix", root.WithGroup(""), []any{"job_id", "job_1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.logger.Debug("req", tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GroupQualifiedKeyPaths(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "http.req.id", Pattern: "7", Level: "debug", Enabled: true},
+		{Type: "http.labels.env", Pattern: "prod", Level: "debug", Enabled: true},
+	})
+	root := slog.New(handler)
+	httpLog := root.WithGroup("http")
+
+	tests := []struct {
+		name   string
+		logger *slog.Logger
+		args   []any
+		emits  bool
+	}{
+		{"group under WithGroup", httpLog, []any{slog.Group("req", "id", 7)}, true},
+		{"same record fully grouped", root, []any{slog.Group("http", slog.Group("req", "id", 7))}, true},
+		{"mismatch under WithGroup", httpLog, []any{slog.Group("req", "id", 8)}, false},
+		{"map under WithGroup", httpLog, []any{"labels", map[string]string{"env": "prod"}}, true},
+		{"With under WithGroup", httpLog.With(slog.Group("req", "id", 7)), nil, true},
+		{"ungrouped is not qualified", root, []any{slog.Group("req", "id", 7)}, false},
+		{"other group", root.WithGroup("rpc"), []any{slog.Group("req", "id", 7)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.logger.Debug("x", tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
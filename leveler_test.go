@@ -0,0 +1,89 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+)
+
+// sharedLevel is a Leveler owned elsewhere, without a Set method.
+type sharedLevel struct {
+	level atomic.Int64
+}
+
+func (s *sharedLevel) Level() slog.Level { return slog.Level(s.level.Load()) }
+
+// settableLevel is a custom Leveler that SetLevel can change.
+type settableLevel struct {
+	sharedLevel
+}
+
+func (s *settableLevel) Set(level slog.Level) { s.level.Store(int64(level)) }
+
+func TestNewHandlerWithLeveler(t *testing.T) {
+	var buf bytes.Buffer
+	shared := &sharedLevel{}
+	shared.level.Store(int64(slog.LevelWarn))
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandlerWithLeveler(inner, shared)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		level slog.Level // Set on the shared Leveler before logging
+		log   func()
+		want  bool
+	}{
+		{"info below shared level", slog.LevelWarn, func() { logger.Info("info") }, false},
+		{"warn at shared level", slog.LevelWarn, func() { logger.Warn("warn") }, true},
+		{"filter still elevates", slog.LevelWarn, func() { logger.Debug("debug", "job_id", "job_1") }, true},
+		{"owner lowers level", slog.LevelInfo, func() { logger.Info("info") }, true},
+		{"owner raises level", slog.LevelError, func() { logger.Warn("warn") }, false},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		shared.level.Store(int64(tt.level))
+		tt.log()
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.want, buf.String())
+		}
+		if got := handler.GetLevel(); got != tt.level {
+			t.Errorf("%s: expected GetLevel %v, got %v", tt.name, tt.level, got)
+		}
+	}
+}
+
+func TestNewHandlerWithLeveler_SetLevel(t *testing.T) {
+	inner := slog.NewTextHandler(&bytes.Buffer{}, nil)
+
+	shared := &sharedLevel{}
+	handler := NewHandlerWithLeveler(inner, shared)
+	handler.SetLevel(slog.LevelError)
+	if got := shared.Level(); got != slog.LevelInfo {
+		t.Errorf("Expected SetLevel to leave a Leveler without Set alone, got %v", got)
+	}
+
+	settable := &settableLevel{}
+	handler = NewHandlerWithLeveler(inner, settable)
+	handler.SetLevel(slog.LevelError)
+	if got := settable.Level(); got != slog.LevelError {
+		t.Errorf("Expected SetLevel to set a Leveler with Set, got %v", got)
+	}
+
+	// A constant slog.Level works as a fixed global level.
+	handler = NewHandlerWithLeveler(inner, slog.LevelWarn)
+	handler.SetLevel(slog.LevelDebug)
+	if handler.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Expected info disabled under a constant warn level")
+	}
+	if !handler.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("Expected warn enabled under a constant warn level")
+	}
+}
@@ -0,0 +1,172 @@
+package logfilter
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LevelTrace is the conventional level for tracing below debug. ParseLevel
+// recognizes it as "trace".
+const LevelTrace = slog.Level(-8)
+
+// builtinLevels are the level names ParseLevelStrict always recognizes.
+// They can't be redefined by RegisterLevelName.
+var builtinLevels = map[string]slog.Level{
+	"trace":   LevelTrace,
+	"debug":   slog.LevelDebug,
+	"info":    slog.LevelInfo,
+	"warn":    slog.LevelWarn,
+	"warning": slog.LevelWarn,
+	"error":   slog.LevelError,
+}
+
+// customLevels holds names registered via RegisterLevelName, lowercased.
+var (
+	customLevels     = make(map[string]slog.Level)
+	customLevelsLock sync.RWMutex
+)
+
+// RegisterLevelName makes name, case-insensitively, resolve to level in
+// ParseLevel and ParseLevelStrict, e.g. RegisterLevelName("audit", slog.Level(12)).
+// Registering a name again replaces its level. The built-in names (trace,
+// debug, info, warn, warning, error) can't be redefined, nor can names that
+// would read as a number or offset; for those an error is returned.
+//
+// Filters parse their levels when set, so register names before setting
+// filters that use them.
+func RegisterLevelName(name string, level slog.Level) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case key == "":
+		return fmt.Errorf("logfilter: empty level name")
+	case strings.ContainsAny(key, "+-") || isInteger(key):
+		return fmt.Errorf("logfilter: level name %q is ambiguous with a numeric level", name)
+	}
+	if _, ok := builtinLevels[key]; ok {
+		return fmt.Errorf("logfilter: level name %q is built in", name)
+	}
+
+	customLevelsLock.Lock()
+	defer customLevelsLock.Unlock()
+	customLevels[key] = level
+	return nil
+}
+
+// ClearLevelNames removes all names registered via RegisterLevelName.
+// Useful for testing.
+func ClearLevelNames() {
+	customLevelsLock.Lock()
+	defer customLevelsLock.Unlock()
+	customLevels = make(map[string]slog.Level)
+}
+
+// ParseLevelStrict converts a level string to slog.Level, or returns an error
+// if it isn't recognized. Matching is case-insensitive and ignores surrounding
+// whitespace. Accepted forms:
+//
+//	"trace", "debug", "info", "warn"/"warning", "error"
+//	a name registered via RegisterLevelName, e.g. "audit"
+//	an integer, e.g. "12" or "-8"
+//	a name plus or minus an offset, as printed by slog.Level, e.g. "INFO+2", "DEBUG-4"
+func ParseLevelStrict(level string) (slog.Level, error) {
+	s := strings.ToLower(strings.TrimSpace(level))
+	if l, ok := lookupLevelName(s); ok {
+		return l, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n), nil
+	}
+
+	// Name with an offset; the sign may not lead, so "-8" was handled above
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		base, ok := lookupLevelName(s[:i])
+		offset, err := strconv.Atoi(s[i:])
+		if ok && err == nil {
+			return base + slog.Level(offset), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownLevel, level)
+}
+
+// LevelToString is the inverse of ParseLevelStrict: it returns the name that
+// parses back to level, "trace", "debug", "info", "warn" or "error", a name
+// registered via RegisterLevelName (the first alphabetically, if several), or
+// slog's name-plus-offset form, e.g. "INFO+2". Unlike a signed integer, the
+// last can't be mistaken for a relative OutputLevel.
+func LevelToString(level slog.Level) string {
+	switch level {
+	case LevelTrace:
+		return "trace"
+	case slog.LevelDebug:
+		return "debug"
+	case slog.LevelInfo:
+		return "info"
+	case slog.LevelWarn:
+		return "warn"
+	case slog.LevelError:
+		return "error"
+	}
+
+	customLevelsLock.RLock()
+	var names []string
+	for name, l := range customLevels {
+		if l == level {
+			names = append(names, name)
+		}
+	}
+	customLevelsLock.RUnlock()
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names[0]
+	}
+	return level.String()
+}
+
+// levelStep is the distance between adjacent built-in levels.
+const levelStep = slog.LevelInfo - slog.LevelDebug
+
+// parseLevelOffset parses a relative OutputLevel: a signed integer such as
+// "+1" or "-2", counting built-in levels.
+func parseLevelOffset(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || (s[0] != '+' && s[0] != '-') {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// offsetLevel moves level by steps built-in levels, e.g. debug +1 is info,
+// clamping the result to the range trace..error. A level already outside
+// that range is never moved back towards it.
+func offsetLevel(level slog.Level, steps int) slog.Level {
+	l := level + slog.Level(steps)*levelStep
+	switch {
+	case steps > 0 && l > slog.LevelError:
+		return max(level, slog.LevelError)
+	case steps < 0 && l < LevelTrace:
+		return min(level, LevelTrace)
+	}
+	return l
+}
+
+// lookupLevelName resolves a lowercased built-in or registered level name.
+func lookupLevelName(name string) (slog.Level, bool) {
+	if l, ok := builtinLevels[name]; ok {
+		return l, true
+	}
+	customLevelsLock.RLock()
+	defer customLevelsLock.RUnlock()
+	l, ok := customLevels[name]
+	return l, ok
+}
+
+// isInteger reports whether s parses as an integer.
+func isInteger(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
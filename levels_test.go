@@ -0,0 +1,299 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevelStrict(t *testing.T) {
+	if err := RegisterLevelName("audit", slog.Level(12)); err != nil {
+		t.Fatal(err)
+	}
+	defer ClearLevelNames()
+
+	tests := []struct {
+		input string
+		want  slog.Level
+	}{
+		{"trace", LevelTrace},
+		{"TRACE", LevelTrace},
+		{"debug", slog.LevelDebug},
+		{" Info ", slog.LevelInfo},
+		{"warning", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"audit", slog.Level(12)},
+		{"AUDIT", slog.Level(12)},
+		{"12", slog.Level(12)},
+		{"-8", slog.Level(-8)},
+		{"+2", slog.Level(2)},
+		{"0", slog.LevelInfo},
+		{"INFO+2", slog.Level(2)},
+		{"DEBUG-4", slog.Level(-8)},
+		{"error+4", slog.Level(12)},
+		{"audit-1", slog.Level(11)},
+		{"trace+0", LevelTrace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevelStrict(tt.input)
+			if err != nil {
+				t.Fatalf("Expected %q to parse, got %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseLevelStrict_Errors(t *testing.T) {
+	for _, input := range []string{"", "verbose", "info+", "info+x", "2+2", "level+2", "debug--4", "1.5"} {
+		if _, err := ParseLevelStrict(input); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		} else if !strings.Contains(err.Error(), "unknown level") {
+			t.Errorf("Expected unknown level error for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestLevelToString(t *testing.T) {
+	defer ClearLevelNames()
+	for name, level := range map[string]slog.Level{"notice": 2, "audit": 12, "alert": 12} {
+		if err := RegisterLevelName(name, level); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{LevelTrace, "trace"},
+		{slog.LevelDebug, "debug"},
+		{slog.LevelInfo, "info"},
+		{slog.LevelWarn, "warn"},
+		{slog.LevelError, "error"},
+		{2, "notice"},
+		{12, "alert"}, // First of several names alphabetically
+		{3, "INFO+3"},
+		{-2, "DEBUG+2"},
+		{-12, "DEBUG-8"},
+	}
+
+	for _, tt := range tests {
+		got := LevelToString(tt.level)
+		if got != tt.want {
+			t.Errorf("Expected LevelToString(%d) = %q, got %q", tt.level, tt.want, got)
+		}
+		if back, err := ParseLevelStrict(got); err != nil || back != tt.level {
+			t.Errorf("Expected %q to parse back to %d, got %d, %v", got, tt.level, back, err)
+		}
+	}
+}
+
+func TestParseLevel_Fallback(t *testing.T) {
+	if got := ParseLevel("verbose"); got != slog.LevelInfo {
+		t.Errorf("Expected unknown level to fall back to info, got %v", got)
+	}
+	if got := ParseLevel("trace"); got != LevelTrace {
+		t.Errorf("Expected trace, got %v", got)
+	}
+	if got := ParseLevel("12"); got != slog.Level(12) {
+		t.Errorf("Expected numeric level 12, got %v", got)
+	}
+}
+
+func TestRegisterLevelName(t *testing.T) {
+	defer ClearLevelNames()
+
+	if _, err := ParseLevelStrict("notice"); err == nil {
+		t.Fatal("Expected notice to be unknown before registration")
+	}
+	if err := RegisterLevelName("Notice", slog.Level(2)); err != nil {
+		t.Fatalf("Expected registration to succeed, got %v", err)
+	}
+	if got, err := ParseLevelStrict("notice"); err != nil || got != slog.Level(2) {
+		t.Errorf("Expected notice = 2, got %v, %v", got, err)
+	}
+
+	// Re-registering replaces the level
+	if err := RegisterLevelName("notice", slog.Level(3)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ParseLevel("notice"); got != slog.Level(3) {
+		t.Errorf("Expected notice = 3 after re-registration, got %v", got)
+	}
+
+	for _, name := range []string{"", "  ", "debug", "WARNING", "42", "loud+1", "-quiet"} {
+		if err := RegisterLevelName(name, slog.Level(5)); err == nil {
+			t.Errorf("Expected registration of %q to fail", name)
+		}
+	}
+	if got := ParseLevel("debug"); got != slog.LevelDebug {
+		t.Errorf("Expected built-in debug unchanged, got %v", got)
+	}
+
+	ClearLevelNames()
+	if _, err := ParseLevelStrict("notice"); err == nil {
+		t.Error("Expected notice to be unknown after ClearLevelNames")
+	}
+}
+
+func TestHandler_TraceAndCustomLevels(t *testing.T) {
+	if err := RegisterLevelName("audit", slog.Level(12)); err != nil {
+		t.Fatal(err)
+	}
+	defer ClearLevelNames()
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_trace", Level: "trace", Enabled: true},
+		{Type: "job_id", Pattern: "job_audit", Level: "audit", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		level slog.Level
+		jobID string
+		emits bool
+	}{
+		{"trace elevated", LevelTrace, "job_trace", true},
+		{"trace without filter", LevelTrace, "job_other", false},
+		{"error below audit", slog.LevelError, "job_audit", false},
+		{"audit passes", slog.Level(12), "job_audit", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.Log(context.Background(), tt.level, "step", "job_id", tt.jobID)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestLogFilter_GetOutputLevel_Offset(t *testing.T) {
+	tests := []struct {
+		name        string
+		outputLevel string
+		original    slog.Level
+		want        slog.Level
+	}{
+		{"debug up one", "+1", slog.LevelDebug, slog.LevelInfo},
+		{"debug up two", "+2", slog.LevelDebug, slog.LevelWarn},
+		{"warn down one", "-1", slog.LevelWarn, slog.LevelInfo},
+		{"error clamped", "+1", slog.LevelError, slog.LevelError},
+		{"warn clamped", "+3", slog.LevelWarn, slog.LevelError},
+		{"trace clamped", "-1", LevelTrace, LevelTrace},
+		{"above error kept", "+1", slog.Level(12), slog.Level(12)},
+		{"zero offset", "+0", slog.LevelInfo, slog.LevelInfo},
+		{"unsigned is absolute", "1", slog.LevelDebug, slog.Level(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := LogFilter{Type: "job_id", Pattern: "x", OutputLevel: tt.outputLevel, Enabled: true}
+			if got := f.GetOutputLevel(tt.original); got != tt.want {
+				t.Errorf("Expected GetOutputLevel(%v) = %v, got %v", tt.original, tt.want, got)
+			}
+			f.prepare()
+			if got := f.cachedOutputLevel(tt.original); got != tt.want {
+				t.Errorf("Expected cachedOutputLevel(%v) = %v, got %v", tt.original, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandler_OutputLevelOffset(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		NewFilter("job_id").Pattern("job_*").Level(slog.LevelDebug).OutputLevelOffset(1).Build(),
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("step", "job_id", "job_1")
+	if !strings.Contains(buf.String(), "level=INFO") {
+		t.Errorf("Expected debug record bumped to INFO, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Error("failed", "job_id", "job_1")
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("Expected error record to stay ERROR, got: %s", buf.String())
+	}
+}
+
+func TestHandler_DefaultOutputLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}), level,
+		WithDefaultOutputLevel(slog.LevelInfo))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "plain", Level: "trace", Enabled: true},
+		{Type: "job_id", Pattern: "explicit", Level: "debug", OutputLevel: "warn", Enabled: true},
+		{Type: "job_id", Pattern: "offset", Level: "debug", OutputLevel: "+2", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		level slog.Level
+		jobID string
+		want  string
+	}{
+		{"debug uses default", slog.LevelDebug, "plain", "level=INFO"},
+		{"trace uses default", LevelTrace, "plain", "level=INFO"},
+		{"error not downgraded", slog.LevelError, "plain", "level=ERROR"},
+		{"explicit overrides", slog.LevelDebug, "explicit", "level=WARN"},
+		{"offset overrides", slog.LevelDebug, "offset", "level=WARN"},
+		{"unmatched suppressed", slog.LevelDebug, "other", ""},
+		{"unmatched info unchanged", slog.LevelInfo, "other", "level=INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.Log(context.Background(), tt.level, "step", "job_id", tt.jobID)
+			if tt.want == "" {
+				if buf.Len() > 0 {
+					t.Errorf("Expected record suppressed, got: %s", buf.String())
+				}
+				return
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("Expected %s, got: %s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_DefaultOutputLevel_Unset(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "plain", Level: "debug", Enabled: true}})
+
+	slog.New(handler).Debug("step", "job_id", "plain")
+	if !strings.Contains(buf.String(), "level=DEBUG") {
+		t.Errorf("Expected level preserved without a default, got: %s", buf.String())
+	}
+}
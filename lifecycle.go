@@ -0,0 +1,60 @@
+package logfilter
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// ErrHandlerClosed is returned by Handle once the handler has been closed.
+var ErrHandlerClosed = errors.New("logfilter: handler closed")
+
+// innerHandler holds the root inner handler. It is swapped as a whole by
+// SetInnerHandler, and its pointer identifies the generation that derived
+// handlers' cached inner handlers were built from.
+type innerHandler struct {
+	handler slog.Handler
+}
+
+// SetInnerHandler replaces the inner handler that records are delegated to.
+// Handlers derived via WithAttrs and WithGroup switch over too, re-applying
+// their attributes and groups to the new handler on first use.
+//
+// Each Handle call resolves the inner handler once, so a record being handled
+// during the swap goes entirely to either the old or the new handler. The old
+// handler is not closed.
+func (h *Handler) SetInnerHandler(inner slog.Handler) {
+	h.innerRef.Store(&innerHandler{handler: inner})
+}
+
+// Close marks the handler, and every handler derived from it, as closed.
+// It waits for in-flight Handle calls to finish, then closes the inner
+// handler if it implements io.Closer. After Close, Enabled reports false and
+// Handle returns ErrHandlerClosed without touching the inner handler.
+// Calling Close more than once is a no-op.
+func (h *Handler) Close() error {
+	if h.closed.Swap(true) {
+		return nil
+	}
+
+	// Acquiring the write lock waits out any Handle holding the read lock.
+	h.lifecycle.Lock()
+	defer h.lifecycle.Unlock()
+
+	if c, ok := h.innerRef.Load().handler.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// IsClosed reports whether Close has been called.
+func (h *Handler) IsClosed() bool {
+	return h.closed.Load()
+}
+
+// resolveInner returns the current root inner handler reference and the
+// inner handler with this handler's scope applied.
+func (h *Handler) resolveInner() (*innerHandler, slog.Handler) {
+	ref := h.innerRef.Load()
+	return ref, h.scoped(&h.innerCache, ref, ref.handler)
+}
@@ -0,0 +1,164 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closingHandler records whether Close was called on it.
+type closingHandler struct {
+	slog.Handler
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *closingHandler) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func TestHandler_Close(t *testing.T) {
+	var buf bytes.Buffer
+	inner := &closingHandler{Handler: slog.NewTextHandler(&buf, nil)}
+	h := NewHandler(inner, newLevel(slog.LevelInfo))
+	derived := h.WithAttrs([]slog.Attr{slog.String("k", "v")})
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if !inner.closed {
+		t.Error("Expected inner handler to be closed")
+	}
+	if !h.IsClosed() {
+		t.Error("Expected IsClosed to report true")
+	}
+	if h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected Enabled to report false after Close")
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, "late", 0)
+	if err := h.Handle(context.Background(), r); !errors.Is(err, ErrHandlerClosed) {
+		t.Errorf("Expected ErrHandlerClosed, got %v", err)
+	}
+	if err := derived.Handle(context.Background(), r); !errors.Is(err, ErrHandlerClosed) {
+		t.Errorf("Expected ErrHandlerClosed from derived handler, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output after Close, got %q", buf.String())
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Expected second Close to be a no-op, got %v", err)
+	}
+}
+
+func TestHandler_SetInnerHandler(t *testing.T) {
+	var oldBuf, newBuf bytes.Buffer
+	h := NewHandler(slog.NewTextHandler(&oldBuf, nil), newLevel(slog.LevelInfo))
+	logger := slog.New(h).With("request", "abc").WithGroup("g")
+
+	logger.Info("before", "n", 1)
+	h.SetInnerHandler(slog.NewTextHandler(&newBuf, nil))
+	logger.Info("after", "n", 2)
+
+	if !strings.Contains(oldBuf.String(), "before") || strings.Contains(oldBuf.String(), "after") {
+		t.Errorf("Expected only the first record in old handler, got %q", oldBuf.String())
+	}
+	out := newBuf.String()
+	if !strings.Contains(out, "after") || strings.Contains(out, "before") {
+		t.Errorf("Expected only the second record in new handler, got %q", out)
+	}
+	if !strings.Contains(out, "request=abc") || !strings.Contains(out, "g.n=2") {
+		t.Errorf("Expected scope to be re-applied to new handler, got %q", out)
+	}
+}
+
+func TestHandler_CloseDuringLog(t *testing.T) {
+	h := NewHandler(slog.NewTextHandler(&syncBuffer{}, nil), newLevel(slog.LevelInfo))
+	logger := slog.New(h).With("k", "v")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				logger.Info("msg", "j", j)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = h.Close()
+	}()
+	wg.Wait()
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)
+	if err := h.Handle(context.Background(), r); !errors.Is(err, ErrHandlerClosed) {
+		t.Errorf("Expected ErrHandlerClosed, got %v", err)
+	}
+}
+
+func TestHandler_SwapDuringLog(t *testing.T) {
+	h := NewHandler(slog.NewTextHandler(&syncBuffer{}, nil), newLevel(slog.LevelInfo))
+	logger := slog.New(h).WithGroup("g")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				logger.Info("msg", "j", j)
+			}
+		}()
+	}
+	bufs := make([]*syncBuffer, 20)
+	for i := range bufs {
+		bufs[i] = &syncBuffer{}
+		h.SetInnerHandler(slog.NewTextHandler(bufs[i], nil))
+	}
+	wg.Wait()
+
+	// Every record must be written whole to exactly one handler.
+	for _, b := range bufs {
+		for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+			if line != "" && !strings.Contains(line, "g.j=") {
+				t.Errorf("Expected complete scoped record, got %q", line)
+			}
+		}
+	}
+}
+
+// newLevel returns a LevelVar set to l.
+func newLevel(l slog.Level) *slog.LevelVar {
+	v := new(slog.LevelVar)
+	v.Set(l)
+	return v
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -48,6 +48,7 @@ import (
 	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -66,9 +67,56 @@ type options struct {
 	level   slog.Level
 	format  string // "json" or "text"
 	output  io.Writer
-	source  bool
+	source  bool         // Print source in output (AddSource)
+	handler slog.Handler // Inner handler for New, replacing format/output
 	workDir string
 	filters []LogFilter
+
+	captureKey    string // Context extractor key for capture-on-error
+	captureWindow int    // Records retained per capture ID
+
+	suppressedSize         int  // Suppressed records retained for FlushSuppressed; 0 disables
+	flushSuppressedOnError bool // Replay suppressed records before each error
+
+	passMalformed bool // FilterStream: write malformed lines through
+
+	rejectMatchAll bool        // Reject unconfirmed catch-all filters
+	baseFilters    []LogFilter // Always-evaluated filters immune to SetFilters/ClearFilters
+	componentKey   string      // Attribute key naming the logger's component
+	clock          Clock       // Time source; nil uses the package-level clock
+
+	samplingLevel slog.Level // Baseline sampling applies at or above this level
+	samplingRate  float64    // Fraction of suppressed records emitted; 0 disables
+
+	routes map[string]slog.Handler // Named destinations for filters with a Route
+
+	redactKeys []string // Attribute key patterns whose values are redacted
+
+	defaultOutputLevel    slog.Level // OutputLevel for elevated records of filters without one
+	hasDefaultOutputLevel bool
+
+	levelFloor    slog.Level // Level below which nothing is emitted
+	hasLevelFloor bool
+
+	annotateKey string // Attribute naming the matched filter; empty disables
+
+	metrics MetricsCollector // Receives per-record decisions; nil for none
+
+	errorHandler func(error) // Called with inner handler errors; nil for none
+
+	stickyKey    string        // Context extractor key for sticky matches; empty disables
+	stickyTTL    time.Duration // How long a match sticks to its context ID
+	stickyMaxIDs int           // Context IDs tracked; 0 for the default
+
+	maxMatchBytes    int // Attribute bytes seen by matching; <= 0 for no limit
+	hasMaxMatchBytes bool
+
+	allowList bool // Emit only records matched by a filter
+
+	sourcePathMode string // How source file paths are formatted
+
+	retryAttempts int           // Attempts per record at the inner handler; <= 1 disables retries
+	retryBackoff  time.Duration // Wait before the first retry, doubled for each further one
 }
 
 // WithLevel sets the initial log level.
@@ -92,8 +140,34 @@ func WithOutput(w io.Writer) Option {
 	}
 }
 
+// WithHandler makes New wrap the given handler, such as a pretty-printing
+// handler, instead of constructing a text or JSON handler. It takes
+// precedence over WithFormat, WithOutput and WithPrintSource, which configure
+// the constructed handler. The handler's own level, if any, must admit the
+// levels filters elevate records to (e.g. debug); the global level is
+// applied by the filter handler.
+func WithHandler(h slog.Handler) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
 // WithSource enables source file:line in log output.
+// It is equivalent to WithPrintSource.
 func WithSource(enabled bool) Option {
+	return WithPrintSource(enabled)
+}
+
+// WithPrintSource controls whether the inner handler adds source file:line to
+// log output (slog.HandlerOptions.AddSource). It only affects formatting:
+// source filters (source:file, source:function and source:package) work
+// whether or not source is printed, because slog.Logger always records the
+// caller's program counter and the filter handler resolves it itself, only
+// when source filters exist.
+//
+// Turning printing off avoids the inner handler's per-record source formatting
+// for applications that need source filtering but not source in the output.
+func WithPrintSource(enabled bool) Option {
 	return func(o *options) {
 		o.source = enabled
 	}
@@ -106,10 +180,71 @@ func WithFilters(filters []LogFilter) Option {
 	}
 }
 
+// WithBaseFilters sets base filters that are always evaluated and cannot be
+// changed at runtime. Base filters are evaluated before the regular filters,
+// so the first matching base filter takes precedence over any filter set via
+// SetFilters or AddFilter. SetFilters, RemoveFilter and ClearFilters never
+// affect them, and GetFilters does not include them (see GetBaseFilters).
+//
+// This lets a platform enforce logging policy, such as dropping health-check
+// logs, while applications manage their own temporary filters.
+func WithBaseFilters(filters []LogFilter) Option {
+	return func(o *options) {
+		o.baseFilters = filters
+	}
+}
+
+// WithRejectMatchAll guards against accidentally installing catch-all filters.
+// When enabled, SetFilters and AddFilter drop any filter whose pattern matches
+// every value (such as "*") unless the filter has Confirmed set, and log a
+// warning for each rejected filter. This is a safety rail for production
+// admin endpoints, where a stray "*" with level "debug" can flood the logs.
+func WithRejectMatchAll(reject bool) Option {
+	return func(o *options) {
+		o.rejectMatchAll = reject
+	}
+}
+
+// WithDefaultOutputLevel sets the level that records elevated by a filter,
+// i.e. let through below the global level, are emitted at when the filter has
+// no OutputLevel of its own; e.g. slog.LevelInfo makes every filter-elevated
+// debug line appear as info. A filter's OutputLevel takes precedence. Records
+// at or above the global level keep their level, so a matched error is never
+// downgraded.
+func WithDefaultOutputLevel(level slog.Level) Option {
+	return func(o *options) {
+		o.defaultOutputLevel = level
+		o.hasDefaultOutputLevel = true
+	}
+}
+
 // New creates a new slog.Logger with filter support.
 // The returned logger uses the global filter handler, so filters can be
 // updated at runtime using SetFilters, AddFilter, etc.
 func New(opts ...Option) *slog.Logger {
+	handler := newHandler(defaultLevel, opts)
+
+	defaultHandlerLock.Lock()
+	defaultHandler = handler
+	defaultHandlerLock.Unlock()
+
+	return slog.New(handler)
+}
+
+// NewWithHandler creates a new slog.Logger with filter support, like New, and
+// returns its handler. Unlike New, it leaves the global handler and level
+// alone: the handler has its own level, and the package-level functions such
+// as SetFilters and SetLevel don't affect it. Use the handler's methods to
+// manage it, which lets an application run several independently filtered
+// loggers.
+func NewWithHandler(opts ...Option) (*slog.Logger, *Handler) {
+	handler := newHandler(new(slog.LevelVar), opts)
+	return slog.New(handler), handler
+}
+
+// newHandler builds the filter handler for New and NewWithHandler, setting
+// level to the configured initial level.
+func newHandler(level *slog.LevelVar, opts []Option) *Handler {
 	o := &options{
 		level:  slog.LevelInfo,
 		format: "json",
@@ -122,17 +257,17 @@ func New(opts ...Option) *slog.Logger {
 		opt(o)
 	}
 
-	defaultLevel.Set(o.level)
+	level.Set(o.level)
 
 	trimPrefix := detectSourcePrefix()
 
 	handlerOpts := &slog.HandlerOptions{
-		Level:     defaultLevel,
+		Level:     level,
 		AddSource: o.source,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == slog.SourceKey {
 				if src, ok := a.Value.Any().(*slog.Source); ok {
-					src.File = trimSourcePath(src.File, trimPrefix, o.workDir)
+					src.File = outputSourcePath(o.sourcePathMode, src, trimPrefix, o.workDir)
 				}
 			}
 			return a
@@ -140,24 +275,16 @@ func New(opts ...Option) *slog.Logger {
 	}
 
 	var inner slog.Handler
-	if o.format == "text" {
+	switch {
+	case o.handler != nil:
+		inner = o.handler
+	case o.format == "text":
 		inner = slog.NewTextHandler(o.output, handlerOpts)
-	} else {
+	default:
 		inner = slog.NewJSONHandler(o.output, handlerOpts)
 	}
 
-	handler := NewHandler(inner, defaultLevel)
-
-	// Apply initial filters if provided
-	if len(o.filters) > 0 {
-		handler.SetFilters(o.filters)
-	}
-
-	defaultHandlerLock.Lock()
-	defaultHandler = handler
-	defaultHandlerLock.Unlock()
-
-	return slog.New(handler)
+	return NewHandler(inner, level, opts...)
 }
 
 // SetLevel changes the global log level at runtime.
@@ -170,6 +297,21 @@ func GetLevel() slog.Level {
 	return defaultLevel.Level()
 }
 
+// SetLevel changes the handler's global log level at runtime: the level
+// records must reach when no filter matches. It does nothing for a handler
+// built with NewHandlerWithLeveler from a Leveler without a Set(slog.Level)
+// method.
+func (h *Handler) SetLevel(level slog.Level) {
+	if lv, ok := h.globalLevel.(interface{ Set(slog.Level) }); ok {
+		lv.Set(level)
+	}
+}
+
+// GetLevel returns the handler's current global log level.
+func (h *Handler) GetLevel() slog.Level {
+	return h.globalLevel.Level()
+}
+
 // SetFilters replaces all filters on the global handler.
 // Filters are applied in order; first match wins.
 func SetFilters(filters []LogFilter) {
@@ -182,7 +324,8 @@ func SetFilters(filters []LogFilter) {
 	}
 }
 
-// GetFilters returns a copy of the current filters.
+// GetFilters returns a copy of the global handler's filters, in the order
+// they were added (see Handler.GetFilters).
 func GetFilters() []LogFilter {
 	defaultHandlerLock.RLock()
 	h := defaultHandler
@@ -194,6 +337,19 @@ func GetFilters() []LogFilter {
 	return nil
 }
 
+// GetActiveFilters returns the global handler's active filters (see
+// Handler.GetActiveFilters).
+func GetActiveFilters() []LogFilter {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.GetActiveFilters()
+	}
+	return nil
+}
+
 // AddFilter adds a filter to the global handler.
 func AddFilter(filter LogFilter) {
 	defaultHandlerLock.RLock()
@@ -227,6 +383,18 @@ func ClearFilters() {
 	}
 }
 
+// GetBaseFilters returns a copy of the global handler's base filters.
+func GetBaseFilters() []LogFilter {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.GetBaseFilters()
+	}
+	return nil
+}
+
 // GetHandler returns the global filter handler.
 // This can be used to wrap with additional handlers or for testing.
 func GetHandler() *Handler {
@@ -2,8 +2,11 @@ package logfilter
 
 import (
 	"bytes"
+	"context"
 	"log/slog"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -64,6 +67,122 @@ func TestNew_WithFilters(t *testing.T) {
 	}
 }
 
+// recordingHandler records every record it handles.
+type recordingHandler struct {
+	mu      *sync.Mutex
+	records *[]slog.Record
+	attrs   []slog.Attr
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{mu: new(sync.Mutex), records: new([]slog.Record)}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(h.attrs...)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &nh
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// messages returns the messages of the recorded records.
+func (h *recordingHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var msgs []string
+	for _, r := range *h.records {
+		msgs = append(msgs, r.Level.String()+" "+r.Message)
+	}
+	return msgs
+}
+
+func TestNew_WithHandler(t *testing.T) {
+	var buf bytes.Buffer
+	rec := newRecordingHandler()
+	logger := New(
+		WithHandler(rec),
+		WithFormat("text"), // Ignored in favor of WithHandler
+		WithOutput(&buf),
+		WithFilters([]LogFilter{
+			{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "warn", Enabled: true},
+			{Type: "service", Pattern: "noisy", Level: "error", Enabled: true},
+		}),
+	)
+
+	logger.Debug("elevated", "job_id", "job_1")
+	logger.Debug("dropped", "job_id", "task_1")
+	logger.Info("suppressed", "service", "noisy")
+	logger.With("service", "api").Info("passed")
+
+	want := []string{"WARN elevated", "INFO passed"}
+	if got := rec.messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected records %v, got %v", want, got)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("Expected nothing written to WithOutput, got: %s", buf.String())
+	}
+}
+
+func TestNewWithHandler_Isolation(t *testing.T) {
+	var global, bufA, bufB bytes.Buffer
+	globalLogger := New(WithOutput(&global), WithFormat("text"), WithLevel(slog.LevelInfo))
+	loggerA, handlerA := NewWithHandler(WithOutput(&bufA), WithFormat("text"), WithLevel(slog.LevelInfo))
+	loggerB, handlerB := NewWithHandler(WithOutput(&bufB), WithFormat("text"), WithLevel(slog.LevelWarn))
+
+	if GetHandler() == handlerA || GetHandler() == handlerB {
+		t.Fatal("Expected NewWithHandler to leave the global handler alone")
+	}
+	if GetLevel() != slog.LevelInfo {
+		t.Errorf("Expected global level INFO, got %v", GetLevel())
+	}
+
+	handlerA.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true}})
+	SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "error", Enabled: true}})
+
+	if got := handlerB.GetFilters(); len(got) != 0 {
+		t.Errorf("Expected handler B to have no filters, got %v", got)
+	}
+	if got := handlerA.GetFilters(); len(got) != 1 || got[0].Level != "debug" {
+		t.Errorf("Expected handler A's filter to survive SetFilters, got %v", got)
+	}
+
+	for _, l := range []*slog.Logger{globalLogger, loggerA, loggerB} {
+		l.Debug("step", "job_id", "job_1")
+		l.Info("ready")
+	}
+
+	if !strings.Contains(bufA.String(), "msg=step") || !strings.Contains(bufA.String(), "msg=ready") {
+		t.Errorf("Expected handler A to emit both records, got: %s", bufA.String())
+	}
+	if bufB.Len() > 0 {
+		t.Errorf("Expected handler B at WARN to emit nothing, got: %s", bufB.String())
+	}
+	if strings.Contains(global.String(), "msg=step") || !strings.Contains(global.String(), "msg=ready") {
+		t.Errorf("Expected global logger to emit only ready, got: %s", global.String())
+	}
+
+	handlerB.SetLevel(slog.LevelInfo)
+	if handlerB.GetLevel() != slog.LevelInfo || handlerA.GetLevel() != slog.LevelInfo || GetLevel() != slog.LevelInfo {
+		t.Errorf("Expected only handler B's level to change")
+	}
+	loggerB.Info("ready")
+	if !strings.Contains(bufB.String(), "msg=ready") {
+		t.Errorf("Expected handler B to emit after SetLevel, got: %s", bufB.String())
+	}
+}
+
 func TestSetLevel(t *testing.T) {
 	_ = New(WithLevel(slog.LevelInfo))
 
@@ -343,3 +462,53 @@ func TestDetectSourcePrefix(t *testing.T) {
 		t.Errorf("Expected prefix to contain module name, got %q", prefix)
 	}
 }
+
+func TestPrintSource_IndependentOfSourceFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		printSource   bool
+		sourceFilters bool
+	}{
+		{"print on, source filters", true, true},
+		{"print on, no source filters", true, false},
+		{"print off, source filters", false, true},
+		{"print off, no source filters", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var filters []LogFilter
+			if tt.sourceFilters {
+				filters = []LogFilter{
+					{Type: SourceFilePrefix, Pattern: "*logfilter_test.go", Level: "debug", Enabled: true},
+				}
+			}
+
+			logger := New(
+				WithLevel(slog.LevelInfo),
+				WithFormat("text"),
+				WithOutput(&buf),
+				WithPrintSource(tt.printSource),
+				WithFilters(filters),
+			)
+
+			logger.Debug("debug record")
+			logger.Info("info record")
+			out := buf.String()
+
+			// Source filters elevate debug regardless of print setting
+			if got := strings.Contains(out, "debug record"); got != tt.sourceFilters {
+				t.Errorf("Expected debug emitted=%v, got output: %s", tt.sourceFilters, out)
+			}
+			if !strings.Contains(out, "info record") {
+				t.Errorf("Expected info record to be emitted, got: %s", out)
+			}
+
+			// Source appears in output only when printing is enabled
+			if got := strings.Contains(out, "source="); got != tt.printSource {
+				t.Errorf("Expected source printed=%v, got output: %s", tt.printSource, out)
+			}
+		})
+	}
+}
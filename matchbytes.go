@@ -0,0 +1,53 @@
+package logfilter
+
+import (
+	"log/slog"
+	"unicode/utf8"
+)
+
+// defaultMaxMatchBytes is the length to which attribute values are clipped
+// for matching unless WithMaxMatchBytes says otherwise.
+const defaultMaxMatchBytes = 4096
+
+// WithMaxMatchBytes bounds the work matching does on large attribute values,
+// such as JSON blobs: filters and suppressions see only the first n bytes of
+// an attribute's string form (cut back to a whole UTF-8 character), so a
+// pattern matching beyond them doesn't match. It doesn't affect output; the
+// record is emitted with its full values. The default is 4096 bytes; n <= 0
+// removes the limit.
+func WithMaxMatchBytes(n int) Option {
+	return func(o *options) {
+		o.maxMatchBytes = n
+		o.hasMaxMatchBytes = true
+	}
+}
+
+// clipMatch returns s cut to at most n bytes, without splitting a UTF-8
+// character. n <= 0 means no limit.
+func clipMatch(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// matchString returns val as a string for matching, clipped to the handler's
+// limit (see WithMaxMatchBytes).
+func (h *Handler) matchString(val slog.Value) string {
+	return clipMatch(attrValueToString(val), h.maxMatchBytes)
+}
+
+// matchElements returns the elements of a slice val as strings for matching,
+// each clipped as by matchString, and whether val is a slice.
+func (h *Handler) matchElements(val slog.Value) ([]string, bool) {
+	elems, ok := attrValueElems(val)
+	if ok && h.maxMatchBytes > 0 {
+		for i, e := range elems {
+			elems[i] = clipMatch(e, h.maxMatchBytes)
+		}
+	}
+	return elems, ok
+}
@@ -0,0 +1,102 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_MaxMatchBytes(t *testing.T) {
+	// A large payload with a marker well past the default limit.
+	blob := strings.Repeat("x", 64*1024) + "needle"
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		value string
+		want  bool
+	}{
+		{"default limit hides late match", nil, blob, false},
+		{"default limit keeps early match", nil, "needle" + blob, true},
+		{"raised limit", []Option{WithMaxMatchBytes(128 * 1024)}, blob, true},
+		{"lowered limit", []Option{WithMaxMatchBytes(4)}, "needle", false},
+		{"no limit", []Option{WithMaxMatchBytes(0)}, blob, true},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		level := new(slog.LevelVar)
+		level.Set(slog.LevelInfo)
+
+		inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		handler := NewHandler(inner, level, tt.opts...)
+		handler.SetFilters([]LogFilter{
+			{Type: "payload", Pattern: "*needle*", Level: "debug", Enabled: true},
+		})
+
+		slog.New(handler).Debug("received", "payload", tt.value)
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got %v", tt.name, tt.want, got)
+		}
+		if tt.want && !strings.Contains(buf.String(), tt.value) {
+			t.Errorf("%s: expected the full value in the output", tt.name)
+		}
+	}
+}
+
+func TestHandler_MaxMatchBytes_Bounded(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithMaxMatchBytes(16))
+	handler.SetFilters([]LogFilter{
+		{Type: "payload", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "*_blob", Pattern: "*", Level: "debug", Enabled: true},
+		{Type: "labels.body", Pattern: "*", Level: "debug", Enabled: true},
+	})
+
+	big := strings.Repeat("y", 1024)
+	tests := []struct {
+		name string
+		attr slog.Attr
+	}{
+		{"attribute", slog.String("payload", big)},
+		{"slice element", slog.Any("payload", []string{big})},
+		{"wildcard key", slog.String("req_blob", big)},
+		{"key path", slog.Any("labels", map[string]string{"body": big})},
+	}
+
+	for _, tt := range tests {
+		r := slog.NewRecord(handler.now(), slog.LevelDebug, "msg", 0)
+		r.AddAttrs(tt.attr)
+		d := handler.Explain(context.Background(), r)
+		if !d.Emit {
+			t.Errorf("%s: expected a match, got %+v", tt.name, d)
+			continue
+		}
+		if len(d.Value) != 16 {
+			t.Errorf("%s: expected the matched value clipped to 16 bytes, got %d", tt.name, len(d.Value))
+		}
+	}
+}
+
+func TestClipMatch(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated", 5, "trunc"},
+		{"unlimited", 0, "unlimited"},
+		{"héllo", 2, "h"}, // Doesn't split é
+		{"héllo", 3, "hé"},
+	}
+
+	for _, tt := range tests {
+		if got := clipMatch(tt.s, tt.n); got != tt.want {
+			t.Errorf("clipMatch(%q, %d): expected %q, got %q", tt.s, tt.n, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package logfilter
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// matchKind identifies how a compiled pattern is evaluated.
+type matchKind int
+
+const (
+	matchNone     matchKind = iota // Empty pattern, never matches
+	matchAll                       // "*" or "**", matches everything
+	matchExact                     // "value"
+	matchPrefix                    // "prefix*"
+	matchSuffix                    // "*suffix"
+	matchContains                  // "*contains*"
+	matchWildcard                  // "a*b", "*a*b*": inner wildcards
+	matchGlob                      // "job_[0-9]*", "v?": character classes or "?"
+	matchRegex                     // Regular expression (LogFilter.Regex)
+	matchNumeric                   // Numeric comparison (LogFilter.Numeric)
+)
+
+// Matcher is a compiled filter pattern. It holds the result of parsing the
+// pattern once so that repeated matches do no parsing, and can be used on its
+// own (e.g. when scanning archived logs) independent of slog.
+//
+// The zero Matcher matches nothing.
+type Matcher struct {
+	kind    matchKind
+	literal string         // Pattern with leading/trailing wildcards stripped
+	re      *regexp.Regexp // Set for matchRegex
+	num     *numericRange  // Set for matchNumeric
+
+	// For matchWildcard: whether the first/last segment is anchored to the
+	// start/end of the value (the pattern doesn't begin/end with "*").
+	anchorStart, anchorEnd bool
+}
+
+// NewMatcher compiles a glob-style pattern. See LogFilter.Pattern for the syntax.
+func NewMatcher(pattern string) Matcher {
+	if pattern == "" {
+		return Matcher{kind: matchNone}
+	}
+	if strings.ContainsAny(pattern, "?[") && validGlob(pattern) {
+		return Matcher{kind: matchGlob, literal: pattern}
+	}
+
+	startsWithWildcard := strings.HasPrefix(pattern, "*")
+	endsWithWildcard := strings.HasSuffix(pattern, "*")
+	core := strings.Trim(pattern, "*")
+
+	switch {
+	case core == "":
+		return Matcher{kind: matchAll} // Pattern is only wildcards, e.g. "*" or "**"
+	case strings.Contains(core, "*"):
+		// Inner wildcards, e.g. "job_*_stage_*"; the fast paths below cover the rest
+		return Matcher{kind: matchWildcard, literal: core, anchorStart: !startsWithWildcard, anchorEnd: !endsWithWildcard}
+	case startsWithWildcard && endsWithWildcard:
+		return Matcher{kind: matchContains, literal: core}
+	case endsWithWildcard:
+		return Matcher{kind: matchPrefix, literal: core}
+	case startsWithWildcard:
+		return Matcher{kind: matchSuffix, literal: core}
+	default:
+		return Matcher{kind: matchExact, literal: pattern}
+	}
+}
+
+// NewRegexMatcher compiles a Go regular expression that must match the whole
+// value, as if wrapped in ^(?:...)$. Use ".*" at either end for a partial match.
+func NewRegexMatcher(pattern string) (Matcher, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return Matcher{kind: matchNone}, err
+	}
+	return Matcher{kind: matchRegex, re: re}, nil
+}
+
+// regexMatchAll reports whether the regular expression pattern trivially
+// matches every value: once simplified, it is a star over any character, such
+// as ".*" or "[\s\S]*", possibly captured, anchored, repeated or one branch
+// of an alternation. Other expressions that happen to match everything aren't
+// detected.
+func regexMatchAll(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return regexTotal(re.Simplify())
+}
+
+// regexTotal reports whether re, a simplified regular expression, is one
+// regexMatchAll considers to match every value.
+func regexTotal(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar:
+		sub := re.Sub[0]
+		return regexAnyChar(sub) || regexTotal(sub)
+	case syntax.OpCapture:
+		return regexTotal(re.Sub[0])
+	case syntax.OpAlternate:
+		return slices.ContainsFunc(re.Sub, regexTotal)
+	case syntax.OpConcat:
+		total := false
+		for _, sub := range re.Sub {
+			switch {
+			case regexTotal(sub):
+				total = true
+			case sub.Op != syntax.OpBeginText && sub.Op != syntax.OpEndText &&
+				sub.Op != syntax.OpBeginLine && sub.Op != syntax.OpEndLine &&
+				sub.Op != syntax.OpEmptyMatch:
+				return false
+			}
+		}
+		return total
+	default:
+		return false
+	}
+}
+
+// regexAnyChar reports whether re matches any one character, as "." and
+// "[\s\S]" do.
+func regexAnyChar(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpCharClass:
+		return len(re.Rune) == 2 && re.Rune[0] == 0 && re.Rune[1] == unicode.MaxRune
+	default:
+		return false
+	}
+}
+
+// Matcher returns the compiled matcher for the filter's pattern. For a filter
+// with Numeric or Regex set and an invalid pattern it returns the zero
+// Matcher, which matches nothing.
+func (f *LogFilter) Matcher() Matcher {
+	m, _ := f.compile()
+	return m
+}
+
+// compile compiles the filter's pattern according to its mode. Numeric takes
+// precedence over Regex. Glob patterns always compile.
+func (f *LogFilter) compile() (Matcher, error) {
+	switch {
+	case f.Numeric:
+		return NewNumericMatcher(f.Pattern)
+	case f.Regex:
+		return NewRegexMatcher(f.Pattern)
+	default:
+		return NewMatcher(f.Pattern), nil
+	}
+}
+
+// Match reports whether value matches the compiled pattern.
+func (m Matcher) Match(value string) bool {
+	switch m.kind {
+	case matchAll:
+		return true
+	case matchExact:
+		return value == m.literal
+	case matchPrefix:
+		return strings.HasPrefix(value, m.literal)
+	case matchSuffix:
+		return strings.HasSuffix(value, m.literal)
+	case matchContains:
+		return strings.Contains(value, m.literal)
+	case matchWildcard:
+		return matchSegments(m.literal, value, m.anchorStart, m.anchorEnd)
+	case matchGlob:
+		return matchGlobPattern(m.literal, value)
+	case matchRegex:
+		return m.re.MatchString(value)
+	case matchNumeric:
+		return m.num.match(value)
+	default:
+		return false
+	}
+}
+
+// matchSegments reports whether the "*"-separated segments of pattern, which
+// has at least one inner "*" and none at either end, appear in order in value.
+// The first and last segments are anchored to the value's start and end as
+// requested. It does not allocate.
+func matchSegments(pattern, value string, anchorStart, anchorEnd bool) bool {
+	first, rest, _ := strings.Cut(pattern, "*")
+	last := rest[strings.LastIndexByte(rest, '*')+1:]
+	middle := rest[:len(rest)-len(last)]
+
+	// Consume the first segment, leftmost occurrence if unanchored.
+	if anchorStart {
+		if !strings.HasPrefix(value, first) {
+			return false
+		}
+		value = value[len(first):]
+	} else {
+		i := strings.Index(value, first)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(first):]
+	}
+
+	// Consume the last segment from what remains, rightmost if unanchored.
+	if anchorEnd {
+		if !strings.HasSuffix(value, last) {
+			return false
+		}
+		value = value[:len(value)-len(last)]
+	} else {
+		i := strings.LastIndex(value, last)
+		if i < 0 {
+			return false
+		}
+		value = value[:i]
+	}
+
+	// The middle segments must appear in order in between.
+	for middle != "" {
+		var seg string
+		seg, middle, _ = strings.Cut(middle, "*")
+		if seg == "" {
+			continue // Adjacent wildcards
+		}
+		i := strings.Index(value, seg)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(seg):]
+	}
+	return true
+}
+
+// validGlob reports whether every "[" in pattern opens a well-formed character
+// class and no "\\" is left dangling at the end. Patterns that aren't valid
+// globs are matched with "*" as the only wildcard, so "[" and "?" are literal.
+func validGlob(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i++; i == len(pattern) {
+				return false
+			}
+		case '[':
+			n := classLen(pattern[i:])
+			if n < 0 {
+				return false
+			}
+			i += n - 1
+		}
+	}
+	return true
+}
+
+// matchGlobPattern reports whether value matches the whole of pattern, where
+// "*" matches any run of characters (including none and "/"), "?" matches
+// one character, "[...]" matches one character from a class, and "\\"
+// escapes the next character. pattern must satisfy validGlob. Backtracking is
+// limited to the most recent "*", so matching takes O(len(pattern)*len(value))
+// time at worst and does not allocate.
+func matchGlobPattern(pattern, value string) bool {
+	px, vx := 0, 0
+	starPx, starVx := -1, 0 // Position after the last "*", and where it resumes
+	for px < len(pattern) || vx < len(value) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				px++
+				starPx, starVx = px, vx
+				continue
+			case '?':
+				if vx < len(value) {
+					_, n := utf8.DecodeRuneInString(value[vx:])
+					px, vx = px+1, vx+n
+					continue
+				}
+			case '[':
+				if vx < len(value) {
+					r, n := utf8.DecodeRuneInString(value[vx:])
+					class := pattern[px : px+classLen(pattern[px:])]
+					if matchClass(class, r) {
+						px, vx = px+len(class), vx+n
+						continue
+					}
+				}
+			default:
+				lit := pattern[px:]
+				if c == '\\' {
+					lit = lit[1:]
+				}
+				_, n := utf8.DecodeRuneInString(lit)
+				if strings.HasPrefix(value[vx:], lit[:n]) {
+					px, vx = px+len(pattern[px:])-len(lit)+n, vx+n
+					continue
+				}
+			}
+		}
+		// Mismatch: let the last "*" absorb one more character and retry.
+		if starPx < 0 || starVx >= len(value) {
+			return false
+		}
+		_, n := utf8.DecodeRuneInString(value[starVx:])
+		starVx += n
+		px, vx = starPx, starVx
+	}
+	return true
+}
+
+// classLen returns the length of the character class at the start of s,
+// including its brackets, or -1 if it isn't closed or is empty. A class is
+// "[" optionally followed by "!" or "^" to negate it, then characters and
+// ranges such as "a-z". A "]" first in the class is literal, and "\\"
+// escapes the next character.
+func classLen(s string) int {
+	i := 1
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		i++
+	}
+	start := i
+	for i < len(s) {
+		switch {
+		case s[i] == ']' && i > start:
+			return i + 1
+		case s[i] == '\\':
+			i += 2
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+// matchClass reports whether r matches class, a character class accepted by
+// classLen.
+func matchClass(class string, r rune) bool {
+	class = class[1 : len(class)-1]
+	negate := class[0] == '!' || class[0] == '^'
+	if negate {
+		class = class[1:]
+	}
+	for class != "" {
+		var lo, hi rune
+		lo, class = classChar(class)
+		hi = lo
+		if len(class) > 1 && class[0] == '-' {
+			hi, class = classChar(class[1:])
+		}
+		if lo <= r && r <= hi {
+			return !negate
+		}
+	}
+	return negate
+}
+
+// classChar decodes the possibly escaped character at the start of a class
+// body and returns it with the rest of the body.
+func classChar(s string) (rune, string) {
+	if s[0] == '\\' && len(s) > 1 {
+		s = s[1:]
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return r, s[n:]
+}
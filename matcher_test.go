@@ -0,0 +1,225 @@
+package logfilter
+
+import "testing"
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"empty pattern", "", "", false},
+		{"match all", "*", "anything", true},
+		{"match all empty value", "**", "", true},
+		{"exact", "job_1", "job_1", true},
+		{"exact no match", "job_1", "job_2", false},
+		{"prefix", "job_*", "job_1", true},
+		{"prefix no match", "job_*", "task_1", false},
+		{"suffix", "*_prod", "db_prod", true},
+		{"suffix no match", "*_prod", "db_dev", false},
+		{"contains", "*err*", "an error", true},
+		{"contains no match", "*err*", "fine", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(tt.pattern)
+			if got := m.Match(tt.value); got != tt.want {
+				t.Errorf("NewMatcher(%q).Match(%q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_ZeroValue(t *testing.T) {
+	var m Matcher
+	if m.Match("") || m.Match("anything") {
+		t.Error("Expected zero Matcher to match nothing")
+	}
+}
+
+func TestLogFilter_Matcher(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "job_*"}
+	m := f.Matcher()
+
+	for _, v := range []string{"job_1", "job_abc"} {
+		if !m.Match(v) {
+			t.Errorf("Expected matcher to match %q", v)
+		}
+		if m.Match(v) != f.Matches(v) {
+			t.Errorf("Expected Matcher and Matches to agree for %q", v)
+		}
+	}
+	if m.Match("task_1") {
+		t.Error("Expected matcher not to match task_1")
+	}
+}
+
+func TestLogFilter_PrepareCachesMatcher(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "*abc*"}
+	f.prepare()
+
+	if f.matcher != NewMatcher("*abc*") {
+		t.Errorf("Expected prepare to cache compiled matcher, got %+v", f.matcher)
+	}
+}
+
+func TestRegexMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"full match", "job_[0-9a-f]{8}", "job_0a1b2c3d", true},
+		{"too short", "job_[0-9a-f]{8}", "job_0a1b", false},
+		{"anchored at end", "job_[0-9a-f]{8}", "job_0a1b2c3d_retry", false},
+		{"anchored at start", "job_[0-9a-f]{8}", "old_job_0a1b2c3d", false},
+		{"explicit partial", ".*timeout.*", "read timeout after 5s", true},
+		{"alternation is grouped", "a|b", "ab", false},
+		{"glob characters are literal regex", "job_*", "job_____", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewRegexMatcher(tt.pattern)
+			if err != nil {
+				t.Fatalf("NewRegexMatcher(%q) returned error: %v", tt.pattern, err)
+			}
+			if got := m.Match(tt.value); got != tt.want {
+				t.Errorf("NewRegexMatcher(%q).Match(%q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexMatcher_Invalid(t *testing.T) {
+	m, err := NewRegexMatcher("job_[")
+	if err == nil {
+		t.Fatal("Expected error for invalid regex")
+	}
+	if m.Match("job_[") {
+		t.Error("Expected invalid regex matcher to match nothing")
+	}
+
+	f := LogFilter{Pattern: "job_[", Regex: true}
+	if f.Matches("job_[") {
+		t.Error("Expected filter with invalid regex never to match")
+	}
+}
+
+func TestMatcher_MultiWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"job_*_stage_*", "job_1_stage_2", true},
+		{"job_*_stage_*", "job_1_stage_", true},
+		{"job_*_stage_*", "job_1_step_2", false},
+		{"job_*_stage_*", "xjob_1_stage_2", false},
+		{"user_*_admin", "user_42_admin", true},
+		{"user_*_admin", "user_42_admin_x", false},
+		{"user_*_admin", "user__admin", true},
+		{"user_*_admin", "user_admin", false}, // Segments may not overlap
+		{"*a*b*", "xxaxxbxx", true},
+		{"*a*b*", "xxbxxaxx", false},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{"a*b*c", "abbbc", true},
+		{"a**b", "ab", true},
+		{"a**b", "axyzb", true},
+		{"**a**b**", "zazbz", true},
+		{"a*a", "a", false},
+		{"a*a", "aa", true},
+		{"***", "anything", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.value, func(t *testing.T) {
+			if got := NewMatcher(tt.pattern).Match(tt.value); got != tt.want {
+				t.Errorf("NewMatcher(%q).Match(%q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcher_FastPaths(t *testing.T) {
+	tests := []struct {
+		pattern string
+		kind    matchKind
+	}{
+		{"job_1", matchExact},
+		{"job_*", matchPrefix},
+		{"*_prod", matchSuffix},
+		{"*err*", matchContains},
+		{"**err**", matchContains},
+		{"job_*_x", matchWildcard},
+		{"job_[0-9]*", matchGlob},
+		{"v?", matchGlob},
+		{"[INFO", matchExact}, // Unclosed class: "[" is literal
+	}
+
+	for _, tt := range tests {
+		if got := NewMatcher(tt.pattern).kind; got != tt.kind {
+			t.Errorf("NewMatcher(%q).kind = %v, want %v", tt.pattern, got, tt.kind)
+		}
+	}
+}
+
+func TestMatcher_Glob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"class", "job_[abc]", "job_b", true},
+		{"class no match", "job_[abc]", "job_d", false},
+		{"class one char", "job_[abc]", "job_ab", false},
+		{"range", "job_[0-9]*", "job_42", true},
+		{"range no match", "job_[0-9]*", "job_x42", false},
+		{"range needs char", "job_[0-9]*", "job_", false},
+		{"multiple ranges", "[a-zA-Z][0-9]", "Q7", true},
+		{"negated bang", "job_[!0-9]", "job_x", true},
+		{"negated bang no match", "job_[!0-9]", "job_5", false},
+		{"negated caret", "[^a]", "b", true},
+		{"literal bracket first", "[]]", "]", true},
+		{"literal dash last", "[a-]", "-", true},
+		{"escaped in class", `[\]]`, "]", true},
+		{"question", "v?", "v1", true},
+		{"question needs char", "v?", "v", false},
+		{"question one char", "v?", "v12", false},
+		{"question multibyte", "caf?", "café", true},
+		{"question and star", "?*_prod", "x_prod", true},
+		{"question and star no match", "?*_prod", "_prod", false},
+		{"star crosses slash", "internal/[a-z]*/handler.go", "internal/svc/api/handler.go", true},
+		{"anchored end", "job_[0-9]", "job_1x", false},
+		{"backtracking", "*a[bc]?", "xaxabz", true},
+		{"escaped question", `what\?`, "what?", true},
+		{"escaped question literal", `what\?`, "whats", false},
+		{"unclosed class is literal", "[INFO", "[INFO", true},
+		{"unclosed class with star", "[INFO*", "[INFO] ready", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(tt.pattern)
+			if got := m.Match(tt.value); got != tt.want {
+				t.Errorf("NewMatcher(%q).Match(%q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPattern_Glob(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "job_[0-9]*", Level: "debug", Enabled: true}
+	f.prepare()
+	if !f.Matcher().Match("job_123") || f.Matcher().Match("job_abc") {
+		t.Error("Expected filter pattern job_[0-9]* to match job_123 only")
+	}
+	if !matchPattern("v?.?", "v1.2") {
+		t.Error("Expected matchPattern to support ?")
+	}
+}
@@ -0,0 +1,173 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHandler_MaxHits(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", MaxHits: 100, Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	for i := 0; i < 100; i++ {
+		logger.Debug("step", "job_id", "job_42")
+	}
+	if n := strings.Count(buf.String(), "msg=step"); n != 100 {
+		t.Fatalf("Expected the first 100 matching records to be emitted, got %d", n)
+	}
+
+	buf.Reset()
+	logger.Debug("step", "job_id", "job_42")
+	if buf.Len() > 0 {
+		t.Errorf("Expected the 101st matching record to be suppressed, got: %s", buf.String())
+	}
+
+	// Records above the global level still pass
+	logger.Info("done", "job_id", "job_42")
+	if !strings.Contains(buf.String(), "msg=done") {
+		t.Errorf("Expected INFO record to pass the global level, got: %s", buf.String())
+	}
+
+	filters := handler.GetFilters()
+	if !filters[0].IsExhausted() || filters[0].IsActive() {
+		t.Errorf("Expected exhausted filter to be inactive")
+	}
+
+	// Passing the filter back keeps its count
+	handler.SetFilters(filters)
+	buf.Reset()
+	logger.Debug("step", "job_id", "job_42")
+	if buf.Len() > 0 {
+		t.Errorf("Expected re-set filter to stay exhausted, got: %s", buf.String())
+	}
+
+	// A new filter starts from zero
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", MaxHits: 100, Enabled: true},
+	})
+	logger.Debug("step", "job_id", "job_42")
+	if buf.Len() == 0 {
+		t.Errorf("Expected a new filter to start counting from zero")
+	}
+}
+
+func TestHandler_MaxHits_FallsThrough(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "warn", MaxHits: 1, Enabled: true},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("first", "job_id", "job_1")
+	logger.Debug("second", "job_id", "job_1")
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN msg=first") || !strings.Contains(out, "level=DEBUG msg=second") {
+		t.Errorf("Expected the next filter to match once the first is exhausted, got: %s", out)
+	}
+}
+
+func TestHandler_MaxHits_Concurrent(t *testing.T) {
+	var emitted atomic.Int64
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	counter := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(countingHandler{counter, &emitted}, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", MaxHits: 50, Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Debug("step", "job_id", "job_42")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := emitted.Load(); n != 50 {
+		t.Errorf("Expected exactly 50 records emitted, got %d", n)
+	}
+}
+
+func TestValidate_NegativeMaxHits(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "job_*", MaxHits: -1}
+	if err := f.Validate(); !errors.Is(err, ErrNegativeLimit) {
+		t.Errorf("Expected ErrNegativeLimit, got %v", err)
+	}
+}
+
+// countingHandler counts the records it handles.
+type countingHandler struct {
+	slog.Handler
+	n *atomic.Int64
+}
+
+func (h countingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.n.Add(1)
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestHandler_MaxHits_ModifiedCopy(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "a", Level: "debug", MaxHits: 2, Enabled: true},
+	})
+	logger := slog.New(handler)
+	logger.Debug("a1", "job_id", "a")
+	logger.Debug("a2", "job_id", "a")
+
+	// A copy changed to match something else is a different filter
+	filters := handler.GetFilters()
+	modified := filters[0]
+	modified.Pattern = "b"
+	handler.SetFilters([]LogFilter{filters[0], modified, modified})
+
+	buf.Reset()
+	logger.Debug("a3", "job_id", "a")
+	logger.Debug("b1", "job_id", "b")
+	if strings.Contains(buf.String(), "a3") {
+		t.Errorf("Expected the original filter to stay exhausted, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "b1") {
+		t.Errorf("Expected the modified copy to start from zero, got: %s", buf.String())
+	}
+
+	stats := handler.Stats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 stats, got %+v", stats)
+	}
+	for i, want := range []uint64{2, 1, 0} {
+		if stats[i].Matches != want {
+			t.Errorf("Expected filter %d (pattern %s) to have %d matches, got %d", i, stats[i].Pattern, want, stats[i].Matches)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package logfilter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// statLevelNames are the label values for statLevels, in the same order.
+var statLevelNames = [len(statLevels)]string{"debug", "info", "warn", "error"}
+
+// WriteMetrics writes the handler's metrics to w in the Prometheus text
+// exposition format, for serving on a /metrics endpoint without a client
+// library:
+//
+//	logfilter_records_total{level,outcome}      records emitted/suppressed by original level
+//	logfilter_filter_matches_total{id,type,set} records decided by each filter
+//	logfilter_active_filters                    enabled, unexpired filters (including base filters)
+//	logfilter_source_extraction_active          1 if source filters make Handle resolve call sites
+//
+// Filters are labelled by FilterID, which is stable across restarts even for
+// filters without an explicit ID, and never by pattern, so label cardinality is
+// bounded by the number of filters. The set label is "base" for base filters,
+// "filters" for the regular filters and "group:<name>" for filter groups.
+func (h *Handler) WriteMetrics(w io.Writer) error {
+	h.filtersLock.RLock()
+	sets := h.filterSets()
+	hasSourceFilters := h.hasSourceFilters
+	h.filtersLock.RUnlock()
+
+	var buf bytes.Buffer
+
+	buf.WriteString("# HELP logfilter_records_total Records handled, by original level and outcome.\n")
+	buf.WriteString("# TYPE logfilter_records_total counter\n")
+	for i, name := range statLevelNames {
+		fmt.Fprintf(&buf, "logfilter_records_total{level=%q,outcome=\"emitted\"} %d\n", name, h.levelCounters[i].emitted.Load())
+		fmt.Fprintf(&buf, "logfilter_records_total{level=%q,outcome=\"suppressed\"} %d\n", name, h.levelCounters[i].suppressed.Load())
+	}
+
+	buf.WriteString("# HELP logfilter_filter_matches_total Records decided by each filter.\n")
+	buf.WriteString("# TYPE logfilter_filter_matches_total counter\n")
+	active := 0
+	now := h.now()
+	for _, set := range sets {
+		for i := range set.filters {
+			f := &set.filters[i]
+			if f.IsActiveAt(now) {
+				active++
+			}
+			fmt.Fprintf(&buf, "logfilter_filter_matches_total{id=\"%s\",type=\"%s\",set=\"%s\"} %d\n",
+				escapeLabelValue(f.FilterID()), escapeLabelValue(f.Type), escapeLabelValue(set.name), f.matchCount())
+		}
+	}
+
+	buf.WriteString("# HELP logfilter_active_filters Enabled, unexpired filters.\n")
+	buf.WriteString("# TYPE logfilter_active_filters gauge\n")
+	fmt.Fprintf(&buf, "logfilter_active_filters %d\n", active)
+
+	buf.WriteString("# HELP logfilter_source_extraction_active Whether source filters require call-site resolution.\n")
+	buf.WriteString("# TYPE logfilter_source_extraction_active gauge\n")
+	sourceActive := 0
+	if hasSourceFilters {
+		sourceActive = 1
+	}
+	fmt.Fprintf(&buf, "logfilter_source_extraction_active %d\n", sourceActive)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// filterSet is a named filter list reported by WriteMetrics and Stats.
+type filterSet struct {
+	name    string
+	filters []LogFilter
+}
+
+// filterSets returns the handler's filter lists in evaluation order: base
+// filters, regular filters, then each group. Must be called with filtersLock held.
+func (h *Handler) filterSets() []filterSet {
+	sets := []filterSet{{"base", h.baseFilters}, {"filters", h.filters}}
+	for _, g := range h.groups {
+		sets = append(sets, filterSet{"group:" + g.name, g.filters})
+	}
+	return sets
+}
+
+// labelEscaper escapes a Prometheus label value.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes s for use inside a quoted Prometheus label value.
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
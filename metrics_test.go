@@ -0,0 +1,98 @@
+package logfilter
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// metricLine matches a Prometheus text-format sample line.
+var metricLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*",?)*\})? (\d+)$`)
+
+// parseMetrics parses Prometheus text output into series (name plus labels) -> value,
+// failing the test on any malformed line.
+func parseMetrics(t *testing.T, out string) map[string]uint64 {
+	t.Helper()
+	series := make(map[string]uint64)
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "# HELP ") || strings.HasPrefix(line, "# TYPE ") {
+			continue
+		}
+		m := metricLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("Malformed metric line: %q", line)
+		}
+		v, _ := strconv.ParseUint(m[3], 10, 64)
+		series[m[1]+m[2]] = v
+	}
+	return series
+}
+
+func TestHandler_WriteMetrics(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	sourceFilter := LogFilter{Type: SourceFilePrefix, Pattern: "vendor/*", Level: "error", Enabled: true}
+	userFilter := LogFilter{Type: "user_id", Pattern: "u1", Level: "debug", Enabled: false}
+	baseFilter := LogFilter{Type: "noisy", Pattern: "yes", Level: "error", Enabled: true}
+
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level,
+		WithBaseFilters([]LogFilter{baseFilter}))
+	handler.SetFilters([]LogFilter{
+		{ID: "job-debug", Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		sourceFilter,
+		userFilter,
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("a", "job_id", "job_1")
+	logger.Debug("b", "job_id", "job_2")
+	logger.Debug("c")
+	logger.Info("d")
+	logger.Warn("e", "noisy", "yes")
+
+	var buf bytes.Buffer
+	if err := handler.WriteMetrics(&buf); err != nil {
+		t.Fatalf("WriteMetrics returned error: %v", err)
+	}
+	got := parseMetrics(t, buf.String())
+
+	want := map[string]uint64{
+		`logfilter_records_total{level="debug",outcome="emitted"}`:                                                   2,
+		`logfilter_records_total{level="debug",outcome="suppressed"}`:                                                1,
+		`logfilter_records_total{level="info",outcome="emitted"}`:                                                    1,
+		`logfilter_records_total{level="warn",outcome="suppressed"}`:                                                 1,
+		`logfilter_filter_matches_total{id="job-debug",type="job_id",set="filters"}`:                                 2,
+		`logfilter_filter_matches_total{id="` + DeriveFilterID(sourceFilter) + `",type="source:file",set="filters"}`: 0,
+		`logfilter_filter_matches_total{id="` + DeriveFilterID(userFilter) + `",type="user_id",set="filters"}`:       0,
+		`logfilter_filter_matches_total{id="` + DeriveFilterID(baseFilter) + `",type="noisy",set="base"}`:            1,
+		`logfilter_active_filters`:           3,
+		`logfilter_source_extraction_active`: 1,
+	}
+	for series, v := range want {
+		if got[series] != v {
+			t.Errorf("Expected %s = %d, got %d (present=%v)", series, v, got[series], hasKey(got, series))
+		}
+	}
+	if !strings.Contains(buf.String(), "# TYPE logfilter_records_total counter") {
+		t.Errorf("Expected TYPE line for records_total, got:\n%s", buf.String())
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	if got := escapeLabelValue("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("Expected escaped label value, got %q", got)
+	}
+}
+
+func hasKey(m map[string]uint64, k string) bool {
+	_, ok := m[k]
+	return ok
+}
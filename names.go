@@ -0,0 +1,170 @@
+package logfilter
+
+// GetFilterByName returns a copy of the first filter named name, and whether
+// one was found. Base filters and filter groups are not searched.
+func (h *Handler) GetFilterByName(name string) (LogFilter, bool) {
+	if name == "" {
+		return LogFilter{}, false
+	}
+
+	h.filtersLock.RLock()
+	defer h.filtersLock.RUnlock()
+
+	for _, f := range h.filters {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return LogFilter{}, false
+}
+
+// RemoveFilterByName removes the first filter named name, reporting whether
+// one was found. Unlike RemoveFilter, which removes every filter sharing a
+// type and pattern, it removes exactly one filter.
+func (h *Handler) RemoveFilterByName(name string) bool {
+	if name == "" {
+		return false
+	}
+	return h.removeFirstFilter(func(f *LogFilter) bool { return f.Name == name })
+}
+
+// UpsertFilter replaces the first filter with the same Name as filter, in
+// place, or adds filter if there is none, in a single update, so concurrent
+// records never see neither. A filter without a Name is always added. The
+// replacement starts with fresh runtime state (match counts, MaxHits), even
+// if it is a copy of a filter from GetFilters or GetFilterByName, and is
+// subject to the same checks as AddFilter.
+func (h *Handler) UpsertFilter(filter LogFilter) {
+	h.putFilterByName(filter.Name, filter, true)
+}
+
+// ReplaceFilter replaces the first filter named name with filter, in place,
+// reporting whether one was found. If filter has no Name it takes name, so
+// it can be found again. Unlike UpsertFilter, it adds nothing if name isn't
+// found. The replacement starts with fresh runtime state, as with
+// UpsertFilter.
+func (h *Handler) ReplaceFilter(name string, filter LogFilter) bool {
+	if name == "" {
+		return false
+	}
+	if filter.Name == "" {
+		filter.Name = name
+	}
+	return h.putFilterByName(name, filter, false)
+}
+
+// addFilterIfNameFree adds filter unless another filter already has its
+// Name, reporting whether it was added. The check and the add happen under
+// one lock, so of concurrent callers adding the same name only one succeeds.
+// Unlike AddFilter it doesn't screen filter; callers do that first.
+func (h *Handler) addFilterIfNameFree(filter LogFilter) bool {
+	h.filtersLock.Lock()
+	if filter.Name != "" {
+		for _, f := range h.filters {
+			if f.Name == filter.Name {
+				h.filtersLock.Unlock()
+				return false
+			}
+		}
+	}
+	h.filters = append(h.filters, filter)
+	h.updateLowestLevel()
+	h.filtersLock.Unlock()
+
+	warnInvalid([]LogFilter{filter})
+	warnUnregisteredContextKeys([]LogFilter{filter})
+	return true
+}
+
+// putFilterByName replaces the first filter named name with filter, or with
+// add set, adds filter if there is none. It reports whether a filter was
+// replaced.
+func (h *Handler) putFilterByName(name string, filter LogFilter, add bool) bool {
+	filter.state = nil // Don't carry over the state of a filter read back from the handler
+	accepted, rejected := h.screenFilters([]LogFilter{filter})
+	if len(accepted) == 0 {
+		warnRejected(rejected)
+		return false
+	}
+
+	h.filtersLock.Lock()
+	replaced := false
+	if name != "" {
+		for i := range h.filters {
+			if h.filters[i].Name == name {
+				// Copy rather than write in place: records being evaluated may
+				// still hold the old slice.
+				filters := make([]LogFilter, len(h.filters))
+				copy(filters, h.filters)
+				filters[i] = filter
+				h.filters = filters
+				replaced = true
+				break
+			}
+		}
+	}
+	if !replaced && add {
+		h.filters = append(h.filters, filter)
+	}
+	if replaced || add {
+		h.updateLowestLevel()
+	}
+	h.filtersLock.Unlock()
+
+	if replaced || add {
+		warnInvalid(accepted)
+		warnUnregisteredContextKeys(accepted)
+	}
+	return replaced
+}
+
+// GetFilterByName returns a copy of the global handler's first filter named
+// name, and whether one was found.
+func GetFilterByName(name string) (LogFilter, bool) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.GetFilterByName(name)
+	}
+	return LogFilter{}, false
+}
+
+// RemoveFilterByName removes the first filter named name from the global
+// handler, reporting whether one was found.
+func RemoveFilterByName(name string) bool {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.RemoveFilterByName(name)
+	}
+	return false
+}
+
+// UpsertFilter replaces the global handler's first filter with the same Name
+// as filter, or adds filter if there is none.
+func UpsertFilter(filter LogFilter) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.UpsertFilter(filter)
+	}
+}
+
+// ReplaceFilter replaces the global handler's first filter named name with
+// filter, reporting whether one was found.
+func ReplaceFilter(name string, filter LogFilter) bool {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.ReplaceFilter(name, filter)
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestHandler_RemoveFilterByName(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	// Same type and pattern, differing only in level and expiry
+	expiry := time.Now().Add(time.Hour)
+	handler.SetFilters([]LogFilter{
+		{Name: "short-debug", Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true, ExpiresAt: &expiry},
+		{Name: "quiet", Type: "job_id", Pattern: "job_*", Level: "error", Enabled: true},
+		{Type: "job_id", Pattern: "job_*", Level: "warn", Enabled: true},
+	})
+
+	if !handler.RemoveFilterByName("short-debug") {
+		t.Fatal("Expected removal by name to succeed")
+	}
+	filters := handler.GetFilters()
+	if len(filters) != 2 {
+		t.Fatalf("Expected exactly one filter removed, got %d left", len(filters))
+	}
+	if filters[0].Name != "quiet" || filters[0].Level != "error" || filters[1].Level != "warn" {
+		t.Errorf("Expected remaining filters untouched and in order, got %+v", filters)
+	}
+
+	if handler.RemoveFilterByName("short-debug") {
+		t.Error("Expected removal of missing name to report false")
+	}
+	if handler.RemoveFilterByName("") {
+		t.Error("Expected removal of empty name to report false")
+	}
+	if n := len(handler.GetFilters()); n != 2 {
+		t.Errorf("Expected 2 filters after failed removals, got %d", n)
+	}
+}
+
+func TestHandler_RemoveFilterByName_Duplicates(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Name: "dup", Type: "a", Pattern: "1", Level: "debug", Enabled: true},
+		{Name: "dup", Type: "b", Pattern: "2", Level: "debug", Enabled: true},
+	})
+
+	handler.RemoveFilterByName("dup")
+	filters := handler.GetFilters()
+	if len(filters) != 1 || filters[0].Type != "b" {
+		t.Errorf("Expected only the first named filter removed, got %+v", filters)
+	}
+}
+
+func TestHandler_GetFilterByName(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "warn", Enabled: true},
+		{Name: "checkout", Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+	})
+
+	f, ok := handler.GetFilterByName("checkout")
+	if !ok {
+		t.Fatal("Expected filter to be found by name")
+	}
+	if f.Level != "debug" {
+		t.Errorf("Expected the named filter, got level %q", f.Level)
+	}
+
+	// The result is a copy
+	f.Level = "error"
+	if got, _ := handler.GetFilterByName("checkout"); got.Level != "debug" {
+		t.Errorf("Expected handler's filter unchanged, got level %q", got.Level)
+	}
+
+	if _, ok := handler.GetFilterByName("missing"); ok {
+		t.Error("Expected missing name not to be found")
+	}
+	if _, ok := handler.GetFilterByName(""); ok {
+		t.Error("Expected empty name not to match unnamed filters")
+	}
+}
+
+func TestFilterByName_Global(t *testing.T) {
+	_ = New()
+	ClearFilters()
+
+	AddFilter(LogFilter{Name: "x-debug", Type: "x", Pattern: "y", Level: "debug", Enabled: true})
+	AddFilter(LogFilter{Type: "x", Pattern: "y", Level: "warn", Enabled: true})
+
+	if _, ok := GetFilterByName("x-debug"); !ok {
+		t.Error("Expected named filter to be found")
+	}
+	if !RemoveFilterByName("x-debug") {
+		t.Error("Expected removal by name to succeed")
+	}
+	if filters := GetFilters(); len(filters) != 1 || filters[0].Level != "warn" {
+		t.Errorf("Expected only the unnamed filter left, got %+v", filters)
+	}
+}
+
+func TestHandler_UpsertFilter(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Name: "first", Type: "a", Pattern: "1", Level: "warn", Enabled: true},
+		{Name: "checkout", Type: "job_id", Pattern: "job_1", Level: "error", Enabled: true},
+		{Type: "b", Pattern: "2", Level: "warn", Enabled: true},
+	})
+
+	// Update existing: replaced in place, lowest level recomputed
+	handler.UpsertFilter(LogFilter{Name: "checkout", Type: "job_id", Pattern: "job_2", Level: "debug", Enabled: true})
+	filters := handler.GetFilters()
+	if len(filters) != 3 {
+		t.Fatalf("Expected the named filter replaced, got %d filters", len(filters))
+	}
+	if filters[1].Name != "checkout" || filters[1].Pattern != "job_2" || filters[1].Level != "debug" {
+		t.Errorf("Expected checkout replaced in place, got %+v", filters)
+	}
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected the replacement's level to lower the enabled threshold")
+	}
+
+	// Insert new: appended
+	handler.UpsertFilter(LogFilter{Name: "billing", Type: "service", Pattern: "billing", Level: "debug", Enabled: true})
+	filters = handler.GetFilters()
+	if len(filters) != 4 || filters[3].Name != "billing" {
+		t.Errorf("Expected billing appended, got %+v", filters)
+	}
+
+	// Unnamed filters are always added
+	handler.UpsertFilter(LogFilter{Type: "b", Pattern: "2", Level: "error", Enabled: true})
+	if n := len(handler.GetFilters()); n != 5 {
+		t.Errorf("Expected unnamed filter added, got %d filters", n)
+	}
+}
+
+func TestHandler_ReplaceFilter(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Name: "checkout", Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true},
+		{Type: "b", Pattern: "2", Level: "warn", Enabled: true},
+	})
+
+	if !handler.ReplaceFilter("checkout", LogFilter{Type: "job_id", Pattern: "job_9", Level: "error", Enabled: true}) {
+		t.Fatal("Expected replacement of existing name to succeed")
+	}
+	f, ok := handler.GetFilterByName("checkout")
+	if !ok || f.Pattern != "job_9" || f.Level != "error" {
+		t.Errorf("Expected replacement to take the name, got %+v, %v", f, ok)
+	}
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug disabled once the debug filter was replaced")
+	}
+
+	if handler.ReplaceFilter("missing", LogFilter{Type: "c", Pattern: "3", Level: "debug", Enabled: true}) {
+		t.Error("Expected replacement of missing name to report false")
+	}
+	if handler.ReplaceFilter("", LogFilter{Type: "c", Pattern: "3", Level: "debug", Enabled: true}) {
+		t.Error("Expected replacement of empty name to report false")
+	}
+	if n := len(handler.GetFilters()); n != 2 {
+		t.Errorf("Expected nothing added by failed replacements, got %d filters", n)
+	}
+}
+
+func TestHandler_UpsertFilter_RejectMatchAll(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithRejectMatchAll(true))
+	handler.SetFilters([]LogFilter{{Name: "checkout", Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true}})
+
+	if handler.ReplaceFilter("checkout", LogFilter{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true}) {
+		t.Error("Expected unconfirmed match-all replacement to be rejected")
+	}
+	handler.UpsertFilter(LogFilter{Name: "checkout", Type: "job_id", Pattern: "*", Level: "debug", Enabled: true})
+	if f, _ := handler.GetFilterByName("checkout"); f.Pattern != "job_1" {
+		t.Errorf("Expected original filter kept, got %+v", f)
+	}
+}
+
+func TestUpsertFilter_Global(t *testing.T) {
+	_ = New()
+	ClearFilters()
+
+	UpsertFilter(LogFilter{Name: "x-debug", Type: "x", Pattern: "y", Level: "debug", Enabled: true})
+	UpsertFilter(LogFilter{Name: "x-debug", Type: "x", Pattern: "z", Level: "debug", Enabled: true})
+	if filters := GetFilters(); len(filters) != 1 || filters[0].Pattern != "z" {
+		t.Errorf("Expected one upserted filter, got %+v", filters)
+	}
+	if !ReplaceFilter("x-debug", LogFilter{Type: "x", Pattern: "w", Level: "warn", Enabled: true}) {
+		t.Error("Expected global replacement to succeed")
+	}
+	if f, ok := GetFilterByName("x-debug"); !ok || f.Pattern != "w" {
+		t.Errorf("Expected replaced filter, got %+v, %v", f, ok)
+	}
+}
+
+func TestHandler_UpsertFilter_ResetsState(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Name: "once", Type: "job_id", Pattern: "job_1", Level: "debug", MaxHits: 1, Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("first", "job_id", "job_1")
+	logger.Debug("spent", "job_id", "job_1")
+	if bytes.Contains(buf.Bytes(), []byte("spent")) {
+		t.Fatalf("Expected the filter's MaxHits to be used up, got: %s", buf.String())
+	}
+
+	for _, put := range []func(LogFilter){
+		handler.UpsertFilter,
+		func(f LogFilter) { handler.ReplaceFilter("once", f) },
+	} {
+		f, ok := handler.GetFilterByName("once")
+		if !ok {
+			t.Fatal("Expected the filter to be found")
+		}
+		put(f)
+
+		buf.Reset()
+		logger.Debug("again", "job_id", "job_1")
+		if !bytes.Contains(buf.Bytes(), []byte("again")) {
+			t.Errorf("Expected the replaced filter to fire again, got: %q", buf.String())
+		}
+		if stats := handler.Stats(); len(stats) != 1 || stats[0].Matches != 1 {
+			t.Errorf("Expected match counts to restart, got %+v", stats)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestHandler_NoFilters(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	tests := []struct {
+		name   string
+		mutate func()
+		want   bool
+	}{
+		{"new handler", func() {}, true},
+		{"filter set", func() { handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Enabled: true}}) }, false},
+		{"filters cleared", func() { handler.ClearFilters() }, true},
+		{"suppression set", func() { handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/health"}}) }, false},
+		{"suppressions cleared", func() { handler.SetGlobalSuppressions(nil) }, true},
+		{"disabled filter set", func() { handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*"}}) }, false},
+	}
+
+	for _, tt := range tests {
+		tt.mutate()
+		if got := handler.noFilters.Load(); got != tt.want {
+			t.Errorf("%s: expected noFilters=%v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	withBase := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level,
+		WithBaseFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Enabled: true}}))
+	if withBase.noFilters.Load() {
+		t.Error("Expected base filters to count as filters")
+	}
+}
+
+func TestHandler_NoFilters_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, WithLevelFloor(slog.LevelInfo))
+	logger := slog.New(handler)
+	debugCtx := ContextWithLevel(context.Background(), slog.LevelDebug)
+
+	tests := []struct {
+		name string
+		log  func()
+		want bool
+	}{
+		{"info emitted", func() { logger.Info("info", "job_id", "job_1") }, true},
+		{"debug suppressed", func() { logger.Debug("debug") }, false},
+		{"warn emitted", func() { logger.Warn("warn") }, true},
+		{"context level raises", func() { logger.WarnContext(ContextWithLevel(context.Background(), slog.LevelError), "warn") }, false},
+		{"context level floored", func() { logger.DebugContext(debugCtx, "debug") }, false},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		tt.log()
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.want, buf.String())
+		}
+	}
+	if stats := handler.LevelStats(); stats[slog.LevelInfo].Emitted != 1 {
+		t.Errorf("Expected counters to be kept on the fast path, got %+v", stats)
+	}
+}
+
+// BenchmarkHandle_NoFilters compares a handler without filters, the common
+// case of one installed preemptively, with its inner handler alone.
+func BenchmarkHandle_NoFilters(b *testing.B) {
+	inner := slog.NewJSONHandler(io.Discard, nil)
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handlers := []struct {
+		name    string
+		handler slog.Handler
+	}{
+		{"inner", inner},
+		{"filtered", NewHandler(inner, level)},
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "request", 0)
+	r.AddAttrs(slog.String("service", "billing"), slog.String("user_id", "u_1"))
+	ctx := context.Background()
+
+	for _, hh := range handlers {
+		b.Run(hh.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = hh.handler.Handle(ctx, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package logfilter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numericRange is a compiled numeric pattern: an interval whose ends are
+// optionally exclusive. Unbounded ends are infinite.
+type numericRange struct {
+	lo, hi                   float64
+	loExclusive, hiExclusive bool
+}
+
+// NewNumericMatcher compiles a numeric comparison (LogFilter.Numeric):
+//
+//	">500"       greater than
+//	">=3"        greater than or equal
+//	"<10"        less than
+//	"<=10"       less than or equal
+//	"=42", "42"  equal
+//	"100..500"   inclusive range
+//
+// Bounds and values may be integers or floats and are compared as float64.
+// Values that don't parse as a number never match.
+func NewNumericMatcher(pattern string) (Matcher, error) {
+	r, err := parseNumericRange(strings.TrimSpace(pattern))
+	if err != nil {
+		return Matcher{kind: matchNone}, fmt.Errorf("logfilter: invalid numeric pattern %q: %w", pattern, err)
+	}
+	return Matcher{kind: matchNumeric, num: r}, nil
+}
+
+// parseNumericRange parses a trimmed numeric pattern.
+func parseNumericRange(p string) (*numericRange, error) {
+	r := &numericRange{lo: math.Inf(-1), hi: math.Inf(1)}
+	var err error
+	switch {
+	case strings.HasPrefix(p, ">="):
+		r.lo, err = parseBound(p[2:])
+	case strings.HasPrefix(p, ">"):
+		r.lo, err = parseBound(p[1:])
+		r.loExclusive = true
+	case strings.HasPrefix(p, "<="):
+		r.hi, err = parseBound(p[2:])
+	case strings.HasPrefix(p, "<"):
+		r.hi, err = parseBound(p[1:])
+		r.hiExclusive = true
+	case strings.Contains(p, ".."):
+		lo, hi, _ := strings.Cut(p, "..")
+		if r.lo, err = parseBound(lo); err != nil {
+			return nil, err
+		}
+		if r.hi, err = parseBound(hi); err != nil {
+			return nil, err
+		}
+		if r.lo > r.hi {
+			return nil, fmt.Errorf("range start %v exceeds end %v", r.lo, r.hi)
+		}
+	default:
+		r.lo, err = parseBound(strings.TrimPrefix(p, "="))
+		r.hi = r.lo
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseBound parses one end of a numeric pattern. NaN is rejected since it
+// compares false with everything.
+func parseBound(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) {
+		return 0, fmt.Errorf("bound is NaN")
+	}
+	return v, nil
+}
+
+// match reports whether value parses as a number inside the range.
+func (r *numericRange) match(value string) bool {
+	v, ok := parseNumber(value)
+	return ok && r.contains(v)
+}
+
+// contains reports whether v lies inside the range.
+func (r *numericRange) contains(v float64) bool {
+	if v < r.lo || (r.loExclusive && v == r.lo) {
+		return false
+	}
+	return v < r.hi || (!r.hiExclusive && v == r.hi)
+}
+
+// parseNumber parses an attribute value as an integer or float. NaN is not
+// a number for matching purposes.
+func parseNumber(value string) (float64, bool) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) {
+		return 0, false
+	}
+	return v, true
+}
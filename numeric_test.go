@@ -0,0 +1,162 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestNewNumericMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		// Greater than
+		{">500", "501", true},
+		{">500", "500", false},
+		{">500", "499", false},
+		{">500", "500.0001", true},
+		{">0.5", "0.5", false},
+		{">0.5", "0.75", true},
+
+		// Greater than or equal
+		{">=3", "3", true},
+		{">=3", "2", false},
+		{">=3", "2.999", false},
+		{">=3", "3.0", true},
+		{">=-1", "-1", true},
+		{">=-1", "-2", false},
+
+		// Less than
+		{"<10", "9", true},
+		{"<10", "10", false},
+		{"<10", "9.99", true},
+		{"<10", "-100", true},
+
+		// Less than or equal
+		{"<=10", "10", true},
+		{"<=10", "10.01", false},
+		{"<=1.5", "1.5", true},
+
+		// Equal
+		{"=42", "42", true},
+		{"42", "42", true},
+		{"42", "42.0", true},
+		{"42", "43", false},
+
+		// Inclusive range
+		{"100..500", "100", true},
+		{"100..500", "500", true},
+		{"100..500", "300", true},
+		{"100..500", "99", false},
+		{"100..500", "501", false},
+		{"100..500", "500.5", false},
+		{"-5..5", "-5", true},
+		{"-5..5", "0", true},
+		{"0.5..1.5", "1.5", true},
+		{"0.5..1.5", "1.6", false},
+
+		// Whitespace in pattern
+		{" >= 3 ", "3", true},
+		{"100 .. 500", "100", true},
+
+		// Non-numeric values never match
+		{">500", "", false},
+		{">500", "fast", false},
+		{">500", "600ms", false},
+		{"<10", "NaN", false},
+		{"0..10", "5 ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.value, func(t *testing.T) {
+			m, err := NewNumericMatcher(tt.pattern)
+			if err != nil {
+				t.Fatalf("Expected pattern %q to compile, got %v", tt.pattern, err)
+			}
+			if got := m.Match(tt.value); got != tt.want {
+				t.Errorf("Expected %q matching %q = %v, got %v", tt.pattern, tt.value, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewNumericMatcher_Invalid(t *testing.T) {
+	for _, pattern := range []string{"", ">", ">=abc", "<x", "500..100", "..5", "5..", "NaN", "*"} {
+		m, err := NewNumericMatcher(pattern)
+		if err == nil {
+			t.Errorf("Expected pattern %q to be rejected", pattern)
+		}
+		if m.Match("5") {
+			t.Errorf("Expected invalid pattern %q to match nothing", pattern)
+		}
+	}
+}
+
+func TestLogFilter_Numeric(t *testing.T) {
+	f := LogFilter{Type: "retry_count", Pattern: ">=3", Numeric: true}
+	if !f.Matches("3") || f.Matches("2") {
+		t.Errorf("Expected >=3 to match 3 but not 2")
+	}
+
+	f.Negate = true
+	if f.Matches("3") || !f.Matches("2") {
+		t.Errorf("Expected negated >=3 to match 2 but not 3")
+	}
+	if f.Matches("many") {
+		t.Errorf("Expected negated numeric filter not to match a non-numeric value")
+	}
+
+	invalid := LogFilter{Type: "retry_count", Pattern: ">=x", Numeric: true, Negate: true}
+	if invalid.Matches("3") || invalid.IsMatchAll() {
+		t.Errorf("Expected invalid numeric filter to match nothing, even negated")
+	}
+
+	// Numeric takes precedence over Regex
+	both := LogFilter{Type: "retry_count", Pattern: "<10", Numeric: true, Regex: true}
+	if !both.Matches("5") {
+		t.Errorf("Expected Numeric to take precedence over Regex")
+	}
+}
+
+func TestHandler_NumericFilter(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "duration_ms", Pattern: ">500", Numeric: true, Level: "debug", Enabled: true},
+		{Type: "ratio", Pattern: "0.9..1", Numeric: true, Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		args  []any
+		emits bool
+	}{
+		{"int above", []any{"duration_ms", 750}, true},
+		{"int at bound", []any{"duration_ms", 500}, false},
+		{"uint above", []any{"duration_ms", uint64(501)}, true},
+		{"float above", []any{"duration_ms", 500.5}, true},
+		{"float below", []any{"duration_ms", 499.9}, false},
+		{"string number", []any{"duration_ms", "900"}, true},
+		{"duration value", []any{"duration_ms", 900 * time.Millisecond}, false},
+		{"float in range", []any{"ratio", 0.95}, true},
+		{"float out of range", []any{"ratio", 0.5}, false},
+		{"absent", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.Debug("request", tt.args...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+// filterTypes returns the Type of each filter, in order.
+func filterTypes(filters []LogFilter) []string {
+	types := make([]string, len(filters))
+	for i, f := range filters {
+		types[i] = f.Type
+	}
+	return types
+}
+
+func TestHandler_GetFilters_OrderStable(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	tests := []struct {
+		name   string
+		mutate func()
+		want   []string
+	}{
+		{
+			"set",
+			func() {
+				handler.SetFilters([]LogFilter{
+					{Type: "a", Pattern: "1", Enabled: true},
+					{Type: "b", Pattern: "1", Enabled: true, Priority: 5},
+					{Type: "c", Pattern: "1", Enabled: true},
+				})
+			},
+			[]string{"a", "b", "c"},
+		},
+		{
+			"add with priority",
+			func() { handler.AddFilter(LogFilter{Type: "d", Pattern: "1", Enabled: true, Priority: 10}) },
+			[]string{"a", "b", "c", "d"},
+		},
+		{
+			"remove from middle",
+			func() { handler.RemoveFilter("b", "1") },
+			[]string{"a", "c", "d"},
+		},
+		{
+			"add after remove",
+			func() { handler.AddFilter(LogFilter{Type: "b", Pattern: "1", Enabled: true}) },
+			[]string{"a", "c", "d", "b"},
+		},
+		{
+			"upsert new",
+			func() { handler.UpsertFilter(LogFilter{Name: "named", Type: "e", Pattern: "1", Enabled: true}) },
+			[]string{"a", "c", "d", "b", "e"},
+		},
+		{
+			"upsert existing in place",
+			func() {
+				handler.UpsertFilter(LogFilter{Name: "named", Type: "e2", Pattern: "1", Enabled: true, Priority: -3})
+			},
+			[]string{"a", "c", "d", "b", "e2"},
+		},
+		{
+			"remove by ID",
+			func() { handler.RemoveFilterByID(handler.GetFilters()[0].FilterID()) },
+			[]string{"c", "d", "b", "e2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.mutate()
+		got := filterTypes(handler.GetFilters())
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		// Repeated calls return the same order.
+		if again := filterTypes(handler.GetFilters()); !slices.Equal(again, got) {
+			t.Errorf("%s: expected repeated GetFilters to match, got %v then %v", tt.name, got, again)
+		}
+	}
+}
+
+func TestHandler_GetFilters_PriorityStillEvaluated(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+	})
+	handler.AddFilter(LogFilter{Type: "job_id", Pattern: "job_1", Level: "error", Enabled: true, Priority: 10})
+
+	if filters := handler.GetFilters(); len(filters) != 2 || filters[1].Priority != 10 {
+		t.Errorf("Expected the priority filter last in GetFilters, got %+v", filters)
+	}
+	slog.New(handler).Debug("step", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected the higher-priority filter to be evaluated first, got: %s", buf.String())
+	}
+}
+
+func TestHandler_GetActiveFilters(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithClock(clock))
+	expiry := clock.Now().Add(time.Minute)
+	handler.SetFilters([]LogFilter{
+		{Type: "enabled", Pattern: "1", Level: "debug", Enabled: true},
+		{Type: "disabled", Pattern: "1", Level: "debug"},
+		{Type: "expiring", Pattern: "1", Level: "debug", Enabled: true, ExpiresAt: &expiry},
+		{Type: "exhausted", Pattern: "1", Level: "debug", Enabled: true, MaxHits: 1},
+		{Type: "priority", Pattern: "1", Level: "debug", Enabled: true, Priority: 5},
+	})
+
+	want := []string{"enabled", "expiring", "exhausted", "priority"}
+	if got := filterTypes(handler.GetActiveFilters()); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	slog.New(handler).Debug("hit", "exhausted", "1")
+	clock.Advance(2 * time.Minute)
+
+	want = []string{"enabled", "priority"}
+	if got := filterTypes(handler.GetActiveFilters()); !slices.Equal(got, want) {
+		t.Errorf("Expected %v after expiry and exhaustion, got %v", want, got)
+	}
+	if n := len(handler.GetFilters()); n != 5 {
+		t.Errorf("Expected GetFilters to still return all 5 filters, got %d", n)
+	}
+}
+
+func TestGetActiveFilters_Global(t *testing.T) {
+	_ = New()
+	SetFilters([]LogFilter{
+		{Type: "on", Pattern: "1", Level: "debug", Enabled: true},
+		{Type: "off", Pattern: "1", Level: "debug"},
+	})
+
+	if got := filterTypes(GetActiveFilters()); !slices.Equal(got, []string{"on"}) {
+		t.Errorf("Expected only the enabled filter, got %v", got)
+	}
+}
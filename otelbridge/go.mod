@@ -0,0 +1,12 @@
+module github.com/jmylchreest/slog-logfilter/otelbridge
+
+// go.opentelemetry.io/otel v1.46.0 requires go 1.25.0.
+go 1.25.0
+
+require (
+	github.com/jmylchreest/slog-logfilter v0.1.0
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/log v0.22.0
+)
+
+require github.com/cespare/xxhash/v2 v2.3.0 // indirect
@@ -0,0 +1,191 @@
+// Package otelbridge provides an slog.Handler that forwards records to the
+// OpenTelemetry logs API, for use as the inner handler of a logfilter.Handler.
+//
+// The logfilter handler sits in front, so elevation, suppression and output
+// level transformation are applied before records reach OpenTelemetry:
+//
+//	provider := sdklog.NewLoggerProvider(...)
+//	inner := otelbridge.NewHandler(provider, "github.com/example/app")
+//	handler := logfilter.NewHandler(inner, level)
+//	slog.SetDefault(slog.New(handler))
+//
+// This package lives in its own module so the core logfilter package stays
+// free of dependencies.
+package otelbridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+// Handler is an slog.Handler that emits records through an OpenTelemetry
+// log.Logger. Attributes, including those added with WithAttrs, are preserved;
+// groups become nested map values.
+type Handler struct {
+	logger log.Logger
+	frames []frame // frames[0] is the root; each WithGroup opens another
+}
+
+// frame holds the attributes added within one group.
+type frame struct {
+	group string
+	attrs []attribute.KeyValue
+}
+
+// NewHandler creates a Handler emitting through the provider's logger with
+// the given instrumentation scope name, typically the application's module
+// or package path.
+func NewHandler(provider log.LoggerProvider, name string, opts ...log.LoggerOption) *Handler {
+	return &Handler{
+		logger: provider.Logger(name, opts...),
+		frames: []frame{{}},
+	}
+}
+
+// Enabled reports whether the OpenTelemetry logger emits at the given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(ctx, log.EnabledParameters{Severity: Severity(level)})
+}
+
+// Handle converts the record and emits it. The record's level, which a
+// logfilter.Handler in front may already have transformed, determines the
+// severity; the message becomes the body.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var rec log.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetSeverity(Severity(r.Level))
+	rec.SetSeverityText(r.Level.String())
+	rec.SetBody(attribute.StringValue(r.Message))
+
+	var attrs []attribute.KeyValue
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, a)
+		return true
+	})
+
+	// Fold the record's attributes into the open groups, innermost first.
+	for i := len(h.frames) - 1; i >= 0; i-- {
+		f := h.frames[i]
+		attrs = append(append(make([]attribute.KeyValue, 0, len(f.attrs)+len(attrs)), f.attrs...), attrs...)
+		if f.group != "" && len(attrs) > 0 {
+			attrs = []attribute.KeyValue{attribute.Map(f.group, attrs...)}
+		}
+	}
+	rec.AddAttributes(attrs...)
+
+	h.logger.Emit(ctx, rec)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record, within any
+// groups opened so far.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	frames := make([]frame, len(h.frames))
+	copy(frames, h.frames)
+
+	last := &frames[len(frames)-1]
+	converted := make([]attribute.KeyValue, len(last.attrs), len(last.attrs)+len(attrs))
+	copy(converted, last.attrs)
+	for _, a := range attrs {
+		converted = appendAttr(converted, a)
+	}
+	last.attrs = converted
+
+	return &Handler{logger: h.logger, frames: frames}
+}
+
+// WithGroup returns a handler that nests subsequent attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	frames := make([]frame, len(h.frames), len(h.frames)+1)
+	copy(frames, h.frames)
+	return &Handler{logger: h.logger, frames: append(frames, frame{group: name})}
+}
+
+// Severity maps an slog level to an OpenTelemetry severity, following the
+// OpenTelemetry mapping for slog: DEBUG is SeverityDebug1, INFO SeverityInfo1,
+// WARN SeverityWarn1 and ERROR SeverityError1, with custom levels in between
+// mapping to the numbered severities. Levels outside the range are clamped.
+func Severity(level slog.Level) log.Severity {
+	s := int(level) + int(log.SeverityInfo1)
+	switch {
+	case s < int(log.SeverityTrace1):
+		return log.SeverityTrace1
+	case s > int(log.SeverityFatal4):
+		return log.SeverityFatal4
+	default:
+		return log.Severity(s)
+	}
+}
+
+// appendAttr converts a and appends it to attrs, following slog's rules:
+// empty attributes are dropped and groups with an empty key are inlined.
+func appendAttr(attrs []attribute.KeyValue, a slog.Attr) []attribute.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return attrs
+		}
+		if a.Key == "" {
+			for _, ga := range group {
+				attrs = appendAttr(attrs, ga)
+			}
+			return attrs
+		}
+		var members []attribute.KeyValue
+		for _, ga := range group {
+			members = appendAttr(members, ga)
+		}
+		return append(attrs, attribute.Map(a.Key, members...))
+	}
+	return append(attrs, attribute.KeyValue{Key: attribute.Key(a.Key), Value: convertValue(a.Value)})
+}
+
+// convertValue converts a resolved, non-group slog value.
+func convertValue(v slog.Value) attribute.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return attribute.StringValue(v.String())
+	case slog.KindInt64:
+		return attribute.Int64Value(v.Int64())
+	case slog.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			return attribute.Int64Value(int64(u))
+		}
+		return attribute.StringValue(v.String())
+	case slog.KindFloat64:
+		return attribute.Float64Value(v.Float64())
+	case slog.KindBool:
+		return attribute.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return attribute.Int64Value(v.Duration().Nanoseconds())
+	case slog.KindTime:
+		return attribute.StringValue(v.Time().Format(time.RFC3339Nano))
+	default:
+		switch x := v.Any().(type) {
+		case error:
+			return attribute.StringValue(x.Error())
+		case []byte:
+			return attribute.ByteSliceValue(x)
+		case fmt.Stringer:
+			return attribute.StringValue(x.String())
+		default:
+			return attribute.StringValue(fmt.Sprint(x))
+		}
+	}
+}
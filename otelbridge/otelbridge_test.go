@@ -0,0 +1,149 @@
+package otelbridge
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+// provider is a LoggerProvider handing out its recorder.
+type provider struct {
+	embedded.LoggerProvider
+	rec *recorder
+}
+
+func (p provider) Logger(name string, _ ...log.LoggerOption) log.Logger {
+	p.rec.name = name
+	return p.rec
+}
+
+// recorder is a Logger that keeps emitted records.
+type recorder struct {
+	embedded.Logger
+
+	mu      sync.Mutex
+	name    string
+	minimum log.Severity
+	records []log.Record
+}
+
+func (r *recorder) Emit(_ context.Context, rec log.Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec.Clone())
+}
+
+func (r *recorder) Enabled(_ context.Context, p log.EnabledParameters) bool {
+	return p.Severity >= r.minimum
+}
+
+// attrs returns the attributes of the i'th record keyed by name.
+func (r *recorder) attrs(i int) map[string]attribute.Value {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]attribute.Value)
+	r.records[i].WalkAttributes(func(kv attribute.KeyValue) bool {
+		out[string(kv.Key)] = kv.Value
+		return true
+	})
+	return out
+}
+
+func TestHandler_Handle(t *testing.T) {
+	rec := &recorder{}
+	logger := slog.New(NewHandler(provider{rec: rec}, "test/app")).With("service", "api").WithGroup("req")
+
+	logger.Warn("slow", "path", "/x", "ms", 250, "err", errors.New("timeout"))
+
+	if rec.name != "test/app" {
+		t.Errorf("Expected logger name %q, got %q", "test/app", rec.name)
+	}
+	if len(rec.records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(rec.records))
+	}
+	r := rec.records[0]
+	if r.Severity() != log.SeverityWarn1 || r.SeverityText() != "WARN" {
+		t.Errorf("Expected WARN severity, got %v %q", r.Severity(), r.SeverityText())
+	}
+	if r.Body().AsString() != "slow" {
+		t.Errorf("Expected body %q, got %q", "slow", r.Body().AsString())
+	}
+
+	attrs := rec.attrs(0)
+	if attrs["service"].AsString() != "api" {
+		t.Errorf("Expected service=api, got %v", attrs["service"])
+	}
+	group := attrs["req"]
+	if group.Type() != attribute.MAP {
+		t.Fatalf("Expected req to be a map, got %v", group.Type())
+	}
+	members := make(map[string]attribute.Value)
+	for _, kv := range group.AsMap() {
+		members[string(kv.Key)] = kv.Value
+	}
+	if members["path"].AsString() != "/x" || members["ms"].AsInt64() != 250 || members["err"].AsString() != "timeout" {
+		t.Errorf("Unexpected group members: %v", members)
+	}
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	rec := &recorder{minimum: log.SeverityInfo1}
+	h := NewHandler(provider{rec: rec}, "test")
+
+	if h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug to be disabled")
+	}
+	if !h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Expected info to be enabled")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  log.Severity
+	}{
+		{slog.LevelDebug, log.SeverityDebug1},
+		{slog.LevelInfo, log.SeverityInfo1},
+		{slog.LevelWarn, log.SeverityWarn1},
+		{slog.LevelError, log.SeverityError1},
+		{slog.LevelInfo + 1, log.SeverityInfo2},
+		{-100, log.SeverityTrace1},
+		{100, log.SeverityFatal4},
+	}
+	for _, tt := range tests {
+		if got := Severity(tt.level); got != tt.want {
+			t.Errorf("Severity(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_BehindLogFilter(t *testing.T) {
+	rec := &recorder{}
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := logfilter.NewHandler(NewHandler(provider{rec: rec}, "test"), level)
+	handler.SetFilters([]logfilter.LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", OutputLevel: "info", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("elevated", "job_id", "job_1")
+	logger.Debug("suppressed", "job_id", "job_2")
+
+	if len(rec.records) != 1 {
+		t.Fatalf("Expected only the elevated record to reach OTel, got %d", len(rec.records))
+	}
+	r := rec.records[0]
+	if r.Body().AsString() != "elevated" || r.Severity() != log.SeverityInfo1 {
+		t.Errorf("Expected elevated record at INFO, got %q at %v", r.Body().AsString(), r.Severity())
+	}
+}
@@ -0,0 +1,84 @@
+package logfilter
+
+// Values for LogFilter.MatchMode and Condition.MatchMode.
+const (
+	MatchPresent  = "present"  // The value exists, whatever it is
+	MatchAbsent   = "absent"   // The value doesn't exist
+	MatchEmpty    = "empty"    // The value exists and is empty
+	MatchNonEmpty = "nonempty" // The value exists and isn't empty
+)
+
+// validMatchMode reports whether mode is empty or one of the MatchMode values.
+func validMatchMode(mode string) bool {
+	switch mode {
+	case "", MatchPresent, MatchAbsent, MatchEmpty, MatchNonEmpty, MatchErrorType:
+		return true
+	}
+	return false
+}
+
+// matchPresence reports whether the value f's Type names satisfies f's
+// MatchMode, returning the value (empty if absent).
+func (v *recordView) matchPresence(f *LogFilter) (string, bool) {
+	value, found := v.lookup(f)
+	switch f.MatchMode {
+	case MatchPresent:
+		return value, found
+	case MatchAbsent:
+		return "", !found
+	case MatchEmpty:
+		return value, found && value == ""
+	case MatchNonEmpty:
+		return value, found && value != ""
+	}
+	return "", false
+}
+
+// lookup returns the value f's Type names in the record, and whether it
+// exists, without matching it against f's Pattern.
+func (v *recordView) lookup(f *LogFilter) (string, bool) {
+	switch f.kind {
+	case filterKindSourceFile:
+		return v.sourceFile, v.sourceFile != ""
+	case filterKindSourceFunction:
+		return v.sourceFunction, v.sourceFunction != ""
+	case filterKindSourcePackage:
+		return v.sourcePackage, v.sourcePackage != ""
+	case filterKindContext:
+		return v.lookupContext(f)
+	case filterKindComponent:
+		return v.h.componentValue(*v.r)
+	case filterKindMessage:
+		return v.r.Message, true
+	case filterKindHasError:
+		return v.h.recordError(*v.r)
+	case filterKindAny:
+		if value, ok := v.lookupAttr(f); ok {
+			return value, true
+		}
+		return extractFromContext(v.ctx, f.contextKey)
+	default:
+		return v.lookupAttr(f)
+	}
+}
+
+// lookupAttr returns the attribute f's Type names, from the sources its
+// MatchScope allows, following a dotted path into a map or group value if
+// there is no attribute of that name.
+func (v *recordView) lookupAttr(f *LogFilter) (string, bool) {
+	if f.keyGlob != nil {
+		return v.lookupGlobAttr(f)
+	}
+	var value string
+	var found bool
+	if f.scoped() {
+		s := v.scopedAttr(f.attributeKey, f.MatchScope)
+		value, found = s.string(v.h)
+	} else {
+		value, found = v.attr(f.attributeKey)
+	}
+	if !found && f.keyPath {
+		value, found = v.lookupPath(f.attributeKey, f.MatchScope)
+	}
+	return value, found
+}
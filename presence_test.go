@@ -0,0 +1,123 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_MatchMode(t *testing.T) {
+	tests := []struct {
+		mode  string
+		attrs []any
+		emits bool
+	}{
+		{MatchPresent, []any{"trace_id", "t1"}, true},
+		{MatchPresent, []any{"trace_id", ""}, true},
+		{MatchPresent, nil, false},
+		{MatchAbsent, nil, true},
+		{MatchAbsent, []any{"trace_id", ""}, false},
+		{MatchEmpty, []any{"trace_id", ""}, true},
+		{MatchEmpty, []any{"trace_id", "t1"}, false},
+		{MatchEmpty, nil, false},
+		{MatchNonEmpty, []any{"trace_id", "t1"}, true},
+		{MatchNonEmpty, []any{"trace_id", ""}, false},
+		{MatchNonEmpty, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+			handler.SetFilters([]LogFilter{
+				{Type: "trace_id", MatchMode: tt.mode, Level: "debug", Enabled: true},
+			})
+
+			slog.New(handler).Debug("step", tt.attrs...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v for %s with attrs %v, got output: %s", tt.emits, tt.mode, tt.attrs, buf.String())
+			}
+		})
+	}
+}
+
+type tenantKey struct{}
+
+func TestHandler_MatchMode_ContextAndConditions(t *testing.T) {
+	defer ClearContextExtractors()
+	RegisterContextExtractor("tenant", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(tenantKey{}).(string)
+		return v, ok
+	})
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		// Debug for prod records that carry a request ID but no tenant.
+		{
+			Type: "env", Pattern: "prod", Level: "debug", Enabled: true,
+			Conditions: []Condition{
+				{Type: "request_id", MatchMode: MatchNonEmpty},
+				{Type: "context:tenant", MatchMode: MatchAbsent},
+			},
+		},
+	})
+	logger := slog.New(handler)
+	tenantCtx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		attrs []any
+		emits bool
+	}{
+		{"all conditions hold", context.Background(), []any{"env", "prod", "request_id", "r1"}, true},
+		{"tenant present", tenantCtx, []any{"env", "prod", "request_id", "r1"}, false},
+		{"request ID empty", context.Background(), []any{"env", "prod", "request_id", ""}, false},
+		{"wrong env", context.Background(), []any{"env", "dev", "request_id", "r1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.DebugContext(tt.ctx, "step", tt.attrs...)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestLogFilter_MatchMode_Validate(t *testing.T) {
+	f := LogFilter{Type: "trace_id", MatchMode: MatchPresent, Level: "debug"}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected MatchMode filter without pattern to be valid, got %v", err)
+	}
+	if f.IsMatchAll() {
+		t.Error("Expected MatchMode filter not to be a catch-all")
+	}
+
+	f.MatchMode = "exists"
+	if err := f.Validate(); !errors.Is(err, ErrUnknownMatchMode) {
+		t.Errorf("Expected ErrUnknownMatchMode, got %v", err)
+	}
+
+	f = LogFilter{Type: "env", Pattern: "prod", Conditions: []Condition{{Type: "trace_id", MatchMode: "maybe"}}}
+	if err := f.Validate(); !errors.Is(err, ErrUnknownMatchMode) {
+		t.Errorf("Expected ErrUnknownMatchMode for condition, got %v", err)
+	}
+
+	a := NewFilter("trace_id").MatchMode(MatchPresent).Build()
+	b := NewFilter("trace_id").MatchMode(MatchAbsent).Build()
+	if DeriveFilterID(a) == DeriveFilterID(b) {
+		t.Error("Expected MatchMode to contribute to the derived ID")
+	}
+}
@@ -0,0 +1,26 @@
+package logfilter
+
+import "sort"
+
+// sortByPriority returns filters ordered by descending Priority, keeping
+// insertion order among equal priorities. If filters is already in order it is
+// returned as-is; otherwise the result is a sorted copy, so that evaluations
+// holding the old slice are unaffected.
+func sortByPriority(filters []LogFilter) []LogFilter {
+	less := func(a, b *LogFilter) bool { return a.Priority > b.Priority }
+	sorted := true
+	for i := 1; i < len(filters); i++ {
+		if less(&filters[i], &filters[i-1]) {
+			sorted = false
+			break
+		}
+	}
+	if sorted {
+		return filters
+	}
+
+	out := make([]LogFilter, len(filters))
+	copy(out, filters)
+	sort.SliceStable(out, func(i, j int) bool { return less(&out[i], &out[j]) })
+	return out
+}
@@ -0,0 +1,105 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_PriorityWinsWhenAddedLater(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+	})
+	handler.AddFilter(LogFilter{Type: "job_id", Pattern: "job_1", Level: "error", Enabled: true, Priority: 10})
+	logger := slog.New(handler)
+
+	logger.Debug("step", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected later higher-priority filter to win, got: %s", buf.String())
+	}
+	logger.Debug("step", "job_id", "job_2")
+	if buf.Len() == 0 {
+		t.Error("Expected lower-priority filter to apply when the other doesn't match")
+	}
+}
+
+func TestHandler_PriorityOrder(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "a", Pattern: "1"},
+		{Type: "b", Pattern: "1", Priority: 5},
+		{Type: "c", Pattern: "1", Priority: -1},
+		{Type: "d", Pattern: "1"},
+		{Type: "e", Pattern: "1", Priority: 5},
+	})
+	handler.AddFilter(LogFilter{Type: "f", Pattern: "1"})
+	handler.AddFilter(LogFilter{Type: "g", Pattern: "1", Priority: 5})
+
+	// Evaluated by descending priority; ties keep insertion order
+	want := []string{"b", "e", "g", "a", "d", "f", "c"}
+	filters := handler.evalFilters
+	if len(filters) != len(want) {
+		t.Fatalf("Expected %d filters, got %d", len(want), len(filters))
+	}
+	for i, f := range filters {
+		if f.Type != want[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, want[i], f.Type)
+		}
+	}
+
+	// GetFilters keeps insertion order
+	want = []string{"a", "b", "c", "d", "e", "f", "g"}
+	for i, f := range handler.GetFilters() {
+		if f.Type != want[i] {
+			t.Errorf("GetFilters position %d: expected %s, got %s", i, want[i], f.Type)
+		}
+	}
+}
+
+func TestHandler_PriorityInGroupsAndBase(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaseFilters([]LogFilter{
+			{Type: "path", Pattern: "/health*", Level: "error", Enabled: true},
+			{Type: "path", Pattern: "/healthz", Level: "debug", Enabled: true, Priority: 1},
+		}))
+	handler.SetFilterGroups(map[string][]LogFilter{
+		"ops": {
+			{Type: "job_id", Pattern: "job_*", Level: "warn", Enabled: true},
+			{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true, Priority: 1},
+		},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("probe", "path", "/healthz")
+	if buf.Len() == 0 {
+		t.Error("Expected higher-priority base filter to win")
+	}
+	buf.Reset()
+	logger.Debug("step", "job_id", "job_1")
+	if buf.Len() == 0 {
+		t.Error("Expected higher-priority group filter to win")
+	}
+}
+
+func TestSortByPriority_NoCopyWhenSorted(t *testing.T) {
+	filters := []LogFilter{{Type: "a", Priority: 2}, {Type: "b", Priority: 2}, {Type: "c"}}
+	if got := sortByPriority(filters); &got[0] != &filters[0] {
+		t.Error("Expected an ordered list to be returned as-is")
+	}
+
+	unsorted := []LogFilter{{Type: "a"}, {Type: "b", Priority: 1}}
+	got := sortByPriority(unsorted)
+	if got[0].Type != "b" || unsorted[0].Type != "a" {
+		t.Errorf("Expected a sorted copy leaving the input unchanged, got %v / %v", got[0].Type, unsorted[0].Type)
+	}
+}
@@ -0,0 +1,115 @@
+// Package prommetrics provides a logfilter.MetricsCollector that is also a
+// prometheus.Collector, exporting the handler's per-record decisions:
+//
+//	collector := prommetrics.NewCollector()
+//	prometheus.MustRegister(collector)
+//	logger := logfilter.New(logfilter.WithMetrics(collector))
+//
+// It exports these counters:
+//
+//	logfilter_records_seen_total{level}              Records received by Handle
+//	logfilter_records_emitted_total{level,filter}    Records passed to the inner handler
+//	logfilter_records_suppressed_total{level,filter} Records dropped
+//	logfilter_records_elevated_total{level,filter}   Records below the global level a filter let through
+//
+// level is the record's original level and filter the deciding filter's
+// FilterID, or empty if the global level, a global suppression or baseline
+// sampling decided the record. Filter IDs are stable, but series are kept for
+// every filter that has decided a record, including filters since removed, so
+// with filters added and removed at runtime label cardinality grows with
+// every filter ever installed. Call ForgetFilter when removing a filter to
+// delete its series.
+//
+// This package lives in its own module so the core logfilter package stays
+// free of dependencies.
+package prommetrics
+
+import (
+	"log/slog"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector counts a logfilter.Handler's decisions as Prometheus metrics.
+// Pass it to logfilter.WithMetrics and register it with a
+// prometheus.Registerer.
+type Collector struct {
+	seen       *prometheus.CounterVec
+	emitted    *prometheus.CounterVec
+	suppressed *prometheus.CounterVec
+	elevated   *prometheus.CounterVec
+}
+
+var (
+	_ logfilter.MetricsCollector = (*Collector)(nil)
+	_ prometheus.Collector       = (*Collector)(nil)
+)
+
+// NewCollector returns a Collector with all counters at zero.
+func NewCollector() *Collector {
+	return &Collector{
+		seen: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logfilter_records_seen_total",
+			Help: "Records received by the log filter handler, by level.",
+		}, []string{"level"}),
+		emitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logfilter_records_emitted_total",
+			Help: "Records passed to the inner handler, by level and deciding filter.",
+		}, []string{"level", "filter"}),
+		suppressed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logfilter_records_suppressed_total",
+			Help: "Records dropped, by level and deciding filter.",
+		}, []string{"level", "filter"}),
+		elevated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logfilter_records_elevated_total",
+			Help: "Records below the global level emitted by a filter, by level and filter.",
+		}, []string{"level", "filter"}),
+	}
+}
+
+// RecordSeen implements logfilter.MetricsCollector.
+func (c *Collector) RecordSeen(level slog.Level) {
+	c.seen.WithLabelValues(level.String()).Inc()
+}
+
+// RecordEmitted implements logfilter.MetricsCollector.
+func (c *Collector) RecordEmitted(level slog.Level, filterID string) {
+	c.emitted.WithLabelValues(level.String(), filterID).Inc()
+}
+
+// RecordSuppressed implements logfilter.MetricsCollector.
+func (c *Collector) RecordSuppressed(level slog.Level, filterID string) {
+	c.suppressed.WithLabelValues(level.String(), filterID).Inc()
+}
+
+// RecordElevated implements logfilter.MetricsCollector.
+func (c *Collector) RecordElevated(level slog.Level, filterID string) {
+	c.elevated.WithLabelValues(level.String(), filterID).Inc()
+}
+
+// ForgetFilter deletes the series labelled with filterID, e.g. once the
+// filter has been removed from the handler, and reports how many it deleted.
+// A record the filter decides afterwards starts its series again from zero.
+func (c *Collector) ForgetFilter(filterID string) int {
+	labels := prometheus.Labels{"filter": filterID}
+	return c.emitted.DeletePartialMatch(labels) +
+		c.suppressed.DeletePartialMatch(labels) +
+		c.elevated.DeletePartialMatch(labels)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.seen.Describe(ch)
+	c.emitted.Describe(ch)
+	c.suppressed.Describe(ch)
+	c.elevated.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.seen.Collect(ch)
+	c.emitted.Collect(ch)
+	c.suppressed.Collect(ch)
+	c.elevated.Collect(ch)
+}
@@ -0,0 +1,86 @@
+package prommetrics
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	logfilter "github.com/jmylchreest/slog-logfilter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	collector := NewCollector()
+	inner := slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := logfilter.NewHandler(inner, level, logfilter.WithMetrics(collector))
+	handler.SetFilters([]logfilter.LogFilter{
+		{ID: "debug-jobs", Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("elevated", "job_id", "debug_1")
+	logger.Debug("elevated", "job_id", "debug_2")
+	logger.Debug("dropped", "job_id", "other")
+	logger.Info("emitted")
+
+	tests := []struct {
+		name   string
+		metric prometheus.Collector
+		want   float64
+	}{
+		{"seen debug", collector.seen.WithLabelValues("DEBUG"), 3},
+		{"seen info", collector.seen.WithLabelValues("INFO"), 1},
+		{"emitted by filter", collector.emitted.WithLabelValues("DEBUG", "debug-jobs"), 2},
+		{"elevated by filter", collector.elevated.WithLabelValues("DEBUG", "debug-jobs"), 2},
+		{"emitted by global level", collector.emitted.WithLabelValues("INFO", ""), 1},
+		{"suppressed by global level", collector.suppressed.WithLabelValues("DEBUG", ""), 1},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(tt.metric); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCollector_Register(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordSeen(slog.LevelInfo)
+	collector.RecordEmitted(slog.LevelInfo, "")
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Expected collector to register, got %v", err)
+	}
+
+	want := `
+# HELP logfilter_records_seen_total Records received by the log filter handler, by level.
+# TYPE logfilter_records_seen_total counter
+logfilter_records_seen_total{level="INFO"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "logfilter_records_seen_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollector_ForgetFilter(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordEmitted(slog.LevelDebug, "removed")
+	collector.RecordElevated(slog.LevelDebug, "removed")
+	collector.RecordSuppressed(slog.LevelInfo, "removed")
+	collector.RecordEmitted(slog.LevelDebug, "kept")
+
+	if n := collector.ForgetFilter("removed"); n != 3 {
+		t.Errorf("Expected 3 series deleted, got %d", n)
+	}
+	if n := testutil.CollectAndCount(collector, "logfilter_records_emitted_total"); n != 1 {
+		t.Errorf("Expected only the kept filter's emitted series, got %d", n)
+	}
+	if n := testutil.CollectAndCount(collector, "logfilter_records_elevated_total", "logfilter_records_suppressed_total"); n != 0 {
+		t.Errorf("Expected the removed filter's series gone, got %d", n)
+	}
+}
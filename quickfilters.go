@@ -0,0 +1,98 @@
+package logfilter
+
+import (
+	"log/slog"
+	"time"
+)
+
+// FilterOption adjusts a filter built by EnableDebugFor or SuppressTo.
+type FilterOption func(*quickFilter)
+
+// quickFilter is a filter under construction by EnableDebugFor or
+// SuppressTo. The expiry is relative until the filter is added, so it can be
+// resolved against the handler's clock.
+type quickFilter struct {
+	filter    LogFilter
+	expiresIn time.Duration
+}
+
+// WithExpiry makes the filter expire d after it is added, according to the
+// handler's clock.
+func WithExpiry(d time.Duration) FilterOption {
+	return func(q *quickFilter) {
+		q.expiresIn = d
+	}
+}
+
+// WithOutputLevel emits matching records at level (see LogFilter.OutputLevel).
+func WithOutputLevel(level slog.Level) FilterOption {
+	return func(q *quickFilter) {
+		q.filter.OutputLevel = LevelToString(level)
+	}
+}
+
+// WithName sets the filter's Name, so it can be managed by name later, e.g.
+// with RemoveFilterByName.
+func WithName(name string) FilterOption {
+	return func(q *quickFilter) {
+		q.filter.Name = name
+	}
+}
+
+// EnableDebugFor adds a filter emitting debug records whose attrKey
+// attribute matches pattern, e.g. EnableDebugFor("job_id", "job_42",
+// WithExpiry(10*time.Minute)). It is shorthand for AddFilter with an enabled
+// debug-level filter.
+func (h *Handler) EnableDebugFor(attrKey, pattern string, opts ...FilterOption) {
+	h.addQuickFilter(slog.LevelDebug, attrKey, pattern, opts)
+}
+
+// SuppressTo adds a filter dropping records below level whose attrKey
+// attribute matches pattern, e.g. SuppressTo(slog.LevelWarn, "component",
+// "healthcheck") to keep only warnings and errors from a noisy component. It
+// is shorthand for AddFilter with an enabled filter at level.
+func (h *Handler) SuppressTo(level slog.Level, attrKey, pattern string, opts ...FilterOption) {
+	h.addQuickFilter(level, attrKey, pattern, opts)
+}
+
+// addQuickFilter builds a filter at level from opts and adds it.
+func (h *Handler) addQuickFilter(level slog.Level, attrKey, pattern string, opts []FilterOption) {
+	q := quickFilter{filter: LogFilter{
+		Type:    attrKey,
+		Pattern: pattern,
+		Level:   LevelToString(level),
+		Enabled: true,
+	}}
+	for _, opt := range opts {
+		opt(&q)
+	}
+	if q.expiresIn > 0 {
+		t := h.now().Add(q.expiresIn)
+		q.filter.ExpiresAt = &t
+	}
+	h.AddFilter(q.filter)
+}
+
+// EnableDebugFor adds a debug filter to the global handler (see
+// Handler.EnableDebugFor).
+func EnableDebugFor(attrKey, pattern string, opts ...FilterOption) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.EnableDebugFor(attrKey, pattern, opts...)
+	}
+}
+
+// SuppressTo adds a suppressing filter to the global handler (see
+// Handler.SuppressTo).
+func SuppressTo(level slog.Level, attrKey, pattern string, opts ...FilterOption) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.SuppressTo(level, attrKey, pattern, opts...)
+	}
+}
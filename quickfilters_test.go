@@ -0,0 +1,106 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_EnableDebugFor(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithClock(clock))
+
+	handler.EnableDebugFor("job_id", "job_42",
+		WithExpiry(10*time.Minute),
+		WithOutputLevel(slog.LevelInfo),
+		WithName("debug-job-42"),
+	)
+
+	filters := handler.GetFilters()
+	if len(filters) != 1 {
+		t.Fatalf("Expected 1 filter, got %d", len(filters))
+	}
+	f := filters[0]
+	if f.Type != "job_id" || f.Pattern != "job_42" || f.Level != "debug" || !f.Enabled {
+		t.Errorf("Expected enabled debug filter on job_id=job_42, got %+v", f)
+	}
+	if f.OutputLevel != "info" {
+		t.Errorf("Expected output level info, got %q", f.OutputLevel)
+	}
+	if f.Name != "debug-job-42" {
+		t.Errorf("Expected name debug-job-42, got %q", f.Name)
+	}
+	if want := clock.Now().Add(10 * time.Minute); f.ExpiresAt == nil || !f.ExpiresAt.Equal(want) {
+		t.Errorf("Expected expiry at %v, got %v", want, f.ExpiresAt)
+	}
+}
+
+func TestHandler_SuppressTo(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	handler.SuppressTo(slog.LevelWarn, "component", "healthcheck")
+
+	filters := handler.GetFilters()
+	if len(filters) != 1 {
+		t.Fatalf("Expected 1 filter, got %d", len(filters))
+	}
+	f := filters[0]
+	if f.Type != "component" || f.Pattern != "healthcheck" || f.Level != "warn" || !f.Enabled {
+		t.Errorf("Expected enabled warn filter on component=healthcheck, got %+v", f)
+	}
+	if f.ExpiresAt != nil || f.OutputLevel != "" || f.Name != "" {
+		t.Errorf("Expected no expiry, output level or name without options, got %+v", f)
+	}
+}
+
+func TestHandler_QuickFilters_Emit(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.EnableDebugFor("job_id", "job_4*")
+	handler.SuppressTo(slog.LevelWarn, "job_id", "*")
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name  string
+		log   func()
+		want  string
+		emits bool
+	}{
+		{"debug for matching job", func() { logger.Debug("job debug", "job_id", "job_42") }, "job debug", true},
+		{"info for other job suppressed", func() { logger.Info("other info", "job_id", "job_7") }, "other info", false},
+		{"warn for other job", func() { logger.Warn("other warn", "job_id", "job_7") }, "other warn", true},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		tt.log()
+		if got := strings.Contains(buf.String(), tt.want); got != tt.emits {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.emits, buf.String())
+		}
+	}
+}
+
+func TestQuickFilters_Global(t *testing.T) {
+	_ = New()
+
+	EnableDebugFor("job_id", "job_42", WithName("global-debug"))
+	SuppressTo(slog.LevelError, "component", "db")
+
+	filters := GetFilters()
+	if len(filters) != 2 {
+		t.Fatalf("Expected 2 filters, got %d", len(filters))
+	}
+	if filters[0].Name != "global-debug" || filters[0].Level != "debug" {
+		t.Errorf("Expected named debug filter first, got %+v", filters[0])
+	}
+	if filters[1].Type != "component" || filters[1].Level != "error" {
+		t.Errorf("Expected component error filter second, got %+v", filters[1])
+	}
+}
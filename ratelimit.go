@@ -0,0 +1,39 @@
+package logfilter
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket for LogFilter.MaxPerSecond. The bucket holds
+// up to one second's worth of tokens and starts full, so a filter that has
+// been quiet may emit a burst of MaxPerSecond records at once.
+type rateLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time // Time of the last refill; zero until first use
+}
+
+// allow reports whether a record at time now may be emitted at the given
+// rate, taking a token if so. Times earlier than the last seen refill no
+// tokens, so out-of-order records can't mint extra ones.
+func (l *rateLimiter) allow(now time.Time, perSecond int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := float64(perSecond)
+	if l.last.IsZero() {
+		l.tokens = limit
+		l.last = now
+	} else if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(limit, l.tokens+elapsed.Seconds()*limit)
+		l.last = now
+	}
+	l.tokens = min(limit, l.tokens) // The limit may have been lowered
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,107 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_MaxPerSecond(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level, WithClock(clock))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true, MaxPerSecond: 3},
+	})
+
+	// Records without a time are limited by the handler's clock
+	emitN := func(n int, jobID string) int {
+		buf.Reset()
+		for i := 0; i < n; i++ {
+			r := slog.NewRecord(time.Time{}, slog.LevelDebug, "step", 0)
+			r.AddAttrs(slog.String("job_id", jobID))
+			if err := handler.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return strings.Count(buf.String(), "msg=step")
+	}
+
+	if got := emitN(10, "job_1"); got != 3 {
+		t.Errorf("Expected a burst of 3 in the first window, got %d", got)
+	}
+	if got := emitN(10, "job_2"); got != 0 {
+		t.Errorf("Expected the limit to apply across values, got %d", got)
+	}
+
+	clock.Advance(time.Second)
+	if got := emitN(10, "job_1"); got != 3 {
+		t.Errorf("Expected 3 after a second, got %d", got)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if got := emitN(10, "job_1"); got != 1 {
+		t.Errorf("Expected 1 after half a second, got %d", got)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if got := emitN(10, "job_1"); got != 2 {
+		t.Errorf("Expected the leftover half token to carry over, got %d", got)
+	}
+
+	// A long quiet spell refills the bucket only up to one second's worth
+	clock.Advance(time.Minute)
+	if got := emitN(10, "job_1"); got != 3 {
+		t.Errorf("Expected a burst capped at 3, got %d", got)
+	}
+
+	// Limited records are suppressed, like records below the filter level
+	if stats := handler.LevelStats()[slog.LevelDebug]; stats.Emitted != 12 || stats.Suppressed != 48 {
+		t.Errorf("Expected 12 emitted and 48 suppressed, got %+v", stats)
+	}
+}
+
+func TestHandler_MaxPerSecond_RecordTime(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true, MaxPerSecond: 1},
+		{Type: "user_id", Pattern: "*", Level: "debug", Enabled: true},
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	emit := func(offset time.Duration, key, value, msg string) {
+		r := slog.NewRecord(start.Add(offset), slog.LevelDebug, msg, 0)
+		r.AddAttrs(slog.String(key, value))
+		if err := handler.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	emit(0, "job_id", "job_1", "a1")                     // Emitted
+	emit(100*time.Millisecond, "job_id", "job_1", "a2")  // Limited
+	emit(100*time.Millisecond, "user_id", "u1", "u1")    // Other filter: not limited
+	emit(50*time.Millisecond, "job_id", "job_1", "a3")   // Out of order: limited
+	emit(1100*time.Millisecond, "job_id", "job_1", "a4") // Emitted: a second after a1
+	emit(1200*time.Millisecond, "user_id", "u1", "u2")   // Other filter: not limited
+
+	out := buf.String()
+	for _, msg := range []string{"a1", "u1", "a4", "u2"} {
+		if !strings.Contains(out, "msg="+msg) {
+			t.Errorf("Expected %s to be emitted, got: %s", msg, out)
+		}
+	}
+	for _, msg := range []string{"a2", "a3"} {
+		if strings.Contains(out, "msg="+msg) {
+			t.Errorf("Expected %s to be limited, got: %s", msg, out)
+		}
+	}
+}
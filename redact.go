@@ -0,0 +1,107 @@
+package logfilter
+
+import "log/slog"
+
+// RedactedValue replaces the values of attributes matched by WithRedactKeys.
+const RedactedValue = "[REDACTED]"
+
+// WithRedactKeys replaces the value of every attribute whose key matches one
+// of keys with RedactedValue before it reaches any output: the inner handler,
+// routes, the firehose and capture buffers. Keys are glob patterns as for
+// LogFilter.Pattern, e.g. "password" or "*_token", matched case-sensitively
+// against each attribute's own key at any depth of groups. Attributes added
+// via With are redacted too.
+//
+// Redaction happens before filtering, so filters on a redacted key see
+// RedactedValue.
+func WithRedactKeys(keys ...string) Option {
+	return func(o *options) {
+		o.redactKeys = append(o.redactKeys, keys...)
+	}
+}
+
+// redactor rewrites attributes whose keys match any of its patterns.
+type redactor struct {
+	keys []Matcher
+}
+
+// newRedactor compiles key patterns, returning nil if there are none.
+func newRedactor(keys []string) *redactor {
+	if len(keys) == 0 {
+		return nil
+	}
+	rd := &redactor{keys: make([]Matcher, len(keys))}
+	for i, k := range keys {
+		rd.keys[i] = NewMatcher(k)
+	}
+	return rd
+}
+
+// matches reports whether key is to be redacted.
+func (rd *redactor) matches(key string) bool {
+	for _, m := range rd.keys {
+		if m.Match(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// attr returns a with redacted values, and whether anything was redacted.
+// Group values, including those produced by a LogValuer, are redacted
+// recursively.
+func (rd *redactor) attr(a slog.Attr) (slog.Attr, bool) {
+	if a.Key != "" && rd.matches(a.Key) {
+		return slog.String(a.Key, RedactedValue), true
+	}
+
+	v := a.Value
+	if v.Kind() == slog.KindLogValuer {
+		v = v.Resolve()
+	}
+	if v.Kind() != slog.KindGroup {
+		return a, false
+	}
+	members, changed := rd.attrs(v.Group())
+	if !changed {
+		return a, false
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(members...)}, true
+}
+
+// attrs returns attrs with redacted values, and whether anything was
+// redacted. The input slice is not modified.
+func (rd *redactor) attrs(attrs []slog.Attr) ([]slog.Attr, bool) {
+	var out []slog.Attr
+	for i, a := range attrs {
+		ra, changed := rd.attr(a)
+		if changed && out == nil {
+			out = make([]slog.Attr, len(attrs))
+			copy(out, attrs[:i])
+		}
+		if out != nil {
+			out[i] = ra
+		}
+	}
+	if out == nil {
+		return attrs, false
+	}
+	return out, true
+}
+
+// record returns r with redacted attribute values. If nothing is redacted, r
+// itself is returned.
+func (rd *redactor) record(r slog.Record) slog.Record {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	attrs, changed := rd.attrs(attrs)
+	if !changed {
+		return r
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	return nr
+}
@@ -0,0 +1,111 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// secretValuer logs as a group holding a secret.
+type secretValuer struct{}
+
+func (secretValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", "alice"), slog.String("password", "valuer-secret"))
+}
+
+func TestHandler_RedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithRedactKeys("password", "*_token"))
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name   string
+		log    func()
+		secret string
+		want   []string
+	}{
+		{"exact key", func() { logger.Info("login", "user", "alice", "password", "hunter2") },
+			"hunter2", []string{"user=alice", "password=" + RedactedValue}},
+		{"glob key", func() { logger.Info("call", "api_token", "tok-123", "refresh_token", "tok-456") },
+			"tok-", []string{"api_token=" + RedactedValue, "refresh_token=" + RedactedValue}},
+		{"nested group", func() {
+			logger.Info("req", slog.Group("auth", slog.String("password", "grp-secret"), slog.String("scheme", "basic")))
+		},
+			"grp-secret", []string{"auth.password=" + RedactedValue, "auth.scheme=basic"}},
+		{"log valuer", func() { logger.Info("req", "creds", secretValuer{}) },
+			"valuer-secret", []string{"creds.user=alice", "creds.password=" + RedactedValue}},
+		{"with attrs", func() { logger.With("session_token", "with-secret").Info("step") },
+			"with-secret", []string{"session_token=" + RedactedValue}},
+		{"with group", func() { logger.WithGroup("db").Info("connect", "password", "db-secret") },
+			"db-secret", []string{"db.password=" + RedactedValue}},
+		{"no match passes unchanged", func() { logger.Info("plain", "token_count", 3) },
+			"", []string{"token_count=3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log()
+			out := buf.String()
+			if tt.secret != "" && strings.Contains(out, tt.secret) {
+				t.Errorf("Expected %q to be redacted, got: %s", tt.secret, out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected output to contain %q, got: %s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_RedactKeys_AllOutputs(t *testing.T) {
+	var main, routed, firehose bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&main, nil), level,
+		WithRedactKeys("password"), WithRouteOnMatch("debug", &routed))
+	handler.SetFirehose(&firehose, slog.LevelDebug)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", Enabled: true, Route: "debug"},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("routed", "job_id", "job_1", "password", "secret-1")
+	logger.Info("main", "password", "secret-2")
+	logger.Debug("suppressed", "password", "secret-3")
+
+	for name, out := range map[string]string{"main": main.String(), "routed": routed.String(), "firehose": firehose.String()} {
+		if strings.Contains(out, "secret-") {
+			t.Errorf("Expected %s output to be redacted, got: %s", name, out)
+		}
+	}
+	if !strings.Contains(firehose.String(), "suppressed") || !strings.Contains(routed.String(), "routed") {
+		t.Errorf("Expected records in firehose and route, got firehose=%q routed=%q", firehose.String(), routed.String())
+	}
+}
+
+func TestRedactor_AttrsUnchanged(t *testing.T) {
+	rd := newRedactor([]string{"secret"})
+	attrs := []slog.Attr{slog.String("a", "1"), slog.String("secret", "x")}
+	out, changed := rd.attrs(attrs)
+	if !changed || out[1].Value.String() != RedactedValue {
+		t.Errorf("Expected secret redacted, got %v", out)
+	}
+	if attrs[1].Value.String() != "x" {
+		t.Errorf("Expected input slice unmodified, got %v", attrs)
+	}
+
+	plain := []slog.Attr{slog.String("a", "1")}
+	if out, changed := rd.attrs(plain); changed || &out[0] != &plain[0] {
+		t.Error("Expected attrs without matches returned as-is")
+	}
+	if newRedactor(nil) != nil {
+		t.Error("Expected nil redactor without keys")
+	}
+}
@@ -0,0 +1,56 @@
+package logfilter
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithRetry retries a record the inner handler (or a route's handler) fails
+// to handle, for destinations such as network sinks with transient errors.
+// Each record is handled up to attempts times in all, waiting backoff before
+// the first retry and doubling the wait before each further one; Handle
+// returns the last error if every attempt fails. Attempts of 1 or less
+// disable retries, the default.
+//
+// Delivery is at-least-once: a handler that wrote part or all of a record
+// before returning an error is handed the whole record again, so its output
+// may contain the record more than once. A tee route retries each destination
+// separately, so one that succeeded isn't written again when the other fails.
+// Retries stop early if the record's context is done.
+//
+// The WithErrorHandler callback sees only the final error. Retries wait
+// synchronously in Handle, delaying the caller and Close; records flushed by
+// capture-on-error or FlushSuppressed are not retried.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retryAttempts = attempts
+		o.retryBackoff = max(backoff, 0)
+	}
+}
+
+// handleWithRetry passes r to handler, retrying failures as configured by
+// WithRetry.
+func (h *Handler) handleWithRetry(ctx context.Context, handler slog.Handler, r slog.Record) error {
+	err := handler.Handle(ctx, r)
+	if err != nil && ctx == nil {
+		ctx = context.Background()
+	}
+	wait := h.retryBackoff
+	for attempt := 1; err != nil && attempt < h.retryAttempts; attempt++ {
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			wait *= 2
+		} else if ctx.Err() != nil {
+			return err
+		}
+		err = handler.Handle(ctx, r)
+	}
+	return err
+}
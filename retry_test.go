@@ -0,0 +1,148 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyHandler fails the first failures calls to Handle, then delegates.
+type flakyHandler struct {
+	slog.Handler
+	failures int
+	calls    *atomic.Int64
+}
+
+var errFlaky = errors.New("transient")
+
+func (f flakyHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f.calls.Add(1) <= int64(f.failures) {
+		return errFlaky
+	}
+	return f.Handler.Handle(ctx, r)
+}
+
+func TestHandler_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		failures  int
+		wantCalls int64
+		wantErr   bool
+	}{
+		{"no retry by default", nil, 1, 1, true},
+		{"succeeds first time", []Option{WithRetry(3, 0)}, 0, 1, false},
+		{"recovers on retry", []Option{WithRetry(3, time.Millisecond)}, 2, 3, false},
+		{"gives up after attempts", []Option{WithRetry(3, 0)}, 5, 3, true},
+		{"one attempt disables", []Option{WithRetry(1, time.Millisecond)}, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		level := new(slog.LevelVar)
+		level.Set(slog.LevelInfo)
+
+		calls := new(atomic.Int64)
+		inner := flakyHandler{Handler: slog.NewTextHandler(&buf, nil), failures: tt.failures, calls: calls}
+		var reported []error
+		opts := append(tt.opts, WithErrorHandler(func(err error) { reported = append(reported, err) }))
+		handler := NewHandler(inner, level, opts...)
+
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "sent", 0)
+		err := handler.Handle(context.Background(), r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+		if tt.wantErr && (len(reported) != 1 || !errors.Is(reported[0], errFlaky)) {
+			t.Errorf("%s: expected the final error reported once, got %v", tt.name, reported)
+		}
+		if got := calls.Load(); got != tt.wantCalls {
+			t.Errorf("%s: expected %d calls to the inner handler, got %d", tt.name, tt.wantCalls, got)
+		}
+		written := strings.Contains(buf.String(), "sent")
+		if written == tt.wantErr || strings.Count(buf.String(), "sent") > 1 {
+			t.Errorf("%s: expected the record written once on success, got: %s", tt.name, buf.String())
+		}
+	}
+}
+
+func TestHandler_Retry_Backoff(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	calls := new(atomic.Int64)
+	inner := flakyHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), failures: 3, calls: calls}
+	handler := NewHandler(inner, level, WithRetry(4, 10*time.Millisecond))
+
+	start := time.Now()
+	if err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "sent", 0)); err != nil {
+		t.Fatalf("Expected the fourth attempt to succeed, got %v", err)
+	}
+	// 10ms + 20ms + 40ms between the four attempts.
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Expected doubling backoff of at least 70ms, got %v", elapsed)
+	}
+}
+
+func TestHandler_Retry_ContextDone(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	calls := new(atomic.Int64)
+	inner := flakyHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), failures: 10, calls: calls}
+	handler := NewHandler(inner, level, WithRetry(10, time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "sent", 0)); !errors.Is(err, errFlaky) {
+		t.Errorf("Expected the last handler error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected no retries once the context is done, got %d calls", got)
+	}
+}
+
+func TestHandler_Retry_TeeRetriesFailingDestinationOnly(t *testing.T) {
+	var innerBuf, routeBuf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	routeCalls := new(atomic.Int64)
+	route := flakyHandler{Handler: slog.NewTextHandler(&routeBuf, nil), failures: 1, calls: routeCalls}
+	handler := NewHandler(slog.NewTextHandler(&innerBuf, nil), level,
+		WithRouteHandler("audit", route),
+		WithRetry(2, 0),
+	)
+	handler.SetFilters([]LogFilter{
+		{Type: "user", Pattern: "alice", Level: "info", Route: "audit", RouteTee: true, Enabled: true},
+	})
+
+	slog.New(handler).Info("login", "user", "alice")
+	if got := strings.Count(innerBuf.String(), "login"); got != 1 {
+		t.Errorf("Expected the inner handler to get the record once, got %d", got)
+	}
+	if got := strings.Count(routeBuf.String(), "login"); got != 1 || routeCalls.Load() != 2 {
+		t.Errorf("Expected the route retried to one record, got %d records in %d calls", got, routeCalls.Load())
+	}
+}
+
+func TestHandler_Retry_NilContext(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	for _, backoff := range []time.Duration{0, time.Millisecond} {
+		calls := new(atomic.Int64)
+		inner := flakyHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil), failures: 10, calls: calls}
+		handler := NewHandler(inner, level, WithRetry(3, backoff))
+
+		if err := handler.Handle(nil, slog.NewRecord(time.Now(), slog.LevelInfo, "sent", 0)); !errors.Is(err, errFlaky) {
+			t.Errorf("backoff %v: expected the last handler error, got %v", backoff, err)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("backoff %v: expected 3 attempts, got %d", backoff, got)
+		}
+	}
+}
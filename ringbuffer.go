@@ -0,0 +1,221 @@
+package logfilter
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// RingBufferWriter is a bounded, thread-safe io.Writer that retains only the
+// most recent writes. Each call to Write is stored as one entry, so when used
+// as the output of an slog handler (e.g. via SetFirehose) it holds the last N
+// formatted records.
+type RingBufferWriter struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int  // Index of the slot the next write goes to
+	full    bool // True once the buffer has wrapped
+}
+
+// NewRingBufferWriter creates a RingBufferWriter that retains up to size entries.
+// A size below 1 is treated as 1.
+func NewRingBufferWriter(size int) *RingBufferWriter {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBufferWriter{entries: make([][]byte, size)}
+}
+
+// Write stores a copy of p as a new entry, evicting the oldest entry when full.
+func (w *RingBufferWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries[w.next] = entry
+	w.next = (w.next + 1) % len(w.entries)
+	if w.next == 0 {
+		w.full = true
+	}
+	return len(p), nil
+}
+
+// Entries returns the retained entries, oldest first.
+func (w *RingBufferWriter) Entries() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.full {
+		out := make([][]byte, w.next)
+		copy(out, w.entries[:w.next])
+		return out
+	}
+	out := make([][]byte, 0, len(w.entries))
+	out = append(out, w.entries[w.next:]...)
+	out = append(out, w.entries[:w.next]...)
+	return out
+}
+
+// Len returns the number of retained entries.
+func (w *RingBufferWriter) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.full {
+		return len(w.entries)
+	}
+	return w.next
+}
+
+// Reset discards all retained entries.
+func (w *RingBufferWriter) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	clear(w.entries)
+	w.next = 0
+	w.full = false
+}
+
+// RingBufferHandler is an slog.Handler that retains the most recent records in
+// memory instead of writing them anywhere. Paired with a Handler via
+// SetFirehoseHandler, it keeps recent debug context available for retrieval
+// after an error, even when those records were suppressed from the main output.
+//
+// Handlers derived via WithAttrs and WithGroup share the same buffer; their
+// attributes and groups are folded into the stored records.
+type RingBufferHandler struct {
+	buf   *recordRing
+	level slog.Leveler
+	scope []scopeOp
+}
+
+// recordRing is the bounded record storage shared by derived RingBufferHandlers.
+type recordRing struct {
+	mu      sync.Mutex
+	records []slog.Record
+	next    int
+	full    bool
+}
+
+// NewRingBufferHandler creates a RingBufferHandler that retains up to size
+// records at or above level. A nil level retains records of every level.
+// A size below 1 is treated as 1.
+func NewRingBufferHandler(size int, level slog.Leveler) *RingBufferHandler {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBufferHandler{
+		buf:   &recordRing{records: make([]slog.Record, size)},
+		level: level,
+	}
+}
+
+// Enabled reports whether records at the given level are retained.
+func (h *RingBufferHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.level == nil || level >= h.level.Level()
+}
+
+// Handle stores a copy of the record, evicting the oldest record when full.
+func (h *RingBufferHandler) Handle(_ context.Context, r slog.Record) error {
+	var attrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	// Fold the scope in from the innermost call outwards.
+	for i := len(h.scope) - 1; i >= 0; i-- {
+		op := h.scope[i]
+		if op.group != "" {
+			if len(attrs) > 0 {
+				attrs = []slog.Attr{{Key: op.group, Value: slog.GroupValue(attrs...)}}
+			}
+			continue
+		}
+		attrs = append(append(make([]slog.Attr, 0, len(op.attrs)+len(attrs)), op.attrs...), attrs...)
+	}
+
+	rec := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	rec.AddAttrs(attrs...)
+
+	b := h.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records[b.next] = rec
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+	return nil
+}
+
+// WithAttrs returns a handler sharing this buffer that adds attrs to stored records.
+func (h *RingBufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.derive(scopeOp{attrs: attrs})
+}
+
+// WithGroup returns a handler sharing this buffer that nests attributes under name.
+func (h *RingBufferHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.derive(scopeOp{group: name})
+}
+
+// derive returns a handler sharing this buffer with op appended to its scope.
+func (h *RingBufferHandler) derive(op scopeOp) *RingBufferHandler {
+	scope := make([]scopeOp, len(h.scope), len(h.scope)+1)
+	copy(scope, h.scope)
+	return &RingBufferHandler{buf: h.buf, level: h.level, scope: append(scope, op)}
+}
+
+// Dump returns the retained records, oldest first. The records are clones and
+// may be freely modified or replayed through another handler.
+func (h *RingBufferHandler) Dump() []slog.Record {
+	b := h.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var ordered []slog.Record
+	if b.full {
+		ordered = append(append(ordered, b.records[b.next:]...), b.records[:b.next]...)
+	} else {
+		ordered = b.records[:b.next]
+	}
+
+	out := make([]slog.Record, len(ordered))
+	for i, r := range ordered {
+		out[i] = r.Clone()
+	}
+	return out
+}
+
+// Len returns the number of retained records.
+func (h *RingBufferHandler) Len() int {
+	b := h.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.full {
+		return len(b.records)
+	}
+	return b.next
+}
+
+// Reset discards all retained records.
+func (h *RingBufferHandler) Reset() {
+	b := h.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.records)
+	b.next = 0
+	b.full = false
+}
@@ -0,0 +1,184 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRingBufferWriter_Wraps(t *testing.T) {
+	w := NewRingBufferWriter(3)
+
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(w, "entry%d", i)
+	}
+
+	if w.Len() != 3 {
+		t.Errorf("Expected 3 entries, got %d", w.Len())
+	}
+
+	entries := w.Entries()
+	want := []string{"entry3", "entry4", "entry5"}
+	for i, e := range entries {
+		if string(e) != want[i] {
+			t.Errorf("Entry %d: expected %q, got %q", i, want[i], e)
+		}
+	}
+}
+
+func TestRingBufferWriter_PartiallyFilled(t *testing.T) {
+	w := NewRingBufferWriter(5)
+	fmt.Fprint(w, "a")
+	fmt.Fprint(w, "b")
+
+	entries := w.Entries()
+	if len(entries) != 2 || string(entries[0]) != "a" || string(entries[1]) != "b" {
+		t.Errorf("Expected [a b], got %q", entries)
+	}
+}
+
+func TestRingBufferWriter_CopiesInput(t *testing.T) {
+	w := NewRingBufferWriter(2)
+	p := []byte("original")
+	w.Write(p)
+	copy(p, "mutated!")
+
+	if got := string(w.Entries()[0]); got != "original" {
+		t.Errorf("Expected stored entry to be unaffected by caller mutation, got %q", got)
+	}
+}
+
+func TestRingBufferWriter_Reset(t *testing.T) {
+	w := NewRingBufferWriter(2)
+	fmt.Fprint(w, "a")
+	fmt.Fprint(w, "b")
+	fmt.Fprint(w, "c")
+	w.Reset()
+
+	if w.Len() != 0 || len(w.Entries()) != 0 {
+		t.Error("Expected buffer to be empty after reset")
+	}
+}
+
+func TestRingBufferWriter_MinimumSize(t *testing.T) {
+	w := NewRingBufferWriter(0)
+	fmt.Fprint(w, "a")
+	fmt.Fprint(w, "b")
+
+	if entries := w.Entries(); len(entries) != 1 || string(entries[0]) != "b" {
+		t.Errorf("Expected size to clamp to 1, got %q", entries)
+	}
+}
+
+func TestRingBufferWriter_Concurrent(t *testing.T) {
+	w := NewRingBufferWriter(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fmt.Fprint(w, "x")
+				_ = w.Entries()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if w.Len() != 10 {
+		t.Errorf("Expected buffer to be full, got %d", w.Len())
+	}
+}
+
+func TestRingBufferHandler_RetainsLastN(t *testing.T) {
+	h := NewRingBufferHandler(2, nil)
+	logger := slog.New(h)
+
+	logger.Debug("one")
+	logger.Info("two")
+	logger.Warn("three")
+
+	records := h.Dump()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0].Message != "two" || records[1].Message != "three" {
+		t.Errorf("Expected [two three], got [%s %s]", records[0].Message, records[1].Message)
+	}
+	if h.Len() != 2 {
+		t.Errorf("Expected Len 2, got %d", h.Len())
+	}
+}
+
+func TestRingBufferHandler_Level(t *testing.T) {
+	h := NewRingBufferHandler(10, slog.LevelInfo)
+	logger := slog.New(h)
+
+	logger.Debug("skipped")
+	logger.Info("kept")
+
+	records := h.Dump()
+	if len(records) != 1 || records[0].Message != "kept" {
+		t.Errorf("Expected only the info record, got %d records", len(records))
+	}
+}
+
+func TestRingBufferHandler_FoldsScope(t *testing.T) {
+	h := NewRingBufferHandler(10, nil)
+	logger := slog.New(h).With("service", "api").WithGroup("req").With("id", "r1")
+
+	logger.Info("handled", "status", 200)
+
+	// Derived handlers share the buffer
+	records := h.Dump()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	var buf bytes.Buffer
+	if err := slog.NewTextHandler(&buf, nil).Handle(context.Background(), records[0]); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"service=api", "req.id=r1", "req.status=200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in replayed record, got: %s", want, out)
+		}
+	}
+}
+
+func TestRingBufferHandler_WithFilterHandler(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	ring := NewRingBufferHandler(10, nil)
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+	handler.SetFirehoseHandler(ring, slog.LevelDebug)
+
+	logger := slog.New(handler)
+	logger.Debug("lead-up")
+	logger.Error("failure")
+
+	if strings.Contains(buf.String(), "lead-up") {
+		t.Error("Expected debug record to be suppressed from main output")
+	}
+	records := ring.Dump()
+	if len(records) != 2 || records[0].Message != "lead-up" {
+		t.Errorf("Expected ring buffer to retain suppressed debug record, got %d records", len(records))
+	}
+}
+
+func TestRingBufferHandler_Reset(t *testing.T) {
+	h := NewRingBufferHandler(2, nil)
+	slog.New(h).Info("a")
+	h.Reset()
+
+	if h.Len() != 0 || len(h.Dump()) != 0 {
+		t.Error("Expected handler to be empty after reset")
+	}
+}
@@ -0,0 +1,122 @@
+package logfilter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// WithRouteOnMatch registers a named route writing JSON to w. Records emitted
+// through a filter whose Route is name go to w instead of the inner handler
+// (or, with RouteTee, to both), e.g. to collect elevated debug output in a
+// separate file while normal logs stay on stdout.
+func WithRouteOnMatch(name string, w io.Writer) Option {
+	return WithRouteHandler(name, slog.NewJSONHandler(w, nil))
+}
+
+// WithRouteHandler is like WithRouteOnMatch but routes to an arbitrary
+// handler. The handler receives records as the inner handler would, with the
+// logger's attributes and groups and any OutputLevel applied.
+func WithRouteHandler(name string, handler slog.Handler) Option {
+	return func(o *options) {
+		if o.routes == nil {
+			o.routes = make(map[string]slog.Handler)
+		}
+		o.routes[name] = handler
+	}
+}
+
+// route is a named destination for records emitted through filters with a
+// matching Route. A Handler's own routes are fixed when it is created; named
+// handlers can be registered at any time.
+type route struct {
+	handler slog.Handler
+}
+
+var (
+	// namedHandlers holds the routes registered with RegisterNamedHandler.
+	namedHandlers     = make(map[string]*route)
+	namedHandlersLock sync.RWMutex
+)
+
+// RegisterNamedHandler makes handler a route available to every Handler
+// under name, so filters can pick an output format or destination at
+// runtime: a filter whose Route is name emits matching records through
+// handler, e.g. compact JSON for one job's elevated debug lines while the
+// rest stay text. A route configured on the Handler with WithRouteOnMatch or
+// WithRouteHandler takes precedence over a named handler of the same name.
+// Registering a name again replaces its handler. RouteTee works as for other
+// routes.
+//
+// Example:
+//
+//	logfilter.RegisterNamedHandler("json", slog.NewJSONHandler(os.Stdout, nil))
+//	logfilter.AddFilter(logfilter.LogFilter{
+//	    Type: "job_id", Pattern: "job_42", Level: "debug", Route: "json", Enabled: true,
+//	})
+func RegisterNamedHandler(name string, handler slog.Handler) {
+	namedHandlersLock.Lock()
+	defer namedHandlersLock.Unlock()
+	namedHandlers[name] = &route{handler: handler}
+}
+
+// UnregisterNamedHandler removes the handler registered under name. Filters
+// routing to it emit through the inner handler again.
+func UnregisterNamedHandler(name string) {
+	namedHandlersLock.Lock()
+	defer namedHandlersLock.Unlock()
+	delete(namedHandlers, name)
+}
+
+// namedRoute returns the route registered under name, or nil.
+func namedRoute(name string) *route {
+	namedHandlersLock.RLock()
+	defer namedHandlersLock.RUnlock()
+	return namedHandlers[name]
+}
+
+// routed returns the handler, with this Handler's scope applied, for records
+// emitted through f, or nil if f has no route or names an unknown one.
+//
+// Scoped route handlers are cached by name, keyed by the route they were
+// built from, so a name registered again with RegisterNamedHandler replaces
+// its entry rather than keeping the old handler alive beside the new one.
+func (h *Handler) routed(f *LogFilter) slog.Handler {
+	if f == nil || f.Route == "" {
+		return nil
+	}
+	rt := h.routes[f.Route]
+	if rt == nil {
+		rt = namedRoute(f.Route)
+	}
+	if rt == nil {
+		if len(h.scope) > 0 {
+			h.routeCache.Delete(f.Route) // Drop the handler of an unregistered name
+		}
+		return nil
+	}
+	if len(h.scope) == 0 {
+		return rt.handler
+	}
+	if c, ok := h.routeCache.Load(f.Route); ok && c.(*scopedHandler).key == rt {
+		return c.(*scopedHandler).handler
+	}
+	c := &scopedHandler{key: rt, handler: h.applyScope(rt.handler)}
+	h.routeCache.Store(f.Route, c)
+	return c.handler
+}
+
+// emit sends an emitted record to its destination: the filter's route if it
+// has one, the inner handler otherwise, or both for RouteTee.
+func (h *Handler) emit(ctx context.Context, inner slog.Handler, f *LogFilter, r slog.Record) error {
+	rh := h.routed(f)
+	if rh == nil {
+		return h.handleWithRetry(ctx, inner, r)
+	}
+	if !f.RouteTee {
+		return h.handleWithRetry(ctx, rh, r)
+	}
+	return errors.Join(h.handleWithRetry(ctx, rh, r.Clone()), h.handleWithRetry(ctx, inner, r))
+}
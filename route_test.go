@@ -0,0 +1,208 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_RouteOnMatch(t *testing.T) {
+	var main, debugOut bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&main, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithRouteOnMatch("debug-file", &debugOut))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_routed", Level: "debug", Enabled: true, Route: "debug-file"},
+		{Type: "job_id", Pattern: "job_tee", Level: "debug", Enabled: true, Route: "debug-file", RouteTee: true},
+		{Type: "job_id", Pattern: "job_unknown", Level: "debug", Enabled: true, Route: "no-such-route"},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name     string
+		jobID    string
+		level    slog.Level
+		inMain   bool
+		inRouted bool
+	}{
+		{"routed", "job_routed", slog.LevelDebug, false, true},
+		{"routed at info", "job_routed", slog.LevelInfo, false, true},
+		{"tee", "job_tee", slog.LevelDebug, true, true},
+		{"unknown route falls back", "job_unknown", slog.LevelDebug, true, false},
+		{"unmatched", "job_other", slog.LevelInfo, true, false},
+		{"unmatched suppressed", "job_other", slog.LevelDebug, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			main.Reset()
+			debugOut.Reset()
+			logger.Log(context.Background(), tt.level, "step", "job_id", tt.jobID)
+			if got := strings.Contains(main.String(), tt.jobID); got != tt.inMain {
+				t.Errorf("Expected in main output=%v, got: %q", tt.inMain, main.String())
+			}
+			if got := strings.Contains(debugOut.String(), tt.jobID); got != tt.inRouted {
+				t.Errorf("Expected in routed output=%v, got: %q", tt.inRouted, debugOut.String())
+			}
+		})
+	}
+}
+
+func TestHandler_RouteHandler_ScopeAndOutputLevel(t *testing.T) {
+	var main, routed bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&main, nil), level,
+		WithRouteHandler("audit", slog.NewTextHandler(&routed, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "warn", Enabled: true, Route: "audit"},
+	})
+
+	logger := slog.New(handler).With("service", "billing").WithGroup("req")
+	logger.Debug("charged", "job_id", "job_1")
+
+	out := routed.String()
+	for _, want := range []string{"level=WARN", "service=billing", "req.job_id=job_1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected routed output to contain %q, got: %q", want, out)
+		}
+	}
+	if main.Len() > 0 {
+		t.Errorf("Expected nothing in main output, got: %q", main.String())
+	}
+}
+
+func TestRegisterNamedHandler(t *testing.T) {
+	var out bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	// One writer, so matched JSON lines and other text lines interleave.
+	RegisterNamedHandler("json", slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { UnregisterNamedHandler("json") })
+
+	handler := NewHandler(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_42", Level: "debug", Route: "json", Enabled: true},
+	})
+	logger := slog.New(handler).With("service", "worker")
+
+	logger.Debug("traced", "job_id", "job_42")
+	logger.Info("normal", "job_id", "job_7")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), out.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("Expected the matched record as JSON, got %q: %v", lines[0], err)
+	}
+	if rec["msg"] != "traced" || rec["job_id"] != "job_42" || rec["service"] != "worker" {
+		t.Errorf("Expected the matched record with its attributes, got %v", rec)
+	}
+	if !strings.HasPrefix(lines[1], "time=") || !strings.Contains(lines[1], "msg=normal") {
+		t.Errorf("Expected the unmatched record as text, got %q", lines[1])
+	}
+}
+
+func TestRegisterNamedHandler_Precedence(t *testing.T) {
+	var own, named, main bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	RegisterNamedHandler("out", slog.NewJSONHandler(&named, nil))
+	t.Cleanup(func() { UnregisterNamedHandler("out") })
+
+	handler := NewHandler(slog.NewTextHandler(&main, nil), level, WithRouteOnMatch("out", &own))
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "info", Route: "out", Enabled: true}})
+	slog.New(handler).Info("step", "job_id", "job_1")
+
+	if own.Len() == 0 || named.Len() > 0 || main.Len() > 0 {
+		t.Errorf("Expected the handler's own route to take precedence, got own=%q named=%q main=%q",
+			own.String(), named.String(), main.String())
+	}
+}
+
+func TestUnregisterNamedHandler(t *testing.T) {
+	var named, main bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&main, nil), level)
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "info", Route: "late", Enabled: true}})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name    string
+		setup   func()
+		inNamed bool
+	}{
+		{"before registration", func() {}, false},
+		{"registered", func() { RegisterNamedHandler("late", slog.NewJSONHandler(&named, nil)) }, true},
+		{"unregistered", func() { UnregisterNamedHandler("late") }, false},
+	}
+
+	for _, tt := range tests {
+		named.Reset()
+		main.Reset()
+		tt.setup()
+		logger.Info("step", "job_id", "job_1")
+		if got := named.Len() > 0; got != tt.inNamed {
+			t.Errorf("%s: expected in named output=%v, got named=%q main=%q", tt.name, tt.inNamed, named.String(), main.String())
+		}
+		if got := main.Len() > 0; got == tt.inNamed {
+			t.Errorf("%s: expected in main output=%v, got: %q", tt.name, !tt.inNamed, main.String())
+		}
+	}
+}
+
+func TestRegisterNamedHandler_Replace(t *testing.T) {
+	var first, second bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	RegisterNamedHandler("swap", slog.NewJSONHandler(&first, nil))
+	t.Cleanup(func() { UnregisterNamedHandler("swap") })
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{{Type: "job_id", Pattern: "job_*", Level: "info", Route: "swap", Enabled: true}})
+	scoped := slog.New(handler).With("service", "worker")
+	derived := scoped.Handler().(*Handler)
+
+	scoped.Info("before", "job_id", "job_1")
+	RegisterNamedHandler("swap", slog.NewJSONHandler(&second, nil))
+	scoped.Info("after", "job_id", "job_1")
+
+	if !strings.Contains(first.String(), `"before"`) || strings.Contains(first.String(), `"after"`) {
+		t.Errorf("Expected only the first record in the old handler, got %q", first.String())
+	}
+	if !strings.Contains(second.String(), `"after"`) || !strings.Contains(second.String(), `"service":"worker"`) {
+		t.Errorf("Expected the later record, scoped, in the new handler, got %q", second.String())
+	}
+
+	cached := 0
+	derived.routeCache.Range(func(_, v any) bool {
+		cached++
+		if v.(*scopedHandler).key != namedRoute("swap") {
+			t.Error("Expected the old handler evicted from the route cache")
+		}
+		return true
+	})
+	if cached != 1 {
+		t.Errorf("Expected one cached route handler, got %d", cached)
+	}
+
+	UnregisterNamedHandler("swap")
+	scoped.Info("unrouted", "job_id", "job_1")
+	derived.routeCache.Range(func(k, _ any) bool {
+		t.Errorf("Expected the unregistered route %v dropped from the cache", k)
+		return true
+	})
+}
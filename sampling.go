@@ -0,0 +1,40 @@
+package logfilter
+
+import (
+	"log/slog"
+	"math/rand/v2"
+)
+
+// SampledKey is the attribute added to records emitted by baseline sampling.
+const SampledKey = "sampled"
+
+// baselineSampling emits a fraction of otherwise suppressed records.
+type baselineSampling struct {
+	level slog.Level
+	rate  float64
+}
+
+// WithBaselineSampling emits a random fraction rate (0 to 1) of records at or
+// above level that would otherwise be suppressed, e.g. 1% of debug records
+// with WithBaselineSampling(slog.LevelDebug, 0.01). This gives a statistical
+// floor of visibility independent of filters. Sampled records are emitted at
+// their original level with SampledKey=true added so they can be told apart
+// downstream. Records dropped by global suppressions are never sampled.
+//
+// Every record at or above level reaches Handle for the sampling decision,
+// so a low level with a low rate still costs a filter evaluation per record.
+func WithBaselineSampling(level slog.Level, rate float64) Option {
+	return func(o *options) {
+		o.samplingLevel = level
+		o.samplingRate = rate
+	}
+}
+
+// sample decides whether a suppressed record is emitted by baseline sampling.
+func (h *Handler) sample(r slog.Record, d decision) bool {
+	s := h.sampling
+	if s == nil || r.Level < s.level || d.suppression != nil {
+		return false
+	}
+	return s.rate >= 1 || rand.Float64() < s.rate
+}
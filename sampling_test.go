@@ -0,0 +1,83 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_BaselineSampling(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaselineSampling(slog.LevelDebug, 1))
+	logger := slog.New(handler)
+
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected Enabled to admit records at the sampling level")
+	}
+
+	logger.Debug("sampled debug")
+	out := buf.String()
+	if !strings.Contains(out, "sampled debug") || !strings.Contains(out, SampledKey+"=true") || !strings.Contains(out, "level=DEBUG") {
+		t.Errorf("Expected debug to be emitted at its level and tagged, got: %s", out)
+	}
+
+	buf.Reset()
+	logger.Info("normal info")
+	if strings.Contains(buf.String(), SampledKey) {
+		t.Errorf("Expected records emitted normally not to be tagged, got: %s", buf.String())
+	}
+}
+
+func TestHandler_BaselineSampling_Rate(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithBaselineSampling(slog.LevelInfo, 0.1))
+	logger := slog.New(handler)
+
+	const n = 10000
+	for i := 0; i < n; i++ {
+		logger.Info("x")
+		logger.Debug("below sampling level")
+	}
+
+	if strings.Contains(buf.String(), "below sampling level") {
+		t.Error("Expected records below the sampling level never to be sampled")
+	}
+	sampled := strings.Count(buf.String(), "\n")
+	if sampled < n/20 || sampled > n/5 {
+		t.Errorf("Expected roughly 10%% of %d records to be sampled, got %d", n, sampled)
+	}
+
+	stats := handler.LevelStats()[slog.LevelInfo]
+	if stats.Emitted != uint64(sampled) || stats.Emitted+stats.Suppressed != n {
+		t.Errorf("Expected stats to count sampled records as emitted, got %+v", stats)
+	}
+}
+
+func TestHandler_BaselineSampling_RespectsSuppressions(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level, WithBaselineSampling(slog.LevelDebug, 1))
+	handler.SetGlobalSuppressions([]Suppression{
+		{Key: "path", Pattern: "/healthz"},
+		{Key: "path", Pattern: "/metrics", AllowOverride: true},
+	})
+
+	logger := slog.New(handler)
+	logger.Info("probe", "path", "/healthz")
+	logger.Info("scrape", "path", "/metrics")
+	if buf.Len() > 0 {
+		t.Errorf("Expected globally suppressed records not to be sampled, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,22 @@
+package logfilter
+
+import (
+	_ "embed"
+	"slices"
+)
+
+//go:embed logfilter.schema.json
+var filterJSONSchema []byte
+
+// FilterJSONSchema returns a JSON Schema (draft 2020-12) document describing
+// a LogFilter as it appears in JSON, for validating and autocompleting filter
+// configuration in editors and UIs. It covers every field, the level names,
+// the special type prefixes, the match modes and scopes, and the RFC 3339
+// format of expires_at. A list of filters is an array of such objects.
+//
+// The schema checks shape, not everything Validate does: a pattern that must
+// not be empty, an invalid regex or numeric comparison, or an unregistered
+// level name pass it. The returned slice is a copy and may be modified.
+func FilterJSONSchema() []byte {
+	return slices.Clone(filterJSONSchema)
+}
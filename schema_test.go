@@ -0,0 +1,281 @@
+package logfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schemaValidator checks a decoded JSON value against the subset of JSON
+// Schema that FilterJSONSchema uses, failing on any other keyword so the
+// schema can't outgrow what the tests check.
+type schemaValidator struct {
+	root map[string]any
+}
+
+// schemaAnnotations are keywords that don't constrain values.
+var schemaAnnotations = []string{"$schema", "$defs", "title", "description", "default", "examples", "format"}
+
+func (s *schemaValidator) validate(schema map[string]any, v any, path string) []string {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	for kw, arg := range schema {
+		switch kw {
+		case "$ref":
+			name := strings.TrimPrefix(arg.(string), "#/$defs/")
+			def := s.root["$defs"].(map[string]any)[name].(map[string]any)
+			errs = append(errs, s.validate(def, v, path)...)
+		case "type":
+			types, ok := arg.([]any)
+			if !ok {
+				types = []any{arg}
+			}
+			if !slices.ContainsFunc(types, func(t any) bool { return schemaTypeOf(v, t.(string)) }) {
+				fail("expected type %v, got %T", arg, v)
+			}
+		case "enum":
+			if !slices.Contains(arg.([]any), v) {
+				fail("%v not in %v", v, arg)
+			}
+		case "pattern":
+			if str, ok := v.(string); ok && !regexp.MustCompile(arg.(string)).MatchString(str) {
+				fail("%q doesn't match %s", str, arg)
+			}
+		case "minimum":
+			if n, ok := v.(float64); ok && n < arg.(float64) {
+				fail("%v below minimum %v", n, arg)
+			}
+		case "required":
+			if obj, ok := v.(map[string]any); ok {
+				for _, key := range arg.([]any) {
+					if _, ok := obj[key.(string)]; !ok {
+						fail("missing %s", key)
+					}
+				}
+			}
+		case "properties":
+			if obj, ok := v.(map[string]any); ok {
+				props := arg.(map[string]any)
+				for key, val := range obj {
+					if prop, ok := props[key]; ok {
+						errs = append(errs, s.validate(prop.(map[string]any), val, path+"."+key)...)
+					} else if schema["additionalProperties"] == false {
+						fail("unknown property %s", key)
+					}
+				}
+			}
+		case "additionalProperties":
+			// Checked with properties.
+		case "items":
+			if arr, ok := v.([]any); ok {
+				for i, item := range arr {
+					errs = append(errs, s.validate(arg.(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+				}
+			}
+		case "anyOf":
+			if !slices.ContainsFunc(arg.([]any), func(sub any) bool { return len(s.validate(sub.(map[string]any), v, path)) == 0 }) {
+				fail("%v matches none of anyOf", v)
+			}
+		case "allOf":
+			for _, sub := range arg.([]any) {
+				errs = append(errs, s.validate(sub.(map[string]any), v, path)...)
+			}
+		case "if":
+			if len(s.validate(arg.(map[string]any), v, path)) == 0 {
+				if then, ok := schema["then"]; ok {
+					errs = append(errs, s.validate(then.(map[string]any), v, path)...)
+				}
+			}
+		case "then":
+			// Checked with if.
+		default:
+			if !slices.Contains(schemaAnnotations, kw) {
+				fail("unsupported keyword %s", kw)
+			}
+		}
+	}
+	return errs
+}
+
+func schemaTypeOf(v any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+func loadFilterSchema(t *testing.T) *schemaValidator {
+	t.Helper()
+	var root map[string]any
+	if err := json.Unmarshal(FilterJSONSchema(), &root); err != nil {
+		t.Fatalf("Expected the schema to be valid JSON, got %v", err)
+	}
+	return &schemaValidator{root: root}
+}
+
+// jsonFieldNames returns the JSON names of typ's serialized fields, sorted.
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaPropertyNames returns the property names of an object schema, sorted.
+func schemaPropertyNames(schema map[string]any) []string {
+	var names []string
+	for name := range schema["properties"].(map[string]any) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFilterJSONSchema_MatchesStructs(t *testing.T) {
+	s := loadFilterSchema(t)
+	defs := s.root["$defs"].(map[string]any)
+
+	tests := []struct {
+		name   string
+		typ    reflect.Type
+		schema map[string]any
+	}{
+		{"LogFilter", reflect.TypeOf(LogFilter{}), s.root},
+		{"Condition", reflect.TypeOf(Condition{}), defs["condition"].(map[string]any)},
+		{"TimeWindow", reflect.TypeOf(TimeWindow{}), defs["timeWindow"].(map[string]any)},
+	}
+
+	for _, tt := range tests {
+		want := jsonFieldNames(tt.typ)
+		if got := schemaPropertyNames(tt.schema); !slices.Equal(got, want) {
+			t.Errorf("%s: expected schema properties %v, got %v", tt.name, want, got)
+		}
+	}
+
+	modes := defs["matchMode"].(map[string]any)["enum"].([]any)
+	for _, mode := range modes {
+		if !validMatchMode(mode.(string)) {
+			t.Errorf("Expected schema match mode %q to be valid", mode)
+		}
+	}
+}
+
+func TestFilterJSONSchema_ValidFilters(t *testing.T) {
+	s := loadFilterSchema(t)
+	expiry := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	filters := []LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "*_id", Pattern: "debug_*", Level: "DEBUG", OutputLevel: "info", Enabled: true, ExpiresAt: &expiry},
+		{Type: "context:request_id", Pattern: "req-1", Level: "info+2", OutputLevel: "+1"},
+		{Type: "source:package", Pattern: "*/internal/*", Level: "-4", SourceLevels: []string{"warn", "error"}},
+		{Type: "status", Pattern: ">=500", Numeric: true, Level: "warning", MatchScope: MatchScopeRecord, Priority: -3},
+		{Type: "err", MatchMode: MatchErrorType, Pattern: "*net.OpError", Level: "error", Route: "audit", RouteTee: true},
+		{
+			Name:  "checkout-prod",
+			Match: ConditionsAny,
+			Conditions: []Condition{
+				{Type: "service", Pattern: "checkout"},
+				{Type: "env", MatchMode: MatchPresent},
+			},
+			Level:   "debug",
+			Enabled: true,
+		},
+		{
+			ID: "nightly", Type: "job_id", Pattern: "job_*", Level: "debug",
+			TimeWindow:       &TimeWindow{Start: "2:00", End: "03:00:30", Weekdays: []string{"mon", "Friday"}, Timezone: "Europe/London"},
+			ThrottlePerValue: 5 * time.Second, MaxPerSecond: 10, MaxHits: 100, Confirmed: true,
+		},
+	}
+
+	for i, f := range filters {
+		if err := f.Validate(); err != nil {
+			t.Fatalf("filter %d: expected a valid example, got %v", i, err)
+		}
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		if errs := s.validate(s.root, v, "filter"); len(errs) > 0 {
+			t.Errorf("filter %d: expected %s to satisfy the schema, got %v", i, data, errs)
+		}
+	}
+}
+
+func TestFilterJSONSchema_InvalidFilters(t *testing.T) {
+	s := loadFilterSchema(t)
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"unknown field", `{"type": "job_id", "pattern": "x", "level": "debug", "colour": "red"}`},
+		{"no type or conditions", `{"pattern": "x", "level": "debug"}`},
+		{"unknown source type", `{"type": "source:line", "pattern": "x", "level": "debug"}`},
+		{"empty context key", `{"type": "context: ", "pattern": "x", "level": "debug"}`},
+		{"malformed level", `{"type": "job_id", "pattern": "x", "level": "info+x"}`},
+		{"unknown match mode", `{"type": "job_id", "match_mode": "sometimes", "level": "debug"}`},
+		{"unknown match scope", `{"type": "job_id", "pattern": "x", "match_scope": "global"}`},
+		{"unknown match", `{"conditions": [{"type": "a", "pattern": "x"}], "match": "most"}`},
+		{"condition without type", `{"conditions": [{"pattern": "x"}]}`},
+		{"string enabled", `{"type": "job_id", "pattern": "x", "enabled": "yes"}`},
+		{"negative max hits", `{"type": "job_id", "pattern": "x", "max_hits": -1}`},
+		{"fractional priority", `{"type": "job_id", "pattern": "x", "priority": 1.5}`},
+		{"bad clock", `{"type": "job_id", "pattern": "x", "time_window": {"start": "25:00", "end": "01:00"}}`},
+		{"bad weekday", `{"type": "job_id", "pattern": "x", "time_window": {"start": "01:00", "end": "02:00", "weekdays": ["someday"]}}`},
+		{"numeric expiry", `{"type": "job_id", "pattern": "x", "expires_at": 1767366245}`},
+	}
+
+	for _, tt := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(tt.json), &v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if errs := s.validate(s.root, v, "filter"); len(errs) == 0 {
+			t.Errorf("%s: expected %s to violate the schema", tt.name, tt.json)
+		}
+	}
+}
+
+func TestFilterJSONSchema_Copy(t *testing.T) {
+	schema := FilterJSONSchema()
+	schema[0] = 'x'
+	if FilterJSONSchema()[0] != '{' {
+		t.Error("Expected FilterJSONSchema to return a copy")
+	}
+}
@@ -0,0 +1,60 @@
+package logfilter
+
+// Values for LogFilter.MatchScope and Condition.MatchScope.
+const (
+	MatchScopeBoth         = "both"         // Record and logger attributes (the default)
+	MatchScopeRecord       = "record"       // Only attributes passed with the log call
+	MatchScopePreformatted = "preformatted" // Only attributes bound via With
+)
+
+// validMatchScope reports whether scope is empty or one of the MatchScope values.
+func validMatchScope(scope string) bool {
+	switch scope {
+	case "", MatchScopeBoth, MatchScopeRecord, MatchScopePreformatted:
+		return true
+	}
+	return false
+}
+
+// scoped reports whether f consults only one source of attributes.
+func (f *LogFilter) scoped() bool {
+	return f.MatchScope == MatchScopeRecord || f.MatchScope == MatchScopePreformatted
+}
+
+// scopedAttr returns the slot of the attribute with the given key from the
+// record's attributes alone, or the logger's alone, as scope requires. A
+// record attribute doesn't shadow the logger's here, as it does in attr.
+func (v *recordView) scopedAttr(key, scope string) attrSlot {
+	if i, ok := v.keys[key]; ok {
+		if !v.collected {
+			v.collectSlots()
+		}
+		s := *v.slot(i)
+		switch {
+		case scope == MatchScopeRecord && s.rank != attrRecord:
+			return attrSlot{} // Only the logger has it
+		case scope == MatchScopeRecord || s.rank != attrRecord:
+			return s
+		}
+		// The record's attribute shadows the logger's; look past it.
+		return v.scanLoggerAttr(key)
+	}
+	if scope == MatchScopeRecord {
+		return v.scanRecordAttr(key)
+	}
+	return v.scanLoggerAttr(key)
+}
+
+// matchScopedAttr is matchAttr for a filter with a single MatchScope.
+func (v *recordView) matchScopedAttr(f *LogFilter) (string, bool) {
+	s := v.scopedAttr(f.attributeKey, f.MatchScope)
+	if elems, ok := s.elements(v.h); ok {
+		whole, _ := s.string(v.h)
+		return f.matchElems(f.matcher, elems, whole)
+	}
+	value, found := s.string(v.h)
+	if !found && f.keyPath {
+		value, found = v.lookupPath(f.attributeKey, f.MatchScope)
+	}
+	return value, found && f.matchValue(f.matcher, value)
+}
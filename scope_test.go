@@ -0,0 +1,96 @@
+package logfilter
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_MatchScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope string
+		log   func(l *slog.Logger)
+		emits bool
+	}{
+		{"preformatted bound", MatchScopePreformatted, func(l *slog.Logger) { l.With("env", "prod").Debug("step") }, true},
+		{"preformatted ignores per-call", MatchScopePreformatted, func(l *slog.Logger) { l.Debug("step", "env", "prod") }, false},
+		{"preformatted sees past per-call", MatchScopePreformatted, func(l *slog.Logger) { l.With("env", "prod").Debug("step", "env", "dev") }, true},
+		{"record per-call", MatchScopeRecord, func(l *slog.Logger) { l.Debug("step", "env", "prod") }, true},
+		{"record ignores bound", MatchScopeRecord, func(l *slog.Logger) { l.With("env", "prod").Debug("step") }, false},
+		{"record per-call over bound", MatchScopeRecord, func(l *slog.Logger) { l.With("env", "dev").Debug("step", "env", "prod") }, true},
+		{"both bound", MatchScopeBoth, func(l *slog.Logger) { l.With("env", "prod").Debug("step") }, true},
+		{"both per-call shadows bound", MatchScopeBoth, func(l *slog.Logger) { l.With("env", "prod").Debug("step", "env", "dev") }, false},
+		{"default per-call", "", func(l *slog.Logger) { l.Debug("step", "env", "prod") }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+			handler.SetFilters([]LogFilter{
+				{Type: "env", Pattern: "prod", MatchScope: tt.scope, Level: "debug", Enabled: true},
+			})
+
+			tt.log(slog.New(handler))
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_MatchScope_GroupedAndPaths(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "req.env", Pattern: "prod", MatchScope: MatchScopePreformatted, Level: "debug", Enabled: true},
+		{Type: "labels.tier", Pattern: "gold", MatchScope: MatchScopeRecord, Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.WithGroup("req").With("env", "prod").Debug("grouped bound")
+	if buf.Len() == 0 {
+		t.Error("Expected group-qualified bound attribute to match the preformatted scope")
+	}
+
+	buf.Reset()
+	logger.With("labels", map[string]string{"tier": "gold"}).Debug("bound path")
+	if buf.Len() > 0 {
+		t.Errorf("Expected bound map not to match the record scope, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Debug("call path", "labels", map[string]string{"tier": "gold"})
+	if buf.Len() == 0 {
+		t.Error("Expected per-call map to match the record scope")
+	}
+}
+
+func TestLogFilter_MatchScope_Validate(t *testing.T) {
+	f := LogFilter{Type: "env", Pattern: "prod", MatchScope: "logger"}
+	if err := f.Validate(); !errors.Is(err, ErrUnknownMatchScope) {
+		t.Errorf("Expected ErrUnknownMatchScope, got %v", err)
+	}
+
+	f = LogFilter{Type: "env", Pattern: "prod", Conditions: []Condition{{Type: "service", Pattern: "api", MatchScope: "call"}}}
+	if err := f.Validate(); !errors.Is(err, ErrUnknownMatchScope) {
+		t.Errorf("Expected ErrUnknownMatchScope for condition, got %v", err)
+	}
+
+	scoped := NewFilter("env").Pattern("prod").MatchScope(MatchScopeRecord).Build()
+	scoped.prepare()
+	if scoped.indexable() {
+		t.Error("Expected scoped filter not to be indexed")
+	}
+	if DeriveFilterID(scoped) == DeriveFilterID(NewFilter("env").Pattern("prod").Build()) {
+		t.Error("Expected MatchScope to contribute to the derived ID")
+	}
+}
@@ -0,0 +1,63 @@
+package logfilter
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// attrValueElems returns the elements of a slice or array value, such as a
+// []string of tags, as strings, so that filters can match each element
+// rather than slog's rendering of the whole. It reports false for other
+// values, including []byte, which renders as a string. A slog.LogValuer is
+// resolved first.
+func attrValueElems(v slog.Value) ([]string, bool) {
+	v = v.Resolve()
+	if v.Kind() != slog.KindAny {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v.Any())
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+	case reflect.Array:
+	default:
+		return nil, false
+	}
+
+	elems := make([]string, rv.Len())
+	for i := range elems {
+		elems[i] = attrValueToString(slog.AnyValue(rv.Index(i).Interface()).Resolve())
+	}
+	return elems, true
+}
+
+// matchElems applies m, the filter's compiled pattern, to the elements of a
+// slice attribute: the filter matches if any element matches, or with
+// Negate, if none does. It returns the matching element, or whole, the
+// attribute's string form, for a negated match.
+func (f *LogFilter) matchElems(m Matcher, elems []string, whole string) (string, bool) {
+	if f.strictPattern() && m.kind == matchNone {
+		return "", false
+	}
+	for _, e := range elems {
+		var hit bool
+		if m.kind == matchNumeric {
+			n, ok := parseNumber(e)
+			hit = ok && m.num.contains(n)
+		} else {
+			hit = m.Match(e)
+		}
+		if hit {
+			if f.Negate {
+				return "", false
+			}
+			return e, true
+		}
+	}
+	if f.Negate {
+		return whole, true
+	}
+	return "", false
+}
@@ -0,0 +1,116 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAttrValueElems(t *testing.T) {
+	tests := []struct {
+		name  string
+		value slog.Value
+		want  []string
+		ok    bool
+	}{
+		{"strings", slog.AnyValue([]string{"a", "b"}), []string{"a", "b"}, true},
+		{"ints", slog.AnyValue([]int{1, 22}), []string{"1", "22"}, true},
+		{"array", slog.AnyValue([2]string{"x", "y"}), []string{"x", "y"}, true},
+		{"any", slog.AnyValue([]any{"a", 3, true}), []string{"a", "3", "true"}, true},
+		{"empty", slog.AnyValue([]string{}), []string{}, true},
+		{"bytes", slog.AnyValue([]byte("raw")), nil, false},
+		{"string", slog.StringValue("a,b"), nil, false},
+		{"map", slog.AnyValue(map[string]int{"a": 1}), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := attrValueElems(tt.value)
+			if ok != tt.ok || strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestHandler_SliceAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		tags   any
+		emits  bool
+	}{
+		{"any element matches", LogFilter{Pattern: "beta"}, []string{"alpha", "beta", "gamma"}, true},
+		{"glob on element", LogFilter{Pattern: "gam*"}, []string{"alpha", "gamma"}, true},
+		{"no element matches", LogFilter{Pattern: "delta"}, []string{"alpha", "beta"}, false},
+		{"not the rendering", LogFilter{Pattern: "[alpha*"}, []string{"alpha", "beta"}, false},
+		{"empty slice", LogFilter{Pattern: "*"}, []string{}, false},
+		{"negated, no element matches", LogFilter{Pattern: "debug", Negate: true}, []string{"prod", "eu"}, true},
+		{"negated, an element matches", LogFilter{Pattern: "debug", Negate: true}, []string{"prod", "debug"}, false},
+		{"numeric elements", LogFilter{Pattern: ">100", Numeric: true}, []int{5, 500}, true},
+		{"regex elements", LogFilter{Pattern: "v[0-9]+", Regex: true}, []string{"latest", "v2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.filter
+			f.Type, f.Level, f.Enabled = "tags", "debug", true
+			handler.SetFilters([]LogFilter{f})
+
+			buf.Reset()
+			logger.Debug("tagged", "tags", tt.tags)
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestHandler_SliceAttrs_Indexed(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	var filters []LogFilter
+	for _, tag := range []string{"t0", "t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8", "t9"} {
+		filters = append(filters, LogFilter{Type: "tags", Pattern: tag, Level: "debug", Enabled: true})
+	}
+	handler.SetFilters(filters)
+	if handler.filtersIndex == nil {
+		t.Fatal("Expected the filter list to be indexed")
+	}
+	handler.SetMatchHook(func(f LogFilter, r slog.Record) {
+		if f.Pattern != "t3" {
+			t.Errorf("Expected the earliest matching filter t3, got %s", f.Pattern)
+		}
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("tagged", "tags", []string{"x", "t7", "t3"})
+	if buf.Len() == 0 {
+		t.Error("Expected a slice element to hit the index")
+	}
+}
+
+func TestHandler_SliceAttrs_Suppression(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetGlobalSuppressions([]Suppression{{Key: "tags", Pattern: "healthcheck"}})
+	logger := slog.New(handler)
+
+	logger.Info("probe", "tags", []string{"internal", "healthcheck"})
+	if buf.Len() > 0 {
+		t.Errorf("Expected suppression to match a slice element, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package logfilter
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxSourceCacheEntries bounds the number of program counters whose source
+// location a handler remembers. A program has a fixed set of log call sites,
+// so this is only reached by code generating them dynamically; past it,
+// further PCs are resolved on every record instead of being cached.
+const maxSourceCacheEntries = 4096
+
+// sourceCache memoizes extractSource by PC, since a PC always resolves to the
+// same file and function. Reads are lock-free.
+type sourceCache struct {
+	entries sync.Map // uintptr -> recordSource
+	size    atomic.Int64
+}
+
+// get returns the cached source location for pc.
+func (c *sourceCache) get(pc uintptr) (recordSource, bool) {
+	v, ok := c.entries.Load(pc)
+	if !ok {
+		return recordSource{}, false
+	}
+	return v.(recordSource), true
+}
+
+// put caches the source location for pc, unless the cache is full.
+func (c *sourceCache) put(pc uintptr, src recordSource) {
+	if c.size.Load() >= maxSourceCacheEntries {
+		return
+	}
+	if _, loaded := c.entries.LoadOrStore(pc, src); !loaded {
+		c.size.Add(1)
+	}
+}
+
+// source returns the source location of pc, from the cache when possible.
+func (h *Handler) source(pc uintptr) recordSource {
+	if src, ok := h.sourceCache.get(pc); ok {
+		return src
+	}
+	src := h.resolveSource(pc)
+	h.sourceCache.put(pc, src)
+	return src
+}
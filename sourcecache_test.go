@@ -0,0 +1,107 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// callerPC returns the PC of its caller.
+func callerPC() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	return pcs[0]
+}
+
+func TestHandler_SourceCache(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(io.Discard, nil), level)
+
+	pc := callerPC()
+	wantFile, wantFunction := handler.extractSource(pc)
+
+	for i := 0; i < 3; i++ {
+		src := handler.source(pc)
+		if src.file != wantFile || src.function != wantFunction {
+			t.Errorf("Expected (%q, %q), got (%q, %q)", wantFile, wantFunction, src.file, src.function)
+		}
+	}
+	if src, ok := handler.sourceCache.get(pc); !ok || src.file != wantFile || src.function != wantFunction {
+		t.Errorf("Expected cached (%q, %q), got (%+v, %v)", wantFile, wantFunction, src, ok)
+	}
+	if n := handler.sourceCache.size.Load(); n != 1 {
+		t.Errorf("Expected 1 cached PC, got %d", n)
+	}
+
+	// Derived handlers share the cache
+	if _, ok := handler.WithAttrs([]slog.Attr{slog.String("k", "v")}).(*Handler).sourceCache.get(pc); !ok {
+		t.Error("Expected derived handler to share the source cache")
+	}
+}
+
+func TestSourceCache_Bounded(t *testing.T) {
+	var c sourceCache
+	for pc := uintptr(1); pc <= maxSourceCacheEntries+10; pc++ {
+		c.put(pc, recordSource{file: "f.go", function: "F"})
+	}
+	if n := c.size.Load(); n != maxSourceCacheEntries {
+		t.Errorf("Expected cache capped at %d entries, got %d", maxSourceCacheEntries, n)
+	}
+	if _, ok := c.get(maxSourceCacheEntries + 1); ok {
+		t.Error("Expected PCs past the cap not to be cached")
+	}
+	if _, ok := c.get(1); !ok {
+		t.Error("Expected earlier PCs to stay cached")
+	}
+}
+
+func TestHandler_SourceFilterCached(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "source:function", Pattern: "TestHandler_SourceFilterCached*", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	// The same call site, resolved then cached
+	for i := 0; i < 3; i++ {
+		buf.Reset()
+		logger.Debug("step")
+		if buf.Len() == 0 {
+			t.Errorf("Expected record %d from a matching function to be emitted", i)
+		}
+	}
+}
+
+func BenchmarkHandle_SourceFilter(b *testing.B) {
+	for _, mode := range []string{"cached", "uncached"} {
+		b.Run(mode, func(b *testing.B) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			handler := NewHandler(slog.NewJSONHandler(io.Discard, nil), level)
+			handler.SetFilters([]LogFilter{
+				{Type: "source:file", Pattern: "internal/service/*", Level: "debug", Enabled: true},
+			})
+
+			r := slog.NewRecord(time.Now(), slog.LevelDebug, "step", callerPC())
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if mode == "uncached" {
+					handler.sourceCache = sourceCache{}
+				}
+				_ = handler.Handle(ctx, r)
+			}
+		})
+	}
+}
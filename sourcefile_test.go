@@ -0,0 +1,136 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHandler_SourceFileFullPath(t *testing.T) {
+	src := &recordSource{
+		file:     "internal/service/extraction.go",
+		function: "(*ExtractionService).Extract",
+		path:     "/home/dev/refyne/internal/service/extraction.go",
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		negate  bool
+		want    bool
+	}{
+		{"relative path", "internal/service/*", false, true},
+		{"bare filename", "*extraction.go", false, true},
+		{"directory segment in full path", "*/refyne/*", false, true},
+		{"absolute path", "/home/dev/refyne/*", false, true},
+		{"neither form", "*/vendor/*", false, false},
+		{"negate with full path match", "*/refyne/*", true, false},
+		{"negate with neither form", "*/vendor/*", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+			handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+			handler.SetFilters([]LogFilter{
+				{Type: SourceFilePrefix, Pattern: tt.pattern, Negate: tt.negate, Level: "debug", Enabled: true},
+			})
+
+			r := slog.NewRecord(time.Now(), slog.LevelDebug, "test", 0)
+			d := handler.evaluate(context.Background(), r, src)
+			if d.emit != tt.want {
+				t.Errorf("Expected emit=%v for pattern %q, got %v", tt.want, tt.pattern, d.emit)
+			}
+		})
+	}
+}
+
+func TestHandler_SourceFileFullPath_Logger(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{"directory segment", "*/" + filepath.Base(wd) + "/*"},
+		{"bare filename", "*sourcefile_test.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+			handler := NewHandler(inner, level)
+			handler.SetFilters([]LogFilter{
+				{Type: SourceFilePrefix, Pattern: tt.pattern, Level: "debug", Enabled: true},
+			})
+
+			slog.New(handler).Debug("test")
+			if buf.Len() == 0 {
+				t.Errorf("Expected debug message matching %q to be emitted", tt.pattern)
+			}
+		})
+	}
+}
+
+func TestFunctionPackage(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/me/app/internal/auth.(*Service).Login", "github.com/me/app/internal/auth"},
+		{"github.com/me/app/internal/auth.Login.func1", "github.com/me/app/internal/auth"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3"},
+		{"main.main", "main"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := functionPackage(tt.function); got != tt.want {
+			t.Errorf("Expected functionPackage(%q) = %q, got %q", tt.function, tt.want, got)
+		}
+	}
+}
+
+func TestHandler_SourcePackageFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"exact package", "github.com/jmylchreest/slog-logfilter", true},
+		{"package glob", "github.com/jmylchreest/*", true},
+		{"other package", "github.com/jmylchreest/slog-logfilter/otelbridge", false},
+		{"subpackage glob", "github.com/jmylchreest/slog-logfilter/*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+			handler := NewHandler(inner, level)
+			handler.SetFilters([]LogFilter{
+				{Type: SourcePackagePrefix, Pattern: tt.pattern, Level: "debug", Enabled: true},
+			})
+
+			slog.New(handler).Debug("test")
+			if got := buf.Len() > 0; got != tt.want {
+				t.Errorf("Expected emitted=%v for package pattern %q, got %v", tt.want, tt.pattern, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SourceLevels(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		// Downgrade warn records from the cache to info, leaving errors alone.
+		{Type: "component", Pattern: "cache", Level: "info", OutputLevel: "info", SourceLevels: []string{"warn"}, Enabled: true},
+		// Any other cache record needs to be an error.
+		{Type: "component", Pattern: "cache", Level: "error", Enabled: true},
+	})
+	logger := slog.New(handler).With("component", "cache")
+
+	tests := []struct {
+		name      string
+		level     slog.Level
+		wantLevel string // Empty if suppressed
+	}{
+		{"warn downgraded", slog.LevelWarn, "level=INFO"},
+		{"error ignored by warn-only filter", slog.LevelError, "level=ERROR"},
+		{"info falls through to next filter", slog.LevelInfo, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.Log(context.Background(), tt.level, "evicted")
+			if tt.wantLevel == "" {
+				if buf.Len() > 0 {
+					t.Errorf("Expected record to be suppressed, got: %s", buf.String())
+				}
+				return
+			}
+			if !strings.Contains(buf.String(), tt.wantLevel) {
+				t.Errorf("Expected %s, got: %s", tt.wantLevel, buf.String())
+			}
+		})
+	}
+
+	if got := handler.Stats()[0].Matches; got != 1 {
+		t.Errorf("Expected warn-only filter to match only the warn record, got %d matches", got)
+	}
+}
+
+func TestHandler_SourceLevels_Indexed(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_1", Level: "debug", SourceLevels: []string{"debug"}, Enabled: true},
+		{Type: "job_id", Pattern: "job_1", Level: "error", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	logger.Debug("step", "job_id", "job_1")
+	if buf.Len() == 0 {
+		t.Error("Expected debug record to match the debug-only filter")
+	}
+
+	buf.Reset()
+	logger.Warn("slow", "job_id", "job_1")
+	if buf.Len() > 0 {
+		t.Errorf("Expected warn record to fall through to the error filter, got: %s", buf.String())
+	}
+}
+
+func TestLogFilter_SourceLevels_Validate(t *testing.T) {
+	f := LogFilter{Type: "job_id", Pattern: "job_*", Level: "debug", SourceLevels: []string{"warn", "loud"}}
+	if err := f.Validate(); !errors.Is(err, ErrUnknownLevel) || !strings.Contains(err.Error(), "source_levels") {
+		t.Errorf("Expected ErrUnknownLevel for source_levels, got %v", err)
+	}
+
+	f.SourceLevels = []string{"warn", "error"}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected valid filter, got %v", err)
+	}
+}
+
+func TestLogFilter_SourceLevels_Builder(t *testing.T) {
+	f := NewFilter("job_id").Pattern("job_*").SourceLevels(slog.LevelWarn, slog.LevelError).Build()
+	if len(f.SourceLevels) != 2 || f.SourceLevels[0] != "warn" || f.SourceLevels[1] != "error" {
+		t.Errorf("Expected source levels [warn error], got %v", f.SourceLevels)
+	}
+
+	g := f
+	g.SourceLevels = []string{"warn"}
+	if DeriveFilterID(f) == DeriveFilterID(g) {
+		t.Error("Expected SourceLevels to contribute to the derived ID")
+	}
+}
@@ -0,0 +1,67 @@
+package logfilter
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// Values for WithSourcePathMode.
+const (
+	SourcePathRelative = "relative" // Relative to the working directory, else @module/file.go (the default)
+	SourcePathAbsolute = "absolute" // The full path as reported by the runtime
+	SourcePathBase     = "base"     // The file name alone, e.g. "handler.go"
+	SourcePathModule   = "module"   // Always @module/file.go, e.g. "@github.com/me/app/internal/auth/login.go"
+)
+
+// WithSourcePathMode sets how source file paths are formatted, both for
+// source:file filters to match against and, in loggers built by New, in the
+// output's source attribute: SourcePathRelative (the default), with files
+// under the working directory relative to it and others as
+// "@module/path/file.go"; SourcePathAbsolute, the full path; SourcePathBase,
+// the file name alone; or SourcePathModule, "@module/path/file.go" for every
+// file. An unknown mode is treated as the default. A handler passed to
+// NewHandler or WithHandler formats its own output.
+//
+// source:file filters also match the full path whatever the mode (see
+// SourceFilePrefix).
+func WithSourcePathMode(mode string) Option {
+	return func(o *options) {
+		o.sourcePathMode = mode
+	}
+}
+
+// moduleSourcePath returns filePath as "@" followed by the package path of
+// functionName and the file name, e.g. "@github.com/pkg/module/file.go", or
+// false if functionName has no package path.
+func moduleSourcePath(filePath, functionName string) (string, bool) {
+	// Function name looks like: "github.com/user/repo/pkg.(*Type).Method"
+	lastSlash := strings.LastIndex(functionName, "/")
+	if lastSlash < 0 {
+		return "", false
+	}
+	dotIdx := strings.Index(functionName[lastSlash+1:], ".")
+	if dotIdx < 0 {
+		return "", false
+	}
+	// Module path is everything before the type/function
+	return "@" + functionName[:lastSlash+1+dotIdx] + "/" + filepath.Base(filePath), true
+}
+
+// outputSourcePath formats the file of a record's source for the output of
+// loggers built by New, as mode requires. The relative mode trims the module
+// prefix (see trimSourcePath).
+func outputSourcePath(mode string, src *slog.Source, prefix, workDir string) string {
+	switch mode {
+	case SourcePathAbsolute:
+		return src.File
+	case SourcePathBase:
+		return filepath.Base(src.File)
+	case SourcePathModule:
+		if p, ok := moduleSourcePath(src.File, src.Function); ok {
+			return p
+		}
+		return filepath.Base(src.File)
+	}
+	return trimSourcePath(src.File, prefix, workDir)
+}
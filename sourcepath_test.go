@@ -0,0 +1,121 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestHandler_FormatSourcePath_Modes(t *testing.T) {
+	const function = "github.com/other/lib/pkg.(*Client).Do"
+
+	tests := []struct {
+		mode     string
+		filePath string
+		want     string
+	}{
+		{"", "/home/me/app/internal/auth/login.go", "internal/auth/login.go"},
+		{"", "/go/pkg/mod/github.com/other/lib/pkg/client.go", "@github.com/other/lib/pkg/client.go"},
+		{SourcePathRelative, "/home/me/app/main.go", "main.go"},
+		{SourcePathRelative, "/go/pkg/mod/github.com/other/lib/pkg/client.go", "@github.com/other/lib/pkg/client.go"},
+		{SourcePathAbsolute, "/home/me/app/internal/auth/login.go", "/home/me/app/internal/auth/login.go"},
+		{SourcePathAbsolute, "/go/pkg/mod/github.com/other/lib/pkg/client.go", "/go/pkg/mod/github.com/other/lib/pkg/client.go"},
+		{SourcePathBase, "/home/me/app/internal/auth/login.go", "login.go"},
+		{SourcePathBase, "/go/pkg/mod/github.com/other/lib/pkg/client.go", "client.go"},
+		{SourcePathModule, "/home/me/app/internal/auth/login.go", "@github.com/other/lib/pkg/login.go"},
+		{SourcePathModule, "/go/pkg/mod/github.com/other/lib/pkg/client.go", "@github.com/other/lib/pkg/client.go"},
+		{"bogus", "/home/me/app/main.go", "main.go"},
+	}
+
+	for _, tt := range tests {
+		level := new(slog.LevelVar)
+		handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithSourcePathMode(tt.mode))
+		handler.workDir = "/home/me/app"
+		if got := handler.formatSourcePath(tt.filePath, function); got != tt.want {
+			t.Errorf("mode %q, %s: expected %q, got %q", tt.mode, tt.filePath, tt.want, got)
+		}
+	}
+
+	// Without a package path, the module mode falls back to the file name.
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), new(slog.LevelVar), WithSourcePathMode(SourcePathModule))
+	if got := handler.formatSourcePath("/src/main.go", "main.main"); got != "main.go" {
+		t.Errorf("Expected module mode to fall back to the file name, got %q", got)
+	}
+}
+
+func TestHandler_SourcePathMode_Matching(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(wd, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mode string
+		want string // Formatted path the filter matched
+	}{
+		{"", rel},
+		{SourcePathAbsolute, file},
+		{SourcePathBase, "sourcepath_test.go"},
+		{SourcePathModule, "@github.com/jmylchreest/slog-logfilter/sourcepath_test.go"},
+	}
+
+	for _, tt := range tests {
+		level := new(slog.LevelVar)
+		level.Set(slog.LevelInfo)
+		handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithSourcePathMode(tt.mode))
+		handler.SetFilters([]LogFilter{
+			{Type: SourceFilePrefix, Pattern: "*sourcepath_test.go", Level: "debug", Enabled: true},
+		})
+
+		var pcs [1]uintptr
+		runtime.Callers(1, pcs[:])
+		r := slog.NewRecord(time.Now(), slog.LevelDebug, "step", pcs[0])
+		d := handler.Explain(context.Background(), r)
+		if !d.Emit || d.Value != tt.want {
+			t.Errorf("mode %q: expected a match on %q, got %s", tt.mode, tt.want, d)
+		}
+	}
+}
+
+func TestNew_SourcePathMode_Output(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", "sourcepath_test.go"},
+		{SourcePathAbsolute, file},
+		{SourcePathBase, "sourcepath_test.go"},
+		{SourcePathModule, "@github.com/jmylchreest/slog-logfilter/sourcepath_test.go"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := New(WithOutput(&buf), WithFormat("json"), WithSource(true), WithSourcePathMode(tt.mode))
+		logger.Info("step")
+
+		var rec struct {
+			Source struct {
+				File string `json:"file"`
+			} `json:"source"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+			t.Fatalf("mode %q: %v", tt.mode, err)
+		}
+		if rec.Source.File != tt.want {
+			t.Errorf("mode %q: expected source file %q in output, got %q", tt.mode, tt.want, rec.Source.File)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package logfilter
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// LevelStat holds the number of records emitted and suppressed at a level.
+type LevelStat struct {
+	Emitted    uint64 `json:"emitted"`
+	Suppressed uint64 `json:"suppressed"`
+}
+
+// statLevels are the level buckets tracked by LevelStats, in order.
+var statLevels = [...]slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+// levelCounters holds per-level emitted/suppressed counters, one per statLevels entry.
+type levelCounters [len(statLevels)]struct {
+	emitted    atomic.Uint64
+	suppressed atomic.Uint64
+}
+
+// statBucket returns the index into statLevels for a record level. Levels
+// between the standard ones count towards the standard level below them;
+// levels below debug count as debug.
+func statBucket(level slog.Level) int {
+	for i := len(statLevels) - 1; i > 0; i-- {
+		if level >= statLevels[i] {
+			return i
+		}
+	}
+	return 0
+}
+
+// record counts a record at the given (original) level.
+func (c *levelCounters) record(level slog.Level, emitted bool) {
+	b := &c[statBucket(level)]
+	if emitted {
+		b.emitted.Add(1)
+	} else {
+		b.suppressed.Add(1)
+	}
+}
+
+// LevelStats returns the number of records emitted and suppressed by the
+// handler, bucketed by the record's original level (debug, info, warn, error).
+// Custom levels are counted under the nearest standard level below them.
+// Counts include records from all handlers derived via WithAttrs/WithGroup.
+// Records rejected by Enabled never reach the handler and are not counted.
+func (h *Handler) LevelStats() map[slog.Level]LevelStat {
+	stats := make(map[slog.Level]LevelStat, len(statLevels))
+	for i, level := range statLevels {
+		stats[level] = LevelStat{
+			Emitted:    h.levelCounters[i].emitted.Load(),
+			Suppressed: h.levelCounters[i].suppressed.Load(),
+		}
+	}
+	return stats
+}
+
+// ResetLevelStats zeroes the per-level counters.
+func (h *Handler) ResetLevelStats() {
+	for i := range h.levelCounters {
+		h.levelCounters[i].emitted.Store(0)
+		h.levelCounters[i].suppressed.Store(0)
+	}
+}
+
+// FilterStat holds the number of records a filter has decided.
+type FilterStat struct {
+	ID      string `json:"id"`             // FilterID, explicit or derived
+	Name    string `json:"name,omitempty"` // Filter Name, if set
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+	Set     string `json:"set"` // "base", "filters" or "group:<name>", as in WriteMetrics
+	Matches uint64 `json:"matches"`
+}
+
+// Stats returns the match count of every filter, base filters first, then the
+// regular filters in the order they were added, as GetFilters returns them,
+// then filter groups. Priority changes evaluation order, not this one. A
+// filter's count is the number of records it decided: under first match wins,
+// a record counts only towards the first filter it matched, whether or not it
+// was emitted. Filters still at zero after representative traffic are
+// candidates for removal. Counts carry over when a filter obtained from
+// GetFilters is passed back to SetFilters with the same FilterID; newly
+// constructed filters, and copies modified so that their ID changes, start
+// from zero.
+func (h *Handler) Stats() []FilterStat {
+	h.filtersLock.RLock()
+	sets := h.filterSets()
+	h.filtersLock.RUnlock()
+
+	var stats []FilterStat
+	for _, set := range sets {
+		for i := range set.filters {
+			f := &set.filters[i]
+			stats = append(stats, FilterStat{
+				ID:      f.FilterID(),
+				Name:    f.Name,
+				Type:    f.Type,
+				Pattern: f.Pattern,
+				Set:     set.name,
+				Matches: f.matchCount(),
+			})
+		}
+	}
+	return stats
+}
+
+// ResetStats zeroes every filter's match count. The per-level counters are
+// reset separately, by ResetLevelStats.
+func (h *Handler) ResetStats() {
+	h.filtersLock.RLock()
+	sets := h.filterSets()
+	h.filtersLock.RUnlock()
+
+	for _, set := range sets {
+		for i := range set.filters {
+			if st := set.filters[i].state; st != nil {
+				st.matches.Store(0)
+			}
+		}
+	}
+}
+
+// matchCount returns the number of records the filter has decided.
+func (f *LogFilter) matchCount() uint64 {
+	if f.state == nil {
+		return 0
+	}
+	return f.state.matches.Load()
+}
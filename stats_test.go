@@ -0,0 +1,134 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_LevelStats(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "noisy_*", Level: "warn", Enabled: true},
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("suppressed", "job_id", "normal")
+	logger.Debug("suppressed", "job_id", "normal")
+	logger.Debug("emitted", "job_id", "debug_1")
+	logger.Info("emitted")
+	logger.Info("suppressed", "job_id", "noisy_1")
+	logger.With("job_id", "noisy_2").Warn("emitted")
+	logger.Error("emitted")
+
+	stats := handler.LevelStats()
+	want := map[slog.Level]LevelStat{
+		slog.LevelDebug: {Emitted: 1, Suppressed: 2},
+		slog.LevelInfo:  {Emitted: 1, Suppressed: 1},
+		slog.LevelWarn:  {Emitted: 1, Suppressed: 0},
+		slog.LevelError: {Emitted: 1, Suppressed: 0},
+	}
+	for lvl, w := range want {
+		if stats[lvl] != w {
+			t.Errorf("Level %v: expected %+v, got %+v", lvl, w, stats[lvl])
+		}
+	}
+
+	handler.ResetLevelStats()
+	for lvl, s := range handler.LevelStats() {
+		if s != (LevelStat{}) {
+			t.Errorf("Level %v: expected zero stats after reset, got %+v", lvl, s)
+		}
+	}
+}
+
+func TestHandler_Stats(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level,
+		WithBaseFilters([]LogFilter{{Type: "noisy", Pattern: "yes", Level: "error", Enabled: true}}))
+	handler.SetFilters([]LogFilter{
+		{Name: "jobs", Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "job_1", Level: "warn", Enabled: true}, // Shadowed by "jobs"
+		{Type: "user_id", Pattern: "u*", Level: "warn", Enabled: true},
+	})
+	handler.SetFilterGroups(map[string][]LogFilter{
+		"team": {{Type: "team", Pattern: "core", Level: "debug", Enabled: true}},
+	})
+
+	logger := slog.New(handler)
+	const n = 7
+	for i := 0; i < n; i++ {
+		logger.Debug("step", "job_id", "job_1")
+	}
+	logger.Info("suppressed", "user_id", "u1") // Counted although suppressed
+	logger.Info("no match", "user_id", "admin")
+	logger.Warn("base", "noisy", "yes", "job_id", "job_2")
+	logger.Debug("group", "team", "core")
+
+	stats := handler.Stats()
+	want := []struct {
+		set, typ, pattern string
+		matches           uint64
+	}{
+		{"base", "noisy", "yes", 1},
+		{"filters", "job_id", "job_*", n},
+		{"filters", "job_id", "job_1", 0},
+		{"filters", "user_id", "u*", 1},
+		{"group:team", "team", "core", 1},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("Expected %d stats, got %d: %+v", len(want), len(stats), stats)
+	}
+	for i, w := range want {
+		s := stats[i]
+		if s.Set != w.set || s.Type != w.typ || s.Pattern != w.pattern || s.Matches != w.matches {
+			t.Errorf("Stat %d: expected %+v, got %+v", i, w, s)
+		}
+	}
+	if stats[1].Name != "jobs" || stats[1].ID != handler.GetFilters()[0].FilterID() {
+		t.Errorf("Expected stat to carry the filter's name and ID, got %+v", stats[1])
+	}
+
+	// Counts carry over when filters are passed back to SetFilters
+	handler.SetFilters(handler.GetFilters())
+	if got := handler.Stats()[1].Matches; got != n {
+		t.Errorf("Expected count %d kept across SetFilters, got %d", n, got)
+	}
+
+	handler.ResetStats()
+	for _, s := range handler.Stats() {
+		if s.Matches != 0 {
+			t.Errorf("Expected zero matches after reset, got %+v", s)
+		}
+	}
+	if handler.LevelStats()[slog.LevelDebug].Emitted == 0 {
+		t.Error("Expected ResetStats to leave level stats alone")
+	}
+}
+
+func TestStatBucket(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  slog.Level
+	}{
+		{slog.LevelDebug - 4, slog.LevelDebug},
+		{slog.LevelDebug, slog.LevelDebug},
+		{slog.LevelInfo, slog.LevelInfo},
+		{slog.LevelInfo + 2, slog.LevelInfo},
+		{slog.LevelWarn, slog.LevelWarn},
+		{slog.LevelError, slog.LevelError},
+		{slog.LevelError + 4, slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := statLevels[statBucket(tt.level)]; got != tt.want {
+			t.Errorf("statBucket(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
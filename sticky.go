@@ -0,0 +1,168 @@
+package logfilter
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultStickyIDs is the number of context IDs WithStickyMatches tracks
+// when maxIDs isn't positive.
+const defaultStickyIDs = 1024
+
+// WithStickyMatches makes filter matches stick to a context ID, for
+// pipelines where the attribute a filter matches, such as job_id, is only on
+// a job's first record. The ID is the value returned by the context extractor
+// registered for contextKey (see RegisterContextExtractor), e.g. a trace ID.
+// Once a filter matches and emits a record whose context has an ID, later
+// records with the same ID that no filter matches are decided by that filter,
+// as if they had matched it, for ttl after the match. Each new match restarts
+// the window; records emitted only by stickiness don't. Records whose context
+// yields no ID are unaffected.
+//
+// Memory is bounded by maxIDs (1024 if not positive): each tracked ID costs
+// roughly the length of the ID plus 150 bytes, and when the limit is reached
+// the least recently matched ID is forgotten first, ending its window early.
+// Expired IDs are forgotten as they are looked up or evicted. A larger limit
+// keeps more concurrent jobs sticky at the cost of memory; a longer ttl
+// keeps finished jobs' IDs, and so that memory, for longer.
+//
+// A sticky ID keeps the filter it matched until the window ends, even if the
+// filter is changed or removed meanwhile. Global suppressions still apply.
+func WithStickyMatches(contextKey string, ttl time.Duration, maxIDs int) Option {
+	return func(o *options) {
+		o.stickyKey = contextKey
+		o.stickyTTL = ttl
+		o.stickyMaxIDs = maxIDs
+	}
+}
+
+// stickyCache remembers the filter that last matched each context ID.
+type stickyCache struct {
+	key    string        // Context extractor key
+	ttl    time.Duration // How long a match sticks
+	maxIDs int           // Max IDs tracked
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // ID -> element in order
+	order   *list.List               // Most recently matched at front
+}
+
+// stickyEntry is the filter stuck to one context ID.
+type stickyEntry struct {
+	id      string
+	filter  *LogFilter
+	expires time.Time
+}
+
+// newStickyCache creates an empty sticky cache keyed by the given context key.
+func newStickyCache(key string, ttl time.Duration, maxIDs int) *stickyCache {
+	if maxIDs <= 0 {
+		maxIDs = defaultStickyIDs
+	}
+	return &stickyCache{
+		key:     key,
+		ttl:     ttl,
+		maxIDs:  maxIDs,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// remember sticks f to the context's ID, if it has one, until now plus the
+// cache's ttl.
+func (c *stickyCache) remember(ctx context.Context, f *LogFilter, now time.Time) {
+	id, ok := extractFromContext(ctx, c.key)
+	if !ok || id == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		e := el.Value.(*stickyEntry)
+		e.filter, e.expires = f, now.Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.maxIDs {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*stickyEntry).id)
+	}
+	c.entries[id] = c.order.PushFront(&stickyEntry{id: id, filter: f, expires: now.Add(c.ttl)})
+}
+
+// lookup returns the filter stuck to the context's ID at now, or nil. An
+// expired ID is forgotten unless peek is set, as for Explain, which must not
+// change the cache.
+func (c *stickyCache) lookup(ctx context.Context, now time.Time, peek bool) *LogFilter {
+	id, ok := extractFromContext(ctx, c.key)
+	if !ok || id == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[id]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*stickyEntry)
+	if !now.Before(e.expires) {
+		if !peek {
+			c.order.Remove(el)
+			delete(c.entries, id)
+		}
+		return nil
+	}
+	return e.filter
+}
+
+// len returns the number of tracked IDs.
+func (c *stickyCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// stickyMayEmit reports whether a record at level could be emitted through
+// the filter stuck to ctx's ID. Enabled and mayEmit consult it, so a sticky
+// ID keeps its filter's level after the filter is removed or changed, and
+// when the filter only matched through the context.
+func (h *Handler) stickyMayEmit(ctx context.Context, level slog.Level) bool {
+	if h.sticky == nil || ctx == nil {
+		return false
+	}
+	f := h.sticky.lookup(ctx, h.now(), false)
+	return f != nil && level >= h.floored(f.parsedLevel)
+}
+
+// applySticky remembers d's filter for r's context ID if it matched and
+// emitted r, or decides r by the filter stuck to the ID if none matched.
+// With peek set, as for Explain, it only does the latter, leaving the cache
+// unchanged.
+func (h *Handler) applySticky(ctx context.Context, r slog.Record, d decision, peek bool) decision {
+	now := r.Time
+	if now.IsZero() {
+		now = h.now()
+	}
+	switch {
+	case d.filter != nil && d.emit:
+		if !peek {
+			h.sticky.remember(ctx, d.filter, now)
+		}
+	case d.filter == nil && d.suppression == nil:
+		if f := h.sticky.lookup(ctx, now, peek); f != nil {
+			d.filter = f
+			d.level = h.floored(f.parsedLevel)
+			d.outputLevel = h.outputLevel(f, r.Level, h.globalLevel.Level())
+			d.emit = r.Level >= d.level
+		}
+	}
+	return d
+}
@@ -0,0 +1,265 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stickyCtxKey struct{}
+
+func registerStickyExtractor(t *testing.T) {
+	t.Helper()
+	RegisterContextExtractor("trace_id", func(ctx context.Context) (string, bool) {
+		if v, ok := ctx.Value(stickyCtxKey{}).(string); ok {
+			return v, true
+		}
+		return "", false
+	})
+	t.Cleanup(ClearContextExtractors)
+}
+
+func newStickyTestHandler(buf *bytes.Buffer, opts ...Option) *Handler {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, opts...)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_debug*", Level: "debug", Enabled: true},
+	})
+	return handler
+}
+
+func TestStickyMatches_MultiLineJob(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 0)))
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	other := context.WithValue(context.Background(), stickyCtxKey{}, "trace-2")
+
+	logger.DebugContext(job, "before match")
+	logger.DebugContext(job, "job started", "job_id", "job_debug_42")
+	logger.DebugContext(job, "fetching input")
+	logger.DebugContext(job, "writing output")
+	logger.DebugContext(other, "other job")
+	logger.DebugContext(context.Background(), "no trace")
+
+	out := buf.String()
+	for _, want := range []string{"job started", "fetching input", "writing output"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q to be emitted, got: %s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"before match", "other job", "no trace"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("Expected %q to be suppressed, got: %s", unwanted, out)
+		}
+	}
+}
+
+func TestStickyMatches_Disabled(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(newStickyTestHandler(&buf))
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	logger.DebugContext(job, "job started", "job_id", "job_debug_42")
+	logger.DebugContext(job, "fetching input")
+
+	if strings.Contains(buf.String(), "fetching input") {
+		t.Errorf("Expected later records to be suppressed without sticky matches, got: %s", buf.String())
+	}
+}
+
+func TestStickyMatches_Expiry(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	handler := newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 0))
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		after time.Duration
+		attrs []any
+		want  bool
+	}{
+		{"match", 0, []any{"job_id", "job_debug_1"}, true},
+		{"within window", 30 * time.Second, nil, true},
+		{"window not extended by sticky record", 61 * time.Second, nil, false},
+		{"rematch", 90 * time.Second, []any{"job_id", "job_debug_1"}, true},
+		{"within new window", 140 * time.Second, nil, true},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		r := slog.NewRecord(start.Add(tt.after), slog.LevelDebug, tt.name, 0)
+		r.Add(tt.attrs...)
+		if err := handler.Handle(job, r); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestStickyMatches_Bounded(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	handler := newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 2))
+	logger := slog.New(handler)
+
+	for i := 0; i < 3; i++ {
+		ctx := context.WithValue(context.Background(), stickyCtxKey{}, fmt.Sprintf("trace-%d", i))
+		logger.DebugContext(ctx, "job started", "job_id", "job_debug")
+	}
+	if n := handler.sticky.len(); n != 2 {
+		t.Errorf("Expected 2 tracked IDs, got %d", n)
+	}
+
+	// The least recently matched ID was evicted.
+	buf.Reset()
+	logger.DebugContext(context.WithValue(context.Background(), stickyCtxKey{}, "trace-0"), "evicted")
+	logger.DebugContext(context.WithValue(context.Background(), stickyCtxKey{}, "trace-2"), "kept")
+	out := buf.String()
+	if strings.Contains(out, "evicted") || !strings.Contains(out, "kept") {
+		t.Errorf("Expected only the recent ID to stay sticky, got: %s", out)
+	}
+}
+
+func TestStickyMatches_QuietingFilterDoesNotStick(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	handler := newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 0))
+	handler.AddFilter(LogFilter{Type: "component", Pattern: "db", Level: "error", Enabled: true})
+	logger := slog.New(handler)
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	logger.InfoContext(job, "query", "component", "db")
+	logger.InfoContext(job, "later")
+
+	if !strings.Contains(buf.String(), "later") {
+		t.Errorf("Expected unmatched info record to use the global level, got: %s", buf.String())
+	}
+	if n := handler.sticky.len(); n != 0 {
+		t.Errorf("Expected a suppressing match not to stick, got %d IDs", n)
+	}
+}
+
+func TestStickyMatches_FilterRemoved(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	handler := newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 0))
+	logger := slog.New(handler)
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	logger.DebugContext(job, "job started", "job_id", "job_debug_42")
+	handler.ClearFilters()
+	logger.DebugContext(job, "after removal")
+	logger.DebugContext(context.Background(), "no trace")
+
+	out := buf.String()
+	if !strings.Contains(out, "after removal") {
+		t.Errorf("Expected the sticky ID to keep its removed filter, got: %s", out)
+	}
+	if strings.Contains(out, "no trace") {
+		t.Errorf("Expected records without the ID to be suppressed, got: %s", out)
+	}
+}
+
+type stickyTenantKey struct{}
+
+func TestStickyMatches_ContextFilter(t *testing.T) {
+	registerStickyExtractor(t)
+	RegisterContextExtractor("tenant", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(stickyTenantKey{}).(string)
+		return v, ok
+	})
+
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level, WithStickyMatches("trace_id", time.Minute, 0))
+	handler.SetFilters([]LogFilter{
+		{Type: "context:tenant", Pattern: "acme", Level: "debug", Enabled: true},
+	})
+	logger := slog.New(handler)
+
+	// The tenant is only in the context of the job's first record.
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	logger.DebugContext(context.WithValue(job, stickyTenantKey{}, "acme"), "job started")
+	logger.DebugContext(job, "fetching input")
+	logger.DebugContext(context.WithValue(context.Background(), stickyCtxKey{}, "trace-2"), "other job")
+
+	out := buf.String()
+	for _, want := range []string{"job started", "fetching input"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q to be emitted, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "other job") {
+		t.Errorf("Expected other IDs to be suppressed, got: %s", out)
+	}
+}
+
+func TestStickyMatches_DefaultOutputLevel(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(newStickyTestHandler(&buf,
+		WithStickyMatches("trace_id", time.Minute, 0),
+		WithDefaultOutputLevel(slog.LevelInfo),
+	))
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	logger.DebugContext(job, "job started", "job_id", "job_debug_42")
+	logger.DebugContext(job, "fetching input")
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "level=INFO") {
+			t.Errorf("Expected sticky records at the default output level, got: %s", line)
+		}
+	}
+	if !strings.Contains(buf.String(), "fetching input") {
+		t.Errorf("Expected the sticky record to be emitted, got: %s", buf.String())
+	}
+}
+
+func TestStickyMatches_Explain(t *testing.T) {
+	registerStickyExtractor(t)
+
+	var buf bytes.Buffer
+	handler := newStickyTestHandler(&buf, WithStickyMatches("trace_id", time.Minute, 0))
+	logger := slog.New(handler)
+
+	job := context.WithValue(context.Background(), stickyCtxKey{}, "trace-1")
+	other := context.WithValue(context.Background(), stickyCtxKey{}, "trace-2")
+
+	// Explaining a match doesn't stick it
+	if !handler.WouldEmit(other, slog.LevelDebug, slog.String("job_id", "job_debug_1")) {
+		t.Error("Expected the matching record to be emitted")
+	}
+	if handler.WouldEmit(other, slog.LevelDebug) {
+		t.Error("Expected Explain not to stick its match to the context ID")
+	}
+
+	logger.DebugContext(job, "job started", "job_id", "job_debug_42")
+	d := handler.Explain(job, slog.NewRecord(time.Now(), slog.LevelDebug, "fetching input", 0))
+	if !d.Emit || d.Filter == nil || d.Filter.Pattern != "job_debug*" {
+		t.Errorf("Expected the stuck filter to decide the record, got %s", d)
+	}
+}
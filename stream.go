@@ -0,0 +1,265 @@
+package logfilter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// WithPassMalformed controls how FilterStream treats lines that are not valid
+// JSON log records. If true they are written through unchanged; if false
+// (the default) they are dropped.
+func WithPassMalformed(pass bool) Option {
+	return func(o *options) {
+		o.passMalformed = pass
+	}
+}
+
+// FilterStream reads newline-delimited JSON log records (as written by slog's
+// JSONHandler) from r, applies the same filter decisions as Handler.Handle, and
+// writes the surviving records to w.
+//
+// The global level is set with WithLevel (default INFO). Records that pass
+// unchanged are written byte-for-byte; records whose level is transformed by a
+// filter's OutputLevel are re-encoded with the new level. Source filters match
+// against the record's "source" object, and context filters never match since
+// there is no context to extract from. Nested JSON objects, as JSONHandler
+// writes groups, become groups, so dotted types such as "req.id" reach into
+// them; arrays are matched as raw JSON.
+//
+// Other options that shape filter decisions, such as WithLevelFloor,
+// WithBaseFilters, WithAllowListMode or WithDefaultOutputLevel, apply as they
+// do to a Handler, and filters replaces any given with WithFilters. Options
+// that act on emitted records rather than decide them, such as redaction,
+// annotation, routes, sampling and capture-on-error, have no effect, since
+// surviving lines are written as read.
+//
+// A line is malformed if it is not a JSON object or lacks a parseable "level".
+// Malformed lines are dropped unless WithPassMalformed(true) is given.
+func FilterStream(r io.Reader, w io.Writer, filters []LogFilter, opts ...Option) error {
+	o := &options{level: slog.LevelInfo}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	level := new(slog.LevelVar)
+	level.Set(o.level)
+	h := NewHandler(discardHandler{}, level, opts...)
+	h.SetFilters(filters)
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if err := filterStreamLine(h, bw, line, o.passMalformed); err != nil {
+				return err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	return bw.Flush()
+}
+
+// filterStreamLine evaluates one line and writes it to w if it survives.
+func filterStreamLine(h *Handler, w io.Writer, line []byte, passMalformed bool) error {
+	rec, err := parseStreamRecord(line)
+	if err != nil {
+		if passMalformed {
+			return writeStreamLine(w, line)
+		}
+		return nil
+	}
+
+	d := h.decide(context.Background(), rec.record, &rec.source)
+	if !d.emit {
+		return nil
+	}
+	if d.outputLevel == rec.record.Level {
+		return writeStreamLine(w, line)
+	}
+
+	rec.fields[rec.levelIndex].value, _ = json.Marshal(d.outputLevel.String())
+	return writeStreamLine(w, rec.encode())
+}
+
+// writeStreamLine writes line, adding a trailing newline if it lacks one.
+func writeStreamLine(w io.Writer, line []byte) error {
+	if _, err := w.Write(line); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		_, err := w.Write([]byte("\n"))
+		return err
+	}
+	return nil
+}
+
+// streamField is a single top-level key/value of a JSON log record.
+type streamField struct {
+	key   string
+	value json.RawMessage
+}
+
+// streamRecord is a parsed JSON log line.
+type streamRecord struct {
+	fields     []streamField // In original order, for re-encoding
+	levelIndex int           // Index of the "level" field in fields
+	record     slog.Record
+	source     recordSource
+}
+
+// parseStreamRecord parses a JSON log line into a record for evaluation.
+// Keys written by slog's JSONHandler ("time", "level", "msg", "source") populate
+// the record itself; all other top-level keys become attributes.
+func parseStreamRecord(line []byte) (*streamRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+
+	rec := &streamRecord{levelIndex: -1}
+	var attrs []slog.Attr
+	var msg string
+	var t time.Time
+	var level slog.Level
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		rec.fields = append(rec.fields, streamField{key: key, value: raw})
+
+		switch key {
+		case slog.TimeKey:
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				t, _ = time.Parse(time.RFC3339Nano, s)
+			}
+		case slog.LevelKey:
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("invalid level: %w", err)
+			}
+			if err := level.UnmarshalText([]byte(s)); err != nil {
+				return nil, err
+			}
+			rec.levelIndex = len(rec.fields) - 1
+		case slog.MessageKey:
+			_ = json.Unmarshal(raw, &msg)
+		case slog.SourceKey:
+			var src slog.Source
+			if json.Unmarshal(raw, &src) == nil {
+				rec.source = recordSource{file: src.File, function: src.Function, pkg: functionPackage(src.Function)}
+			}
+		default:
+			attrs = append(attrs, slog.Attr{Key: key, Value: jsonToValue(raw)})
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if rec.levelIndex < 0 {
+		return nil, fmt.Errorf("missing level")
+	}
+
+	rec.record = slog.NewRecord(t, level, msg, 0)
+	rec.record.AddAttrs(attrs...)
+	return rec, nil
+}
+
+// jsonToValue converts a raw JSON value into an slog.Value for matching.
+// Objects become groups and arrays are kept as raw JSON.
+func jsonToValue(raw json.RawMessage) slog.Value {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return slog.StringValue(string(raw))
+	}
+	return decodedToValue(v, raw)
+}
+
+// decodedToValue converts a decoded JSON value into an slog.Value. raw is its
+// encoding, if at hand, for values kept as raw JSON.
+func decodedToValue(v any, raw json.RawMessage) slog.Value {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]slog.Attr, len(keys))
+		for i, k := range keys {
+			attrs[i] = slog.Attr{Key: k, Value: decodedToValue(v[k], nil)}
+		}
+		return slog.GroupValue(attrs...)
+	case string:
+		return slog.StringValue(v)
+	case bool:
+		return slog.BoolValue(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return slog.Int64Value(i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return slog.Float64Value(f)
+		}
+		return slog.StringValue(v.String())
+	default:
+		if raw == nil {
+			raw, _ = json.Marshal(v)
+		}
+		return slog.StringValue(string(raw))
+	}
+}
+
+// encode re-encodes the record's fields as a single JSON line.
+func (rec *streamRecord) encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range rec.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// discardHandler is an slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
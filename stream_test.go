@@ -0,0 +1,210 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFilterStream_AppliesFilters(t *testing.T) {
+	input := strings.Join([]string{
+		`{"time":"2024-01-01T00:00:00Z","level":"DEBUG","msg":"hidden","job_id":"normal_1"}`,
+		`{"time":"2024-01-01T00:00:01Z","level":"DEBUG","msg":"elevated","job_id":"debug_1"}`,
+		`{"time":"2024-01-01T00:00:02Z","level":"INFO","msg":"normal","job_id":"normal_1"}`,
+		`{"time":"2024-01-01T00:00:03Z","level":"INFO","msg":"noisy","job_id":"noisy_1"}`,
+	}, "\n")
+
+	filters := []LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "noisy_*", Level: "warn", Enabled: true},
+	}
+
+	var out bytes.Buffer
+	if err := FilterStream(strings.NewReader(input), &out, filters); err != nil {
+		t.Fatalf("FilterStream returned error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"elevated", "normal"} {
+		if !strings.Contains(got, `"msg":"`+want+`"`) {
+			t.Errorf("Expected %q to survive, got: %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"hidden", "noisy"} {
+		if strings.Contains(got, `"msg":"`+unwanted+`"`) {
+			t.Errorf("Expected %q to be suppressed, got: %s", unwanted, got)
+		}
+	}
+}
+
+func TestFilterStream_PreservesUnchangedLines(t *testing.T) {
+	line := `{"time":"2024-01-01T00:00:00Z","level":"INFO","msg":"keep",  "n":1.50,"nested":{"a":1}}`
+
+	var out bytes.Buffer
+	if err := FilterStream(strings.NewReader(line+"\n"), &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != line+"\n" {
+		t.Errorf("Expected line to be written unchanged, got: %s", out.String())
+	}
+}
+
+func TestFilterStream_TransformsLevel(t *testing.T) {
+	line := `{"time":"2024-01-01T00:00:00Z","level":"DEBUG","msg":"trace","job_id":"debug_1","n":2}`
+	filters := []LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", OutputLevel: "warn", Enabled: true},
+	}
+
+	var out bytes.Buffer
+	if err := FilterStream(strings.NewReader(line), &out, filters); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":"2024-01-01T00:00:00Z","level":"WARN","msg":"trace","job_id":"debug_1","n":2}` + "\n"
+	if out.String() != want {
+		t.Errorf("Expected %s, got %s", want, out.String())
+	}
+}
+
+func TestFilterStream_Level(t *testing.T) {
+	input := `{"level":"INFO","msg":"info"}` + "\n" + `{"level":"WARN","msg":"warn"}` + "\n"
+
+	var out bytes.Buffer
+	if err := FilterStream(strings.NewReader(input), &out, nil, WithLevel(slog.LevelWarn)); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), `"info"`) || !strings.Contains(out.String(), `"warn"`) {
+		t.Errorf("Expected only warn record at WARN level, got: %s", out.String())
+	}
+}
+
+func TestFilterStream_SourceFilter(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"DEBUG","source":{"function":"main.run","file":"internal/service/a.go","line":1},"msg":"service"}`,
+		`{"level":"DEBUG","source":{"function":"main.run","file":"internal/other/b.go","line":1},"msg":"other"}`,
+	}, "\n")
+	filters := []LogFilter{
+		{Type: SourceFilePrefix, Pattern: "internal/service/*", Level: "debug", Enabled: true},
+	}
+
+	var out bytes.Buffer
+	if err := FilterStream(strings.NewReader(input), &out, filters); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"msg":"service"`) || strings.Contains(out.String(), `"msg":"other"`) {
+		t.Errorf("Expected only service record to survive, got: %s", out.String())
+	}
+}
+
+func TestFilterStream_Malformed(t *testing.T) {
+	input := strings.Join([]string{
+		`not json`,
+		`{"msg":"no level"}`,
+		`["array"]`,
+		`{"level":"INFO","msg":"ok"}`,
+	}, "\n")
+
+	var dropped bytes.Buffer
+	if err := FilterStream(strings.NewReader(input), &dropped, nil); err != nil {
+		t.Fatal(err)
+	}
+	if dropped.String() != `{"level":"INFO","msg":"ok"}`+"\n" {
+		t.Errorf("Expected malformed lines to be dropped, got: %q", dropped.String())
+	}
+
+	var passed bytes.Buffer
+	if err := FilterStream(strings.NewReader(input), &passed, nil, WithPassMalformed(true)); err != nil {
+		t.Fatal(err)
+	}
+	if passed.String() != input+"\n" {
+		t.Errorf("Expected malformed lines to pass through, got: %q", passed.String())
+	}
+}
+
+func TestFilterStream_RoundTripFromJSONHandler(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger.Debug("first", "job_id", "debug_1", "count", 3)
+	logger.Debug("second", "job_id", "other")
+	logger.Info("third")
+
+	filters := []LogFilter{
+		{Type: "count", Pattern: "3", Level: "debug", Enabled: true},
+	}
+
+	var out bytes.Buffer
+	if err := FilterStream(&logs, &out, filters); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "first") || !strings.Contains(lines[1], "third") {
+		t.Errorf("Expected first and third records, got: %s", out.String())
+	}
+}
+
+func TestFilterStream_Options(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"DEBUG","msg":"elevated","job_id":"debug_1"}`,
+		`{"level":"INFO","msg":"unmatched"}`,
+		`{"level":"WARN","msg":"warning"}`,
+	}, "\n")
+	filters := []LogFilter{
+		{Type: "job_id", Pattern: "debug_*", Level: "debug", Enabled: true},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"no options", nil, []string{"elevated", "unmatched", "warning"}},
+		{"level floor", []Option{WithLevelFloor(slog.LevelInfo)}, []string{"unmatched", "warning"}},
+		{"allow-list mode", []Option{WithAllowListMode(true)}, []string{"elevated"}},
+		{"base filters", []Option{WithBaseFilters([]LogFilter{{Type: "message", Pattern: "unmatched", Level: "error", Enabled: true}})}, []string{"elevated", "warning"}},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := FilterStream(strings.NewReader(input), &out, filters, tt.opts...); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if _, msg, ok := strings.Cut(line, `"msg":"`); ok {
+				got = append(got, msg[:strings.IndexByte(msg, '"')])
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFilterStream_NestedObjects(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger.WithGroup("http").Debug("grouped", slog.Group("req", "id", 7, "tags", []string{"a"}))
+	logger.Debug("other", slog.Group("http", slog.Group("req", "id", 8)))
+	logger.Debug("tagged", "labels", map[string]string{"env": "prod"})
+
+	filters := []LogFilter{
+		{Type: "http.req.id", Pattern: "7", Level: "debug", Enabled: true},
+		{Type: "labels.env", Pattern: "prod", Level: "debug", Enabled: true},
+	}
+
+	var out bytes.Buffer
+	if err := FilterStream(&logs, &out, filters); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{`"msg":"grouped"`, `"msg":"tagged"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s to survive, got: %s", want, got)
+		}
+	}
+	if strings.Contains(got, `"msg":"other"`) {
+		t.Errorf("Expected the mismatched nested value to be suppressed, got: %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+package logfilter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// WithSuppressedBuffer retains the last n records the handler suppresses, so
+// that the debug output leading up to a problem can be recovered: call
+// FlushSuppressed to replay them through the inner handler, or enable
+// WithFlushSuppressedOnError to replay them before each error. Older records
+// are discarded as newer ones arrive. Zero or less disables the buffer.
+//
+// Unlike WithCaptureOnError, which keeps records per context ID, the buffer is
+// shared by every record. Because every record must reach Handle to be
+// buffered, this mode disables slog's Enabled short-circuit for levels below
+// the global level.
+func WithSuppressedBuffer(n int) Option {
+	return func(o *options) {
+		o.suppressedSize = n
+	}
+}
+
+// WithFlushSuppressedOnError makes the handler replay the records held by
+// WithSuppressedBuffer whenever an error-level record is emitted, just before
+// the error itself. It has no effect without WithSuppressedBuffer.
+func WithFlushSuppressedOnError(enabled bool) Option {
+	return func(o *options) {
+		o.flushSuppressedOnError = enabled
+	}
+}
+
+// suppressedBuffer is a ring of the most recently suppressed records.
+type suppressedBuffer struct {
+	flushOnError bool
+
+	mu      sync.Mutex
+	records []capturedRecord
+	next    int  // Index of the slot the next record goes to
+	full    bool // True once the buffer has wrapped
+}
+
+// newSuppressedBuffer creates a buffer retaining up to size records.
+func newSuppressedBuffer(size int, flushOnError bool) *suppressedBuffer {
+	return &suppressedBuffer{flushOnError: flushOnError, records: make([]capturedRecord, size)}
+}
+
+// hold stores a suppressed record, evicting the oldest when full.
+func (b *suppressedBuffer) hold(handler slog.Handler, r slog.Record) {
+	cr := capturedRecord{handler: handler, record: r.Clone()}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records[b.next] = cr
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// take removes and returns the held records, oldest first.
+func (b *suppressedBuffer) take() []capturedRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []capturedRecord
+	if b.full {
+		out = append(append(out, b.records[b.next:]...), b.records[:b.next]...)
+	} else {
+		out = append(out, b.records[:b.next]...)
+	}
+	clear(b.records)
+	b.next = 0
+	b.full = false
+	return out
+}
+
+// replay emits and discards the held records, joining any errors.
+func (b *suppressedBuffer) replay(ctx context.Context) error {
+	var errs []error
+	for _, cr := range b.take() {
+		if err := cr.handler.Handle(ctx, cr.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FlushSuppressed replays the records held by WithSuppressedBuffer through
+// the inner handler, oldest first and at their original levels, and empties
+// the buffer. Errors from the inner handler are joined. It does nothing if
+// the buffer isn't enabled, and returns ErrHandlerClosed once the handler has
+// been closed.
+func (h *Handler) FlushSuppressed() error {
+	h.lifecycle.RLock()
+	defer h.lifecycle.RUnlock()
+	if h.closed.Load() {
+		return ErrHandlerClosed
+	}
+	if h.suppressedBuf == nil {
+		return nil
+	}
+	return h.suppressedBuf.replay(context.Background())
+}
+
+// FlushSuppressed replays the global handler's suppressed-record buffer.
+func FlushSuppressed() error {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.FlushSuppressed()
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandler_FlushSuppressed(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithSuppressedBuffer(10))
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Debug("connecting")
+	logger.Debug("retrying")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected debug records to be suppressed, got: %s", buf.String())
+	}
+
+	if err := handler.FlushSuppressed(); err != nil {
+		t.Fatalf("Expected flush to succeed, got %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=DEBUG msg=connecting component=db") {
+		t.Errorf("Expected suppressed record with its logger attributes, got: %s", out)
+	}
+	if strings.Index(out, "connecting") > strings.Index(out, "retrying") {
+		t.Errorf("Expected records replayed oldest first, got: %s", out)
+	}
+
+	// The buffer is emptied by a flush.
+	buf.Reset()
+	if err := handler.FlushSuppressed(); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected second flush to emit nothing, got %v: %s", err, buf.String())
+	}
+}
+
+func TestHandler_FlushSuppressed_Bounded(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithSuppressedBuffer(3))
+	logger := slog.New(handler)
+
+	for _, msg := range []string{"one", "two", "three", "four", "five"} {
+		logger.Debug(msg)
+	}
+	logger.Info("emitted")
+	buf.Reset()
+
+	if err := handler.FlushSuppressed(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected the last 3 suppressed records, got: %s", buf.String())
+	}
+	for i, msg := range []string{"three", "four", "five"} {
+		if !strings.Contains(lines[i], "msg="+msg) {
+			t.Errorf("Expected line %d to be %q, got: %s", i, msg, lines[i])
+		}
+	}
+}
+
+func TestHandler_FlushSuppressedOnError(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithSuppressedBuffer(10), WithFlushSuppressedOnError(true))
+	logger := slog.New(handler)
+
+	logger.Debug("loaded config")
+	logger.Warn("slow query")
+	if strings.Contains(buf.String(), "loaded config") {
+		t.Fatalf("Expected debug record held until an error, got: %s", buf.String())
+	}
+
+	logger.Error("request failed")
+	out := buf.String()
+	if !strings.Contains(out, "loaded config") {
+		t.Fatalf("Expected suppressed record replayed on error, got: %s", out)
+	}
+	if strings.Index(out, "loaded config") > strings.Index(out, "request failed") {
+		t.Errorf("Expected suppressed record before the error, got: %s", out)
+	}
+}
+
+func TestHandler_SuppressedBuffer_Disabled(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	if err := handler.FlushSuppressed(); err != nil {
+		t.Errorf("Expected flush without a buffer to do nothing, got %v", err)
+	}
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug to stay disabled without a buffer")
+	}
+
+	handler = NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithSuppressedBuffer(1))
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug enabled so suppressed records can be buffered")
+	}
+	_ = handler.Close()
+	if err := handler.FlushSuppressed(); err != ErrHandlerClosed {
+		t.Errorf("Expected ErrHandlerClosed after Close, got %v", err)
+	}
+}
+
+func TestHandler_SuppressedBuffer_Concurrent(t *testing.T) {
+	var buf syncBuffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level,
+		WithSuppressedBuffer(16))
+	logger := slog.New(handler)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				logger.Debug("tick", "i", i)
+				if i%50 == 0 {
+					_ = handler.FlushSuppressed()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := handler.FlushSuppressed(); err != nil {
+		t.Errorf("Expected final flush to succeed, got %v", err)
+	}
+}
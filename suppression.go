@@ -0,0 +1,116 @@
+package logfilter
+
+import (
+	"log/slog"
+)
+
+// Suppression is a policy-level rule that drops records below warn carrying
+// a given attribute, such as health checks or metrics scrapes. Suppressions
+// are evaluated before base and regular filters, independent of first match
+// wins: a record matching any suppression is dropped below warn even if a
+// filter would have emitted it, including catch-all elevation filters.
+type Suppression struct {
+	// Key is the attribute key to match, e.g. "path".
+	Key string `json:"key" yaml:"key" toml:"key"`
+
+	// Pattern matches the attribute value, with the same syntax as LogFilter.Pattern.
+	Pattern string `json:"pattern" yaml:"pattern" toml:"pattern"`
+
+	// AllowOverride lets filters take precedence: a record matching this
+	// suppression is then decided by the first matching filter as usual, and
+	// only dropped if no filter matches.
+	AllowOverride bool `json:"allow_override,omitempty" yaml:"allow_override,omitempty" toml:"allow_override,omitempty"`
+
+	matcher Matcher // Cached compiled Pattern
+}
+
+// SetGlobalSuppressions replaces the handler's suppressions. Suppressions
+// are shared by all handlers derived via WithAttrs/WithGroup.
+func (h *Handler) SetGlobalSuppressions(suppressions []Suppression) {
+	prepared := make([]Suppression, len(suppressions))
+	for i, s := range suppressions {
+		s.matcher = NewMatcher(s.Pattern)
+		prepared[i] = s
+	}
+
+	h.filtersLock.Lock()
+	defer h.filtersLock.Unlock()
+	h.suppressions = prepared
+	h.updateAttrKeys()
+}
+
+// GetGlobalSuppressions returns a copy of the handler's suppressions.
+func (h *Handler) GetGlobalSuppressions() []Suppression {
+	h.filtersLock.RLock()
+	defer h.filtersLock.RUnlock()
+
+	out := make([]Suppression, len(h.suppressions))
+	copy(out, h.suppressions)
+	return out
+}
+
+// ClearGlobalSuppressions removes all suppressions.
+func (h *Handler) ClearGlobalSuppressions() {
+	h.filtersLock.Lock()
+	defer h.filtersLock.Unlock()
+	h.suppressions = nil
+	h.updateAttrKeys()
+}
+
+// matchSuppression returns the first suppression matching the record's
+// attributes, or nil. Records at warn or above are never suppressed. A slice
+// attribute matches if any element does.
+func matchSuppression(suppressions []Suppression, level slog.Level, v *recordView) *Suppression {
+	if level >= slog.LevelWarn || len(suppressions) == 0 {
+		return nil
+	}
+	for i := range suppressions {
+		s := &suppressions[i]
+		if elems, ok := v.attrElems(s.Key); ok {
+			for _, e := range elems {
+				if s.matcher.Match(e) {
+					return s
+				}
+			}
+			continue
+		}
+		if value, ok := v.attr(s.Key); ok && s.matcher.Match(value) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SetGlobalSuppressions replaces the global handler's suppressions.
+func SetGlobalSuppressions(suppressions []Suppression) {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.SetGlobalSuppressions(suppressions)
+	}
+}
+
+// GetGlobalSuppressions returns a copy of the global handler's suppressions.
+func GetGlobalSuppressions() []Suppression {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.GetGlobalSuppressions()
+	}
+	return nil
+}
+
+// ClearGlobalSuppressions removes all suppressions from the global handler.
+func ClearGlobalSuppressions() {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		h.ClearGlobalSuppressions()
+	}
+}
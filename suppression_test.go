@@ -0,0 +1,78 @@
+package logfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_GlobalSuppressions(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		// Catch-all elevation must not bypass the suppression
+		{Type: "path", Pattern: "*", Level: "debug", OutputLevel: "info", Enabled: true},
+	})
+	handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/healthz"}})
+
+	logger := slog.New(handler)
+	logger.Info("probe", "path", "/healthz")
+	logger.Debug("probe debug", "path", "/healthz")
+	logger.Warn("probe slow", "path", "/healthz")
+	logger.Info("request", "path", "/api")
+
+	out := buf.String()
+	if strings.Contains(out, "msg=probe ") || strings.Contains(out, "probe debug") {
+		t.Errorf("Expected sub-warn health checks to be suppressed, got: %s", out)
+	}
+	if !strings.Contains(out, "probe slow") {
+		t.Errorf("Expected warn health check to pass, got: %s", out)
+	}
+	if !strings.Contains(out, "msg=request") {
+		t.Errorf("Expected other paths to pass, got: %s", out)
+	}
+}
+
+func TestHandler_GlobalSuppressions_AllowOverride(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "probe", Pattern: "deep", Level: "debug", Enabled: true},
+	})
+	handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/healthz", AllowOverride: true}})
+
+	logger := slog.New(handler)
+	logger.Info("shallow", "path", "/healthz")
+	logger.Info("deep", "path", "/healthz", "probe", "deep")
+
+	out := buf.String()
+	if strings.Contains(out, "shallow") {
+		t.Errorf("Expected unmatched record to stay suppressed, got: %s", out)
+	}
+	if !strings.Contains(out, "msg=deep") {
+		t.Errorf("Expected matching filter to override suppression, got: %s", out)
+	}
+}
+
+func TestHandler_GlobalSuppressions_Introspection(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+
+	handler.SetGlobalSuppressions([]Suppression{{Key: "path", Pattern: "/metrics"}})
+	got := handler.GetGlobalSuppressions()
+	if len(got) != 1 || got[0].Key != "path" || got[0].Pattern != "/metrics" {
+		t.Errorf("Expected one suppression, got %+v", got)
+	}
+
+	handler.ClearGlobalSuppressions()
+	if n := len(handler.GetGlobalSuppressions()); n != 0 {
+		t.Errorf("Expected no suppressions after clear, got %d", n)
+	}
+}
@@ -0,0 +1,105 @@
+package logfilter
+
+// EnableTag enables every filter whose Tag is tag, e.g. all "payments" debug
+// filters at once, and returns how many filters carry the tag. Base filters
+// and filter groups (SetFilterGroups) are not searched.
+func (h *Handler) EnableTag(tag string) int {
+	return h.setTagEnabled(tag, true)
+}
+
+// DisableTag disables every filter whose Tag is tag, keeping them for a
+// later EnableTag, and returns how many filters carry the tag.
+func (h *Handler) DisableTag(tag string) int {
+	return h.setTagEnabled(tag, false)
+}
+
+// setTagEnabled sets Enabled on every filter whose Tag is tag. Runtime
+// state, such as MaxHits counts, is kept.
+func (h *Handler) setTagEnabled(tag string, enabled bool) int {
+	if tag == "" {
+		return 0
+	}
+
+	h.filtersLock.Lock()
+	defer h.filtersLock.Unlock()
+
+	// Copy rather than write in place: records being evaluated may still
+	// hold the old slice.
+	filters := make([]LogFilter, len(h.filters))
+	copy(filters, h.filters)
+	n := 0
+	for i := range filters {
+		if filters[i].Tag == tag {
+			filters[i].Enabled = enabled
+			n++
+		}
+	}
+	if n > 0 {
+		h.filters = filters
+		h.updateLowestLevel()
+	}
+	return n
+}
+
+// RemoveTag removes every filter whose Tag is tag and returns how many were
+// removed.
+func (h *Handler) RemoveTag(tag string) int {
+	if tag == "" {
+		return 0
+	}
+
+	h.filtersLock.Lock()
+	defer h.filtersLock.Unlock()
+
+	filtered := make([]LogFilter, 0, len(h.filters))
+	for _, f := range h.filters {
+		if f.Tag != tag {
+			filtered = append(filtered, f)
+		}
+	}
+	n := len(h.filters) - len(filtered)
+	if n > 0 {
+		h.filters = filtered
+		h.updateLowestLevel()
+	}
+	return n
+}
+
+// EnableTag enables the global handler's filters tagged tag (see
+// Handler.EnableTag).
+func EnableTag(tag string) int {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.EnableTag(tag)
+	}
+	return 0
+}
+
+// DisableTag disables the global handler's filters tagged tag (see
+// Handler.DisableTag).
+func DisableTag(tag string) int {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.DisableTag(tag)
+	}
+	return 0
+}
+
+// RemoveTag removes the global handler's filters tagged tag (see
+// Handler.RemoveTag).
+func RemoveTag(tag string) int {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.RemoveTag(tag)
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// enabledTypes returns the Type of each enabled filter, in order.
+func enabledTypes(filters []LogFilter) []string {
+	var types []string
+	for _, f := range filters {
+		if f.Enabled {
+			types = append(types, f.Type)
+		}
+	}
+	return types
+}
+
+func TestHandler_TagToggle(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "payment_id", Pattern: "*", Level: "debug", Tag: "payments", Confirmed: true},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "refund_id", Pattern: "*", Level: "debug", Tag: "payments", Enabled: true, Confirmed: true},
+		{Type: "order_id", Pattern: "*", Level: "debug", Tag: "orders", Confirmed: true},
+	})
+
+	tests := []struct {
+		name    string
+		toggle  func() int
+		n       int
+		enabled []string
+	}{
+		{"enable tag", func() int { return handler.EnableTag("payments") }, 2, []string{"payment_id", "job_id", "refund_id"}},
+		{"enable again", func() int { return handler.EnableTag("payments") }, 2, []string{"payment_id", "job_id", "refund_id"}},
+		{"disable tag", func() int { return handler.DisableTag("payments") }, 2, []string{"job_id"}},
+		{"enable other tag", func() int { return handler.EnableTag("orders") }, 1, []string{"job_id", "order_id"}},
+		{"unknown tag", func() int { return handler.DisableTag("shipping") }, 0, []string{"job_id", "order_id"}},
+		{"empty name", func() int { return handler.DisableTag("") }, 0, []string{"job_id", "order_id"}},
+	}
+
+	for _, tt := range tests {
+		if n := tt.toggle(); n != tt.n {
+			t.Errorf("%s: expected %d filters with the tag, got %d", tt.name, tt.n, n)
+		}
+		if got := enabledTypes(handler.GetFilters()); !slices.Equal(got, tt.enabled) {
+			t.Errorf("%s: expected enabled %v, got %v", tt.name, tt.enabled, got)
+		}
+	}
+	if got := filterTypes(handler.GetFilters()); !slices.Equal(got, []string{"payment_id", "job_id", "refund_id", "order_id"}) {
+		t.Errorf("Expected toggling to keep the filters and their order, got %v", got)
+	}
+}
+
+func TestHandler_TagToggle_Emit(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewHandler(inner, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "payment_id", Pattern: "pay_*", Level: "debug", Tag: "payments"},
+	})
+	logger := slog.New(handler)
+
+	tests := []struct {
+		name   string
+		toggle func()
+		want   bool
+	}{
+		{"disabled", func() {}, false},
+		{"enabled", func() { handler.EnableTag("payments") }, true},
+		{"disabled again", func() { handler.DisableTag("payments") }, false},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		tt.toggle()
+		logger.Debug("charge", "payment_id", "pay_1")
+		if got := strings.Contains(buf.String(), "charge"); got != tt.want {
+			t.Errorf("%s: expected emitted=%v, got: %s", tt.name, tt.want, buf.String())
+		}
+		if got := handler.Enabled(context.Background(), slog.LevelDebug); got != tt.want {
+			t.Errorf("%s: expected Enabled(debug)=%v after recomputing the lowest level, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestHandler_TagToggle_KeepsState(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "payment_id", Pattern: "pay_*", Level: "debug", Tag: "payments", MaxHits: 1, Enabled: true},
+	})
+	logger := slog.New(handler)
+	logger.Debug("charge", "payment_id", "pay_1")
+
+	handler.DisableTag("payments")
+	handler.EnableTag("payments")
+	if active := handler.GetActiveFilters(); len(active) != 0 {
+		t.Errorf("Expected the exhausted filter to stay exhausted across toggles, got %+v", active)
+	}
+}
+
+func TestHandler_RemoveTag(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "payment_id", Pattern: "pay_*", Level: "debug", Tag: "payments", Enabled: true},
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "refund_id", Pattern: "ref_*", Level: "debug", Tag: "payments"},
+	})
+
+	if n := handler.RemoveTag("payments"); n != 2 {
+		t.Errorf("Expected 2 filters removed, got %d", n)
+	}
+	if got := filterTypes(handler.GetFilters()); !slices.Equal(got, []string{"job_id"}) {
+		t.Errorf("Expected only the untagged filter to remain, got %v", got)
+	}
+	if n := handler.RemoveTag("payments"); n != 0 {
+		t.Errorf("Expected nothing left to remove, got %d", n)
+	}
+	if n := handler.RemoveTag(""); n != 0 {
+		t.Errorf("Expected the empty tag to remove nothing, got %d", n)
+	}
+}
+
+func TestTagToggle_Global(t *testing.T) {
+	_ = New()
+	SetFilters([]LogFilter{
+		{Type: "payment_id", Pattern: "pay_*", Level: "debug", Tag: "payments"},
+		{Type: "job_id", Pattern: "job_*", Level: "debug"},
+	})
+
+	if n := EnableTag("payments"); n != 1 {
+		t.Errorf("Expected 1 filter enabled, got %d", n)
+	}
+	if got := enabledTypes(GetFilters()); !slices.Equal(got, []string{"payment_id"}) {
+		t.Errorf("Expected only the tagged filter enabled, got %v", got)
+	}
+	if n := DisableTag("payments"); n != 1 {
+		t.Errorf("Expected 1 filter disabled, got %d", n)
+	}
+	if n := RemoveTag("payments"); n != 1 {
+		t.Errorf("Expected 1 filter removed, got %d", n)
+	}
+	if got := filterTypes(GetFilters()); !slices.Equal(got, []string{"job_id"}) {
+		t.Errorf("Expected the other filter to remain, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package logfilter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// teeHandler fans records out to several handlers.
+type teeHandler struct {
+	handlers []slog.Handler
+}
+
+// NewTeeHandler returns a handler that sends each record to every one of
+// handlers that is enabled for its level, e.g. JSON to a file and text to the
+// console. Wrapped by a filter handler (see NewHandler and WithHandler), a
+// record the filters suppress reaches none of them. Errors from the handlers
+// are joined, and closing the tee closes each handler that is an io.Closer.
+// Each handler's own level still applies, so it must admit the levels filters
+// elevate records to.
+func NewTeeHandler(handlers ...slog.Handler) slog.Handler {
+	return &teeHandler{handlers: handlers}
+}
+
+// Enabled reports whether any of the handlers is enabled for level.
+func (t *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle sends r to each handler enabled for its level.
+func (t *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a tee of each handler's WithAttrs.
+func (t *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(t.handlers))
+	for i, h := range t.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &teeHandler{handlers: handlers}
+}
+
+// WithGroup returns a tee of each handler's WithGroup.
+func (t *teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(t.handlers))
+	for i, h := range t.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &teeHandler{handlers: handlers}
+}
+
+// Close closes each handler that implements io.Closer, joining their errors,
+// so that closing the filter handler closes every destination.
+func (t *teeHandler) Close() error {
+	var errs []error
+	for _, h := range t.handlers {
+		if c, ok := h.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,111 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTeeHandler(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	tee := NewTeeHandler(
+		slog.NewJSONHandler(&jsonBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewTextHandler(&textBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	handler := NewHandler(tee, level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true},
+		{Type: "service", Pattern: "noisy", Level: "error", Enabled: true},
+	})
+	logger := slog.New(handler).WithGroup("req").With("id", "r1")
+
+	logger.Debug("elevated", "job_id", "job_1")
+	if !strings.Contains(jsonBuf.String(), `"msg":"elevated"`) || !strings.Contains(jsonBuf.String(), `"req":{"id":"r1","job_id":"job_1"}`) {
+		t.Errorf("Expected elevated record with group in JSON output, got: %s", jsonBuf.String())
+	}
+	if !strings.Contains(textBuf.String(), "msg=elevated req.id=r1 req.job_id=job_1") {
+		t.Errorf("Expected elevated record with group in text output, got: %s", textBuf.String())
+	}
+	if n := strings.Count(jsonBuf.String(), "\n"); n != 1 {
+		t.Errorf("Expected one JSON line, got %d", n)
+	}
+	if n := strings.Count(textBuf.String(), "\n"); n != 1 {
+		t.Errorf("Expected one text line, got %d", n)
+	}
+
+	jsonBuf.Reset()
+	textBuf.Reset()
+	logger.Info("suppressed", "service", "noisy")
+	logger.Debug("dropped", "job_id", "task_1")
+	if jsonBuf.Len() > 0 || textBuf.Len() > 0 {
+		t.Errorf("Expected suppressed records in neither output, got JSON %q, text %q", jsonBuf.String(), textBuf.String())
+	}
+}
+
+func TestTeeHandler_PerHandlerLevel(t *testing.T) {
+	var debugBuf, warnBuf bytes.Buffer
+	tee := NewTeeHandler(
+		slog.NewTextHandler(&debugBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewTextHandler(&warnBuf, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	)
+
+	if !tee.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected tee enabled at debug when any handler is")
+	}
+
+	logger := slog.New(tee)
+	logger.Info("ready")
+	logger.Warn("slow")
+	if !strings.Contains(debugBuf.String(), "msg=ready") || !strings.Contains(debugBuf.String(), "msg=slow") {
+		t.Errorf("Expected both records in debug output, got: %s", debugBuf.String())
+	}
+	if strings.Contains(warnBuf.String(), "msg=ready") || !strings.Contains(warnBuf.String(), "msg=slow") {
+		t.Errorf("Expected only the warning in warn output, got: %s", warnBuf.String())
+	}
+}
+
+// failingHandler fails every record with err.
+type failingHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+func TestTeeHandler_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	tee := NewTeeHandler(
+		failingHandler{Handler: slog.NewTextHandler(&buf, nil), err: errA},
+		slog.NewTextHandler(&buf, nil),
+		failingHandler{Handler: slog.NewTextHandler(&buf, nil), err: errB},
+	)
+
+	err := tee.Handle(context.Background(), slog.NewRecord(now(), slog.LevelInfo, "msg", 0))
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Expected both errors joined, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "msg=msg") {
+		t.Errorf("Expected the healthy handler to still receive the record, got: %s", buf.String())
+	}
+}
+
+func TestTeeHandler_Close(t *testing.T) {
+	a := &closingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil)}
+	b := &closingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil)}
+	level := new(slog.LevelVar)
+	handler := NewHandler(NewTeeHandler(a, slog.NewTextHandler(&bytes.Buffer{}, nil), b), level)
+
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if !a.closed || !b.closed {
+		t.Errorf("Expected every closable handler closed, got a=%v b=%v", a.closed, b.closed)
+	}
+}
@@ -0,0 +1,78 @@
+package logfilter
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxThrottleValues bounds the number of distinct values a single filter
+// tracks for ThrottlePerValue. When exceeded, the least recently emitted
+// value is forgotten, so it may emit again before its interval elapses.
+const maxThrottleValues = 10000
+
+// filterState is runtime state attached to a filter by prepare. It survives
+// re-preparation and is shared by copies of the filter (e.g. from GetFilters),
+// so passing a filter back to SetFilters keeps its state, as long as the copy
+// still has the FilterID recorded in owner.
+type filterState struct {
+	matches  atomic.Uint64  // Records this filter decided
+	hits     atomic.Int64   // Records counted against MaxHits; not reset by ResetStats
+	throttle *valueThrottle // Non-nil when ThrottlePerValue > 0
+	rate     *rateLimiter   // Non-nil when MaxPerSecond > 0
+	owner    string         // stateKey of the filter it belongs to; set by updateLowestLevel under filtersLock
+}
+
+// valueThrottle tracks the last emit time per matched value.
+type valueThrottle struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element // value -> element in order
+	order   *list.List               // Most recently emitted at front
+}
+
+// throttleEntry is the last emit time for one value.
+type throttleEntry struct {
+	value string
+	last  time.Time
+}
+
+// newValueThrottle creates an empty throttle.
+func newValueThrottle() *valueThrottle {
+	return &valueThrottle{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// allow reports whether a record with the given value at time now may be
+// emitted, given the interval, and records the emit if so.
+func (t *valueThrottle) allow(value string, now time.Time, interval time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.entries[value]; ok {
+		e := el.Value.(*throttleEntry)
+		if now.Sub(e.last) < interval {
+			return false
+		}
+		e.last = now
+		t.order.MoveToFront(el)
+		return true
+	}
+
+	if t.order.Len() >= maxThrottleValues {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(*throttleEntry).value)
+	}
+	t.entries[value] = t.order.PushFront(&throttleEntry{value: value, last: now})
+	return true
+}
+
+// len returns the number of tracked values.
+func (t *valueThrottle) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.order.Len()
+}
@@ -0,0 +1,94 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_ThrottlePerValue(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", Enabled: true, ThrottlePerValue: 5 * time.Second},
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	emit := func(offset time.Duration, jobID, msg string) {
+		r := slog.NewRecord(start.Add(offset), slog.LevelDebug, msg, 0)
+		r.AddAttrs(slog.String("job_id", jobID))
+		if err := handler.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	emit(0, "job_1", "a1")             // Emitted: first for job_1
+	emit(time.Second, "job_1", "a2")   // Throttled
+	emit(time.Second, "job_2", "b1")   // Emitted: different value
+	emit(4*time.Second, "job_1", "a3") // Throttled
+	emit(5*time.Second, "job_1", "a4") // Emitted: interval elapsed
+	emit(6*time.Second, "job_2", "b2") // Emitted: 5s after b1
+
+	out := buf.String()
+	for _, msg := range []string{"a1", "b1", "a4", "b2"} {
+		if !strings.Contains(out, "msg="+msg) {
+			t.Errorf("Expected %s to be emitted, got: %s", msg, out)
+		}
+	}
+	for _, msg := range []string{"a2", "a3"} {
+		if strings.Contains(out, "msg="+msg) {
+			t.Errorf("Expected %s to be throttled, got: %s", msg, out)
+		}
+	}
+}
+
+func TestHandler_ThrottlePerValue_StateSurvivesAddFilter(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true, ThrottlePerValue: time.Minute},
+	})
+
+	logger := slog.New(handler)
+	logger.Debug("first", "job_id", "j")
+
+	// Adding an unrelated filter re-prepares the list but keeps throttle state
+	handler.AddFilter(LogFilter{Type: "other", Pattern: "x", Level: "debug", Enabled: true})
+
+	buf.Reset()
+	logger.Debug("second", "job_id", "j")
+	if buf.Len() > 0 {
+		t.Errorf("Expected throttle state to survive AddFilter, got: %s", buf.String())
+	}
+}
+
+func TestValueThrottle_Bounded(t *testing.T) {
+	th := newValueThrottle()
+	now := time.Now()
+
+	for i := 0; i < maxThrottleValues+5; i++ {
+		th.allow(fmt.Sprintf("v%d", i), now, time.Hour)
+	}
+	if th.len() != maxThrottleValues {
+		t.Errorf("Expected %d tracked values, got %d", maxThrottleValues, th.len())
+	}
+
+	// The oldest value was evicted, so it is allowed again immediately
+	if !th.allow("v0", now, time.Hour) {
+		t.Error("Expected evicted value to be allowed")
+	}
+	// A recent value is still throttled
+	if th.allow(fmt.Sprintf("v%d", maxThrottleValues+4), now, time.Hour) {
+		t.Error("Expected recent value to be throttled")
+	}
+}
@@ -0,0 +1,117 @@
+package logfilter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow restricts a filter to records logged at certain times of day
+// (see LogFilter.TimeWindow), e.g. debug for a nightly batch only during its
+// maintenance window.
+type TimeWindow struct {
+	// Start and End are clock times, "15:04" or "15:04:05". The window runs
+	// from Start up to, but not including, End. If End is before Start the
+	// window wraps past midnight, e.g. "23:00" to "01:00"; if they are equal
+	// it covers the whole day.
+	Start string `json:"start" yaml:"start" toml:"start"`
+	End   string `json:"end" yaml:"end" toml:"end"`
+
+	// Weekdays optionally restricts the window to certain days, given as
+	// English day names or their three-letter abbreviations ("mon", "Sunday"),
+	// case-insensitive. A window wrapping past midnight belongs to the day it
+	// starts on, so "fri" 23:00 to 01:00 includes early Saturday. If empty,
+	// the window applies every day.
+	Weekdays []string `json:"weekdays,omitempty" yaml:"weekdays,omitempty" toml:"weekdays,omitempty"`
+
+	// Timezone is the IANA name of the location, such as "Europe/London",
+	// that Start, End and Weekdays are read in. If empty, each record's time
+	// is used in its own location, normally the local time zone.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty" toml:"timezone,omitempty"`
+}
+
+// timeWindow is a parsed TimeWindow.
+type timeWindow struct {
+	start, end int            // Seconds since midnight
+	days       uint8          // Bit per time.Weekday; 0 means every day
+	loc        *time.Location // nil uses the record time's location
+	invalid    bool           // The TimeWindow didn't parse; contains nothing
+}
+
+// parseTimeWindow parses w, returning the first problem found.
+func parseTimeWindow(w *TimeWindow) (*timeWindow, error) {
+	var tw timeWindow
+	var err error
+	if tw.start, err = parseClock(w.Start); err != nil {
+		return nil, fmt.Errorf("%w: start: %v", ErrInvalidTimeWindow, err)
+	}
+	if tw.end, err = parseClock(w.End); err != nil {
+		return nil, fmt.Errorf("%w: end: %v", ErrInvalidTimeWindow, err)
+	}
+	for _, name := range w.Weekdays {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidTimeWindow, name)
+		}
+		tw.days |= 1 << day
+	}
+	if w.Timezone != "" {
+		if tw.loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTimeWindow, err)
+		}
+	}
+	return &tw, nil
+}
+
+// parseClock parses a clock time as seconds since midnight.
+func parseClock(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range [...]string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour()*3600 + t.Minute()*60 + t.Second(), nil
+		}
+	}
+	return 0, fmt.Errorf("clock time %q, want HH:MM or HH:MM:SS", s)
+}
+
+// parseWeekday parses an English day name or its three-letter abbreviation.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// contains reports whether t falls within the window.
+func (w *timeWindow) contains(t time.Time) bool {
+	if w.invalid {
+		return false
+	}
+	if w.loc != nil {
+		t = t.In(w.loc)
+	}
+	secs := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	day := t.Weekday()
+
+	switch {
+	case w.start == w.end:
+		return w.onDay(day)
+	case w.start < w.end:
+		return secs >= w.start && secs < w.end && w.onDay(day)
+	case secs >= w.start:
+		return w.onDay(day)
+	case secs < w.end:
+		// The early hours of a window that started the day before.
+		return w.onDay((day + 6) % 7)
+	}
+	return false
+}
+
+// onDay reports whether the window applies on day.
+func (w *timeWindow) onDay(day time.Weekday) bool {
+	return w.days == 0 || w.days&(1<<day) != 0
+}
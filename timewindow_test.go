@@ -0,0 +1,117 @@
+package logfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestTimeWindow_Contains(t *testing.T) {
+	// 2024-01-05 is a Friday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		t      time.Time
+		want   bool
+	}{
+		{"inside", TimeWindow{Start: "02:00", End: "03:00"}, at(5, 2, 30), true},
+		{"at start", TimeWindow{Start: "02:00", End: "03:00"}, at(5, 2, 0), true},
+		{"at end", TimeWindow{Start: "02:00", End: "03:00"}, at(5, 3, 0), false},
+		{"before", TimeWindow{Start: "02:00", End: "03:00"}, at(5, 1, 59), false},
+		{"seconds", TimeWindow{Start: "02:00:30", End: "02:01"}, at(5, 2, 0), false},
+		{"wrap before midnight", TimeWindow{Start: "23:00", End: "01:00"}, at(5, 23, 30), true},
+		{"wrap after midnight", TimeWindow{Start: "23:00", End: "01:00"}, at(5, 0, 30), true},
+		{"wrap outside", TimeWindow{Start: "23:00", End: "01:00"}, at(5, 12, 0), false},
+		{"whole day", TimeWindow{Start: "00:00", End: "00:00"}, at(5, 12, 0), true},
+		{"weekday", TimeWindow{Start: "02:00", End: "03:00", Weekdays: []string{"fri"}}, at(5, 2, 30), true},
+		{"other weekday", TimeWindow{Start: "02:00", End: "03:00", Weekdays: []string{"Saturday", "sun"}}, at(5, 2, 30), false},
+		{"wrap keeps start day", TimeWindow{Start: "23:00", End: "01:00", Weekdays: []string{"fri"}}, at(6, 0, 30), true},
+		{"wrap other start day", TimeWindow{Start: "23:00", End: "01:00", Weekdays: []string{"fri"}}, at(5, 0, 30), false},
+		// 02:30 UTC is 03:30 in Paris (CET, UTC+1 in January).
+		{"timezone outside", TimeWindow{Start: "02:00", End: "03:00", Timezone: "Europe/Paris"}, at(5, 2, 30), false},
+		{"timezone inside", TimeWindow{Start: "02:00", End: "03:00", Timezone: "Europe/Paris"}, at(5, 1, 30), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := parseTimeWindow(&tt.window)
+			if err != nil {
+				t.Fatalf("Expected window to parse, got %v", err)
+			}
+			if got := w.contains(tt.t); got != tt.want {
+				t.Errorf("Expected contains(%v) = %v, got %v", tt.t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTimeWindow_Record(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	handler := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), level)
+	handler.SetFilters([]LogFilter{
+		NewFilter("job").Pattern("nightly").Level(slog.LevelDebug).
+			TimeWindow(TimeWindow{Start: "02:00", End: "03:00", Timezone: "UTC"}).Build(),
+	})
+
+	tests := []struct {
+		name  string
+		time  time.Time
+		emits bool
+	}{
+		{"inside window", time.Date(2024, 1, 5, 2, 15, 0, 0, time.UTC), true},
+		{"outside window", time.Date(2024, 1, 5, 4, 0, 0, 0, time.UTC), false},
+		{"inside in another zone", time.Date(2024, 1, 5, 3, 15, 0, 0, time.FixedZone("CET", 3600)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			r := slog.NewRecord(tt.time, slog.LevelDebug, "step", 0)
+			r.AddAttrs(slog.String("job", "nightly"))
+			if err := handler.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			if emitted := buf.Len() > 0; emitted != tt.emits {
+				t.Errorf("Expected emitted=%v, got output: %s", tt.emits, buf.String())
+			}
+		})
+	}
+}
+
+func TestTimeWindow_Validate(t *testing.T) {
+	for _, w := range []TimeWindow{
+		{Start: "2am", End: "03:00"},
+		{Start: "02:00", End: "24:00"},
+		{Start: "02:00", End: "03:00", Weekdays: []string{"funday"}},
+		{Start: "02:00", End: "03:00", Timezone: "Mars/Olympus"},
+	} {
+		f := LogFilter{Type: "job", Pattern: "nightly", Level: "debug", TimeWindow: &w, Enabled: true}
+		if err := f.Validate(); !errors.Is(err, ErrInvalidTimeWindow) {
+			t.Errorf("Expected ErrInvalidTimeWindow for %+v, got %v", w, err)
+		}
+
+		// An invalid window never matches rather than applying at all times.
+		f.prepare()
+		if f.window.contains(time.Now()) {
+			t.Errorf("Expected invalid window %+v to contain nothing", w)
+		}
+	}
+
+	f := LogFilter{Type: "job", Pattern: "nightly", Level: "debug", TimeWindow: &TimeWindow{Start: "23:00", End: "1:00", Weekdays: []string{"Mon"}}}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected valid window, got %v", err)
+	}
+	if DeriveFilterID(f) == DeriveFilterID(LogFilter{Type: "job", Pattern: "nightly", Level: "debug"}) {
+		t.Error("Expected TimeWindow to contribute to the derived ID")
+	}
+}
@@ -0,0 +1,163 @@
+package logfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors reported by LogFilter.Validate, wrapped with details. Test for them
+// with errors.Is.
+var (
+	ErrUnknownLevel        = errors.New("logfilter: unknown level")
+	ErrEmptyType           = errors.New("logfilter: empty filter type")
+	ErrEmptyPattern        = errors.New("logfilter: empty pattern")
+	ErrEmptyContextKey     = errors.New("logfilter: empty context key")
+	ErrUnknownSourceType   = errors.New("logfilter: unknown source filter type")
+	ErrInvalidPattern      = errors.New("logfilter: invalid pattern")
+	ErrUnknownMatch        = errors.New("logfilter: unknown match")
+	ErrUnknownMatchMode    = errors.New("logfilter: unknown match mode")
+	ErrUnknownMatchScope   = errors.New("logfilter: unknown match scope")
+	ErrInvalidTimeWindow   = errors.New("logfilter: invalid time window")
+	ErrNegativeLimit       = errors.New("logfilter: negative limit")
+	ErrUnconfirmedMatchAll = errors.New("logfilter: unconfirmed match-all filter")
+)
+
+// sourceTypePrefix is the common prefix of the source filter types.
+const sourceTypePrefix = "source:"
+
+// Validate reports configuration mistakes that would otherwise make the
+// filter behave unexpectedly rather than fail: an unknown Level,
+// OutputLevel or SourceLevels entry (which would become info), an empty Type or Pattern, a
+// "context:" or "any:" type without a key, a "source:" type other than
+// source:file and source:function, an invalid Regex or Numeric pattern, an
+// unknown Match, MatchMode or MatchScope, an invalid TimeWindow, or a
+// negative ThrottlePerValue, MaxPerSecond or MaxHits.
+// Conditions are checked in the same way. All problems found are returned,
+// joined.
+//
+// An empty Pattern is allowed with Negate (matching every present value) or
+// a presence MatchMode, and
+// a compound filter may leave Type and Pattern empty.
+func (f *LogFilter) Validate() error {
+	var errs []error
+	for _, level := range [...]struct{ field, value string }{{"level", f.Level}, {"output_level", f.OutputLevel}} {
+		if !validLevelName(level.value) {
+			errs = append(errs, fmt.Errorf("%w %q in %s", ErrUnknownLevel, level.value, level.field))
+		}
+	}
+	for _, l := range f.SourceLevels {
+		if _, err := ParseLevelStrict(l); err != nil {
+			errs = append(errs, fmt.Errorf("%w %q in source_levels", ErrUnknownLevel, l))
+		}
+	}
+
+	if f.Type != "" || len(f.Conditions) == 0 {
+		if err := validateTypePattern(f.Type, f.Pattern, f.Negate || presenceMode(f.MatchMode)); err != nil {
+			errs = append(errs, err)
+		}
+		if !validMatchMode(f.MatchMode) {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownMatchMode, f.MatchMode))
+		}
+		if !validMatchScope(f.MatchScope) {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownMatchScope, f.MatchScope))
+		}
+		if f.Pattern != "" && f.strictPattern() && !presenceMode(f.MatchMode) {
+			if _, err := f.compile(); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidPattern, err))
+			}
+		}
+	}
+	for i, c := range f.Conditions {
+		if err := validateTypePattern(c.Type, c.Pattern, presenceMode(c.MatchMode)); err != nil {
+			errs = append(errs, fmt.Errorf("condition %d: %w", i, err))
+		}
+		if !validMatchMode(c.MatchMode) {
+			errs = append(errs, fmt.Errorf("condition %d: %w %q", i, ErrUnknownMatchMode, c.MatchMode))
+		}
+		if !validMatchScope(c.MatchScope) {
+			errs = append(errs, fmt.Errorf("condition %d: %w %q", i, ErrUnknownMatchScope, c.MatchScope))
+		}
+	}
+
+	switch f.Match {
+	case "", ConditionsAll, ConditionsAny:
+	default:
+		errs = append(errs, fmt.Errorf("%w %q, want %q or %q", ErrUnknownMatch, f.Match, ConditionsAll, ConditionsAny))
+	}
+	if f.TimeWindow != nil {
+		if _, err := parseTimeWindow(f.TimeWindow); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if f.ThrottlePerValue < 0 {
+		errs = append(errs, fmt.Errorf("%w: throttle_per_value %v", ErrNegativeLimit, f.ThrottlePerValue))
+	}
+	if f.MaxPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("%w: max_per_second %d", ErrNegativeLimit, f.MaxPerSecond))
+	}
+	if f.MaxHits < 0 {
+		errs = append(errs, fmt.Errorf("%w: max_hits %d", ErrNegativeLimit, f.MaxHits))
+	}
+	return errors.Join(errs...)
+}
+
+// validateTypePattern checks a filter or condition's Type and Pattern.
+func validateTypePattern(typ, pattern string, negate bool) error {
+	switch {
+	case strings.TrimSpace(typ) == "":
+		return ErrEmptyType
+	case (strings.HasPrefix(typ, ContextPrefix) || strings.HasPrefix(typ, AnyPrefix)) && strings.TrimSpace(typ[strings.IndexByte(typ, ':')+1:]) == "":
+		return fmt.Errorf("%w in type %q", ErrEmptyContextKey, typ)
+	case strings.HasPrefix(typ, sourceTypePrefix) && typ != SourceFilePrefix && typ != SourceFunctionPrefix && typ != SourcePackagePrefix:
+		return fmt.Errorf("%w %q, want %q, %q or %q", ErrUnknownSourceType, typ, SourceFilePrefix, SourceFunctionPrefix, SourcePackagePrefix)
+	case pattern == "" && !negate:
+		return fmt.Errorf("%w for type %q", ErrEmptyPattern, typ)
+	}
+	return nil
+}
+
+// ValidateFilters validates each filter, returning one error per invalid
+// filter, identifying it by index, type and pattern. It returns nil if all
+// filters are valid.
+func ValidateFilters(filters []LogFilter) []error {
+	var errs []error
+	for i := range filters {
+		if err := filters[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("filter %d (type %q, pattern %q): %w", i, filters[i].Type, filters[i].Pattern, err))
+		}
+	}
+	return errs
+}
+
+// SetFiltersStrict is like SetFilters, but first validates the filters and
+// leaves the current filters unchanged if any is invalid, returning the
+// problems joined. If the handler was created with WithRejectMatchAll(true),
+// unconfirmed catch-all filters are errors too (ErrUnconfirmedMatchAll),
+// rather than being dropped.
+func (h *Handler) SetFiltersStrict(filters []LogFilter) error {
+	errs := ValidateFilters(filters)
+	if _, rejected := h.screenFilters(filters); len(rejected) > 0 {
+		for _, f := range rejected {
+			errs = append(errs, fmt.Errorf("%w (type %q, pattern %q)", ErrUnconfirmedMatchAll, f.Type, f.Pattern))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	h.SetFilters(filters)
+	return nil
+}
+
+// SetFiltersStrict validates filters and, if all are valid, sets them on the
+// global handler. See Handler.SetFiltersStrict.
+func SetFiltersStrict(filters []LogFilter) error {
+	defaultHandlerLock.RLock()
+	h := defaultHandler
+	defaultHandlerLock.RUnlock()
+
+	if h != nil {
+		return h.SetFiltersStrict(filters)
+	}
+	return errors.Join(ValidateFilters(filters)...)
+}
@@ -0,0 +1,145 @@
+package logfilter
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  LogFilter
+		wantErr error
+	}{
+		{"unknown level", LogFilter{Type: "job_id", Pattern: "x", Level: "debg"}, ErrUnknownLevel},
+		{"unknown output level", LogFilter{Type: "job_id", Pattern: "x", Level: "debug", OutputLevel: "inf"}, ErrUnknownLevel},
+		{"empty type", LogFilter{Pattern: "x", Level: "debug"}, ErrEmptyType},
+		{"blank type", LogFilter{Type: "  ", Pattern: "x", Level: "debug"}, ErrEmptyType},
+		{"empty pattern", LogFilter{Type: "job_id", Level: "debug"}, ErrEmptyPattern},
+		{"empty context key", LogFilter{Type: "context:", Pattern: "x", Level: "debug"}, ErrEmptyContextKey},
+		{"empty any key", LogFilter{Type: "any:", Pattern: "x", Level: "debug"}, ErrEmptyContextKey},
+		{"unknown source type", LogFilter{Type: "source:line", Pattern: "42", Level: "debug"}, ErrUnknownSourceType},
+		{"misspelled source type", LogFilter{Type: "source:fil", Pattern: "x", Level: "debug"}, ErrUnknownSourceType},
+		{"invalid regex", LogFilter{Type: "job_id", Pattern: "job_(", Regex: true, Level: "debug"}, ErrInvalidPattern},
+		{"invalid numeric", LogFilter{Type: "ms", Pattern: ">fast", Numeric: true, Level: "debug"}, ErrInvalidPattern},
+		{"unknown match", LogFilter{Type: "job_id", Pattern: "x", Match: "both", Conditions: []Condition{{Type: "env", Pattern: "prod"}}}, ErrUnknownMatch},
+		{"condition empty type", LogFilter{Type: "job_id", Pattern: "x", Conditions: []Condition{{Pattern: "prod"}}}, ErrEmptyType},
+		{"condition empty pattern", LogFilter{Type: "job_id", Pattern: "x", Conditions: []Condition{{Type: "env"}}}, ErrEmptyPattern},
+		{"condition empty context key", LogFilter{Conditions: []Condition{{Type: "context:", Pattern: "x"}}}, ErrEmptyContextKey},
+		{"negative throttle", LogFilter{Type: "job_id", Pattern: "x", ThrottlePerValue: -time.Second}, ErrNegativeLimit},
+		{"negative rate", LogFilter{Type: "job_id", Pattern: "x", MaxPerSecond: -1}, ErrNegativeLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLogFilter_Validate_Valid(t *testing.T) {
+	valid := []LogFilter{
+		{Type: "job_id", Pattern: "job_*", Level: "debug", OutputLevel: "info"},
+		{Type: "job_id", Pattern: "x"}, // Level defaults to info
+		{Type: "job_id", Pattern: "x", Level: "trace"},
+		{Type: "context:user_id", Pattern: "u*", Level: "WARN"},
+		{Type: "any:job_id", Pattern: "x"},
+		{Type: SourceFilePrefix, Pattern: "internal/*"},
+		{Type: SourceFunctionPrefix, Pattern: "*Handler*"},
+		{Type: SourcePackagePrefix, Pattern: "github.com/me/app/*"},
+		{Type: "job_id", Negate: true}, // Every present value
+		{Type: "job_id", Pattern: "job_[0-9]+", Regex: true},
+		{Type: "ms", Pattern: "100..500", Numeric: true},
+		{Conditions: []Condition{{Type: "job_id", Pattern: "x"}}, Match: ConditionsAny},
+	}
+	for _, f := range valid {
+		if err := f.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", f, err)
+		}
+	}
+}
+
+func TestLogFilter_Validate_ReportsAll(t *testing.T) {
+	f := LogFilter{Type: "context:", Level: "debg", OutputLevel: "loud"}
+	err := f.Validate()
+	for _, want := range []error{ErrUnknownLevel, ErrEmptyContextKey} {
+		if !errors.Is(err, want) {
+			t.Errorf("Expected %v among errors, got %v", want, err)
+		}
+	}
+	if n := strings.Count(err.Error(), "unknown level"); n != 2 {
+		t.Errorf("Expected both levels reported, got %v", err)
+	}
+}
+
+func TestValidateFilters(t *testing.T) {
+	errs := ValidateFilters([]LogFilter{
+		{Type: "job_id", Pattern: "x", Level: "debug"},
+		{Type: "job_id", Pattern: "y", Level: "debg"},
+		{Type: "source:lines", Pattern: "z"},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `filter 1 (type "job_id", pattern "y")`) || !errors.Is(errs[0], ErrUnknownLevel) {
+		t.Errorf("Expected first error to identify filter 1, got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "filter 2") || !errors.Is(errs[1], ErrUnknownSourceType) {
+		t.Errorf("Expected second error to identify filter 2, got %v", errs[1])
+	}
+
+	if errs := ValidateFilters([]LogFilter{{Type: "a", Pattern: "b"}}); errs != nil {
+		t.Errorf("Expected nil for valid filters, got %v", errs)
+	}
+}
+
+func TestHandler_SetFiltersStrict(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), level, WithRejectMatchAll(true))
+
+	current := []LogFilter{{Type: "job_id", Pattern: "keep", Level: "debug", Enabled: true}}
+	if err := handler.SetFiltersStrict(current); err != nil {
+		t.Fatalf("Expected valid filters to be set, got %v", err)
+	}
+
+	err := handler.SetFiltersStrict([]LogFilter{
+		{Type: "job_id", Pattern: "new", Level: "debug", Enabled: true},
+		{Type: "job_id", Pattern: "typo", Level: "debg", Enabled: true},
+	})
+	if !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("Expected ErrUnknownLevel, got %v", err)
+	}
+	if filters := handler.GetFilters(); len(filters) != 1 || filters[0].Pattern != "keep" {
+		t.Errorf("Expected filters unchanged after rejection, got %+v", filters)
+	}
+
+	err = handler.SetFiltersStrict([]LogFilter{{Type: "job_id", Pattern: "*", Level: "debug", Enabled: true}})
+	if !errors.Is(err, ErrUnconfirmedMatchAll) {
+		t.Errorf("Expected ErrUnconfirmedMatchAll, got %v", err)
+	}
+	if filters := handler.GetFilters(); len(filters) != 1 || filters[0].Pattern != "keep" {
+		t.Errorf("Expected filters unchanged after rejection, got %+v", filters)
+	}
+}
+
+func TestSetFiltersStrict_Global(t *testing.T) {
+	_ = New()
+	ClearFilters()
+
+	if err := SetFiltersStrict([]LogFilter{{Type: "context:", Pattern: "x"}}); !errors.Is(err, ErrEmptyContextKey) {
+		t.Errorf("Expected ErrEmptyContextKey, got %v", err)
+	}
+	if err := SetFiltersStrict([]LogFilter{{Type: "x", Pattern: "y", Level: "debug", Enabled: true}}); err != nil {
+		t.Errorf("Expected valid filters to be set, got %v", err)
+	}
+	if n := len(GetFilters()); n != 1 {
+		t.Errorf("Expected 1 filter, got %d", n)
+	}
+}